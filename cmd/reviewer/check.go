@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/app"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/config"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/git"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/llm"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/publish"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/review"
+)
+
+type checkStatus string
+
+const (
+	checkPass checkStatus = "PASS"
+	checkFail checkStatus = "FAIL"
+	checkSkip checkStatus = "SKIP"
+)
+
+type checkOptions struct {
+	Base      string
+	Branch    string
+	Provider  string
+	Guideline string
+}
+
+type checkReport struct {
+	out    io.Writer
+	failed int
+}
+
+func (r *checkReport) add(status checkStatus, name, detail string) {
+	if status == checkFail {
+		r.failed++
+	}
+	line := fmt.Sprintf("[%s] %s", status, name)
+	if detail != "" {
+		line += ": " + detail
+	}
+	fmt.Fprintln(r.out, line)
+}
+
+func (r *checkReport) addErr(name string, err error, okDetail string) {
+	if err != nil {
+		r.add(checkFail, name, err.Error())
+		return
+	}
+	r.add(checkPass, name, okDetail)
+}
+
+// runCheck validates the environment a review depends on and prints a pass/fail checklist.
+// It returns the process exit code.
+func runCheck(out io.Writer, opts checkOptions) int {
+	report := &checkReport{out: out}
+
+	cfg, err := config.Load()
+	report.addErr("config", err, "")
+
+	gitPath, err := exec.LookPath("git")
+	report.addErr("git installed", err, gitPath)
+
+	repoRoot := ""
+	if err == nil {
+		cwd, cwdErr := os.Getwd()
+		if cwdErr != nil {
+			report.add(checkFail, "repository", cwdErr.Error())
+		} else {
+			info, repoErr := git.DetectRepoRoot(cwd)
+			report.addErr("repository", repoErr, info.RootPath)
+			repoRoot = info.RootPath
+		}
+	}
+
+	base := firstNonEmpty(opts.Base, cfg.LastBase)
+	branch := firstNonEmpty(opts.Branch, cfg.LastBranch)
+	for _, ref := range []struct{ name, value string }{{"base branch", base}, {"review branch", branch}} {
+		switch {
+		case repoRoot == "":
+			report.add(checkSkip, ref.name, "no repository")
+		case ref.value == "":
+			report.add(checkFail, ref.name, "not set (use --base/--branch or run the wizard once)")
+		default:
+			report.addErr(ref.name, git.VerifyRef(repoRoot, ref.value), ref.value)
+		}
+	}
+
+	if opts.Provider != "" {
+		cfg.Provider = opts.Provider
+	}
+	provider := llm.ProviderForModel(cfg.Provider, cfg.LastModel)
+	keyCheck := fmt.Sprintf("%s API key", provider)
+	apiKey := strings.TrimSpace(config.ProviderAPIKey(provider))
+	client, err := llm.NewClientFromConfig(cfg, apiKey)
+	switch {
+	case err != nil:
+		report.add(checkFail, keyCheck, err.Error())
+	case apiKey == "":
+		report.add(checkFail, keyCheck, config.ProviderKeyEnv(provider)+" is not set")
+	default:
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		err := client.ValidateKey(ctx)
+		cancel()
+		report.addErr(keyCheck, err, "valid")
+	}
+
+	guidelines := append([]string(nil), cfg.Guidelines...)
+	if opts.Guideline != "" && repoRoot != "" {
+		resolved, err := review.ResolveGuidelinePaths(repoRoot, opts.Guideline)
+		if err != nil {
+			report.addErr("guideline "+opts.Guideline, err, "")
+		}
+		guidelines = resolved
+	}
+	if len(guidelines) == 0 {
+		report.add(checkSkip, "guidelines", "none selected")
+	}
+	for _, path := range guidelines {
+		_, err := review.ReadGuideline(path)
+		report.addErr("guideline "+path, err, "readable")
+	}
+
+	report.checkPublish(cfg)
+
+	if report.failed > 0 {
+		fmt.Fprintf(out, "\n%d check(s) failed.\n", report.failed)
+		return 1
+	}
+	fmt.Fprintln(out, "\nAll checks passed.")
+	return 0
+}
+
+// checkPublish checks the saved publish target of the configured code host: its location
+// settings, its token and that the token can read the pull request.
+func (r *checkReport) checkPublish(cfg config.Config) {
+	target, err := app.SavedPublishTarget(cfg)
+	if err != nil {
+		r.add(checkFail, "publish", err.Error())
+		return
+	}
+	if target.Owner == "" && target.Repo == "" && target.PullRequest == 0 {
+		r.add(checkSkip, "publish", "no "+target.Provider+" target configured")
+		return
+	}
+	publisher, err := app.NewPublisher(cfg, target, nil)
+	if err != nil {
+		r.add(checkFail, "publish", err.Error())
+		return
+	}
+	detail := publish.SummaryKey(target.Provider, target.Owner, target.Repo, target.PullRequest)
+	verifier, ok := publisher.(publish.CredentialVerifier)
+	if !ok {
+		r.add(checkPass, "publish", detail+" (no connection check for this host)")
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	err = verifier.VerifyCredentials(ctx)
+	cancel()
+	r.addErr("publish", err, detail+" reachable")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/config"
+)
+
+func TestCheckPublish_whenGitHubTokenMissing_shouldNameGitHubNotBitbucket(t *testing.T) {
+	// arrange
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("BITBUCKET_TOKEN", "set")
+	var out bytes.Buffer
+	report := &checkReport{out: &out}
+	cfg := config.Config{PublishProvider: "github", PublishWorkspace: "acme", PublishRepoSlug: "repo", PublishPRID: 7}
+
+	// act
+	report.checkPublish(cfg)
+
+	// assert
+	if report.failed != 1 || !strings.Contains(out.String(), "GITHUB_TOKEN") || strings.Contains(out.String(), "BITBUCKET") {
+		t.Fatalf("expected a GitHub token failure, got failed=%d:\n%s", report.failed, out.String())
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"strings"
+)
+
+// globListFlag collects repeatable, comma-separated values (e.g. --exclude vendor --exclude '*.lock,*.sum').
+type globListFlag []string
+
+func (f *globListFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *globListFlag) Set(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*f = append(*f, item)
+		}
+	}
+	return nil
+}
+
+func isFlagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
@@ -0,0 +1,381 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/config"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/git"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/llm"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/publish"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/review"
+)
+
+// exitGateFailed is returned when the review completed but --fail-on rejects its result.
+const exitGateFailed = 3
+
+const (
+	failOnNever   = "never"
+	failOnBlocker = "blocker"
+	failOnIssue   = "issue"
+)
+
+const (
+	sourceBranches    = "branches"
+	sourceWorkingTree = "working-tree"
+	sourceStaged      = "staged"
+	sourcePatch       = "patch"
+	sourceRange       = "range"
+)
+
+type headlessOptions struct {
+	Source       string
+	DiffFile     string
+	Range        string
+	Base         string
+	Branch       string
+	Model        string
+	Provider     string
+	Guideline    string
+	ContextLines int
+	DiffMode     string
+	Fetch        bool
+	Include      []string
+	Exclude      []string
+	NoCache      bool
+	Temperature  float64
+	MinSeverity  string
+	Output       string
+	Format       string
+	FailOn       string
+	DryRun       bool
+}
+
+// runHeadless reviews a diff without the TUI. The summary goes to stdout, progress and errors to
+// stderr. It returns the process exit code: 0 on success (including "nothing to review"), 1 on error,
+// exitGateFailed when --fail-on rejects the result. main reserves 2 for invalid flags.
+func runHeadless(stdout, stderr io.Writer, opts headlessOptions) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	result, err := headlessReview(ctx, stderr, opts)
+	if errors.Is(err, errNothingToReview) {
+		fmt.Fprintln(stderr, "reviewer: no changes to review")
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "reviewer: %v\n", err)
+		return 1
+	}
+
+	printSummary(stdout, result)
+	if opts.Output != "" {
+		format, err := review.ParseReportFormat(opts.Format, opts.Output)
+		if err == nil {
+			err = review.WriteReport(opts.Output, format, result)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "reviewer: write report: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(stderr, "Report written to %s\n", opts.Output)
+	}
+	if opts.DryRun {
+		if err := printPublishPreview(ctx, stdout, stderr, result); err != nil {
+			fmt.Fprintf(stderr, "reviewer: publish preview: %v\n", err)
+			return 1
+		}
+	}
+	if reason, failed := gateFailure(result, opts.FailOn); failed {
+		fmt.Fprintf(stderr, "reviewer: failing (--fail-on %s): %s\n", opts.FailOn, reason)
+		return exitGateFailed
+	}
+	return 0
+}
+
+// printPublishPreview shows what the Publish tab would post for the saved provider and inline
+// setting, without calling any API.
+func printPublishPreview(ctx context.Context, stdout, stderr io.Writer, result review.Result) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	provider, err := publish.ParseProvider(cfg.PublishProvider)
+	if err != nil {
+		return err
+	}
+	root := "."
+	if repo, err := git.DetectRepoRoot(root); err == nil {
+		root = repo.RootPath
+	}
+	tmpl, err := review.LoadRepoSummaryTemplate(root, cfg.PublishTemplate)
+	if err != nil {
+		return err
+	}
+	preview, path, err := publish.PreviewPublish(ctx, result, publish.Options{
+		Inline:   cfg.PublishInline && publish.SupportsInline(provider),
+		Template: tmpl,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "\n--- publish preview (%s, dry run) ---\n%s", provider, preview)
+	fmt.Fprintf(stderr, "Publish preview written to %s\n", path)
+	return nil
+}
+
+func validateFailOn(value string) error {
+	switch value {
+	case "", failOnNever, failOnBlocker, failOnIssue:
+		return nil
+	default:
+		return fmt.Errorf("unknown value %q (want blocker, issue or never)", value)
+	}
+}
+
+// gateFailure reports whether result should fail the run under failOn. blocker fails on files that
+// could not be reviewed, a NO_GO verdict or any blocker; issue additionally fails on any issue.
+func gateFailure(result review.Result, failOn string) (string, bool) {
+	stats := result.Verdict.Stats
+	switch failOn {
+	case failOnBlocker, failOnIssue:
+		if n := len(result.FileErrors); n > 0 {
+			return fmt.Sprintf("%d file(s) not reviewed", n), true
+		}
+		if result.Verdict.Decision == review.DecisionNoGo {
+			return "verdict is NO_GO", true
+		}
+		if stats.Blocker > 0 {
+			return fmt.Sprintf("%d blocker(s) found", stats.Blocker), true
+		}
+		if failOn == failOnIssue && stats.Issue > 0 {
+			return fmt.Sprintf("%d issue(s) found", stats.Issue), true
+		}
+	}
+	return "", false
+}
+
+var errNothingToReview = errors.New("nothing to review")
+
+func headlessReview(ctx context.Context, stderr io.Writer, opts headlessOptions) (review.Result, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return review.Result{}, fmt.Errorf("load config: %w", err)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return review.Result{}, err
+	}
+	repo, err := git.DetectRepoRoot(cwd)
+	if err != nil && opts.Source != sourcePatch {
+		return review.Result{}, err
+	}
+	if err != nil {
+		// A patch needs no repository; guidelines, .reviewignore and templates resolve from cwd.
+		repo = git.RepoInfo{RootPath: cwd}
+	}
+
+	diffOpts := git.DefaultDiffOptions()
+	if cfg.ContextLines != nil {
+		diffOpts.ContextLines = *cfg.ContextLines
+	}
+	if opts.ContextLines >= 0 {
+		diffOpts.ContextLines = opts.ContextLines
+	}
+	diffOpts.Mode, err = git.ParseDiffMode(firstNonEmpty(opts.DiffMode, cfg.DiffMode))
+	if err != nil {
+		return review.Result{}, err
+	}
+
+	fetch := opts.Fetch || cfg.FetchRemote
+	var diff string
+	var fileContent review.FileContentReader
+	switch opts.Source {
+	case sourcePatch:
+		// The patch's revision is unknown, so the reviewer gets no surrounding file context.
+		diff, err = git.ReadPatch(opts.DiffFile)
+	case sourceRange:
+		var revisions git.RevisionRange
+		revisions, err = git.ParseRevisionRange(opts.Range)
+		if err != nil {
+			return review.Result{}, err
+		}
+		if fetch {
+			fetchRemoteRefs(stderr, repo.RootPath, revisions.Base, revisions.Head)
+		}
+		diff, err = git.GenerateRangeDiff(repo.RootPath, revisions.String(), diffOpts)
+		fileContent = func(path string) (string, error) {
+			return git.ShowFile(repo.RootPath, revisions.Head, path)
+		}
+	case sourceStaged:
+		diff, err = git.GenerateStagedDiff(repo.RootPath, diffOpts)
+		fileContent = func(path string) (string, error) {
+			return git.ShowFile(repo.RootPath, "", path)
+		}
+	case sourceWorkingTree:
+		diff, err = git.GenerateWorkingTreeDiff(repo.RootPath, diffOpts)
+		fileContent = func(path string) (string, error) {
+			data, err := os.ReadFile(filepath.Join(repo.RootPath, filepath.FromSlash(path)))
+			return string(data), err
+		}
+	default:
+		base := firstNonEmpty(opts.Base, cfg.LastBase)
+		branch := firstNonEmpty(opts.Branch, cfg.LastBranch)
+		if base == "" || branch == "" {
+			return review.Result{}, errors.New("--base and --branch are required (no previous selection saved)")
+		}
+		if fetch {
+			fetchRemoteRefs(stderr, repo.RootPath, base, branch)
+		}
+		diff, err = git.GenerateDiff(repo.RootPath, base, branch, diffOpts)
+		fileContent = func(path string) (string, error) {
+			return git.ShowFile(repo.RootPath, branch, path)
+		}
+	}
+	if err != nil {
+		return review.Result{}, err
+	}
+	if strings.TrimSpace(diff) == "" {
+		return review.Result{}, errNothingToReview
+	}
+	files, err := git.ParseUnifiedDiff(diff)
+	if err != nil {
+		return review.Result{}, fmt.Errorf("parse diff: %w", err)
+	}
+	include, exclude := cfg.Include, cfg.Exclude
+	if opts.Include != nil {
+		include = opts.Include
+	}
+	if opts.Exclude != nil {
+		exclude = opts.Exclude
+	}
+	kept, err := git.FilterDiffFiles(files, include, exclude)
+	if err != nil {
+		return review.Result{}, err
+	}
+	if hidden := len(files) - len(kept); hidden > 0 {
+		fmt.Fprintf(stderr, "%d file(s) hidden by include/exclude\n", hidden)
+	}
+	ignore, err := review.LoadReviewIgnore(repo.RootPath)
+	if err != nil {
+		return review.Result{}, fmt.Errorf("%s: %w", review.ReviewIgnoreFile, err)
+	}
+	files, ignored := review.SplitIgnored(kept, ignore)
+	if len(ignored) > 0 {
+		fmt.Fprintf(stderr, "%d file(s) skipped by %s\n", len(ignored), review.ReviewIgnoreFile)
+	}
+	if len(files) == 0 {
+		return review.Result{}, errNothingToReview
+	}
+
+	guidelines := cfg.Guidelines
+	if opts.Guideline != "" {
+		guidelines, err = review.ResolveGuidelinePaths(repo.RootPath, opts.Guideline)
+		if err != nil {
+			return review.Result{}, err
+		}
+	}
+
+	if opts.Provider != "" {
+		cfg.Provider = opts.Provider
+	}
+	cfg.LastModel = firstNonEmpty(opts.Model, cfg.LastModel)
+	provider := llm.ProviderForModel(cfg.Provider, cfg.LastModel)
+	apiKey := strings.TrimSpace(config.ProviderAPIKey(provider))
+	if apiKey == "" {
+		return review.Result{}, errors.New("missing " + config.ProviderKeyEnv(provider))
+	}
+
+	policy, err := review.ParseNoGoPolicy(cfg.NoGoPolicy)
+	if err != nil {
+		return review.Result{}, fmt.Errorf("config noGoPolicy: %w", err)
+	}
+	minSeverity, err := review.ParseMinSeverity(firstNonEmpty(opts.MinSeverity, cfg.MinSeverity))
+	if err != nil {
+		return review.Result{}, fmt.Errorf("config minSeverity: %w", err)
+	}
+	prompts, err := review.LoadRepoPromptTemplates(repo.RootPath, cfg.PromptTemplate)
+	if err != nil {
+		return review.Result{}, err
+	}
+
+	temperature := cfg.Temperature
+	if opts.Temperature >= 0 {
+		temperature = &opts.Temperature
+	}
+
+	client, err := llm.NewClientFromConfig(cfg, apiKey)
+	if err != nil {
+		return review.Result{}, err
+	}
+	return review.Run(ctx, client, files, review.RunOptions{
+		Model:                  cfg.LastModel,
+		GuidelinePaths:         guidelines,
+		FreeText:               cfg.FreeGuideline,
+		FileHints:              cfg.FileHints,
+		NoCache:                opts.NoCache,
+		MaxDiffLinesPerRequest: cfg.MaxDiffLinesPerRequest,
+		FileContent:            fileContent,
+		FileContext:            cfg.FileContext,
+		Temperature:            temperature,
+		MaxConcurrency:         cfg.MaxConcurrency,
+		MergeOverlapping:       cfg.MergeOverlapping,
+		NoGoPolicy:             &policy,
+		MinSeverity:            minSeverity,
+		Prompts:                prompts,
+		LanguageFocus:          cfg.LanguageFocus,
+	}, func(progress review.Progress) {
+		status := "ok"
+		if progress.LastError != "" {
+			status = "error: " + progress.LastError
+		}
+		fmt.Fprintf(stderr, "[%d/%d] %s (%s)\n", progress.Completed, progress.Total, progress.CurrentFile, status)
+	})
+}
+
+// fetchRemoteRefs updates the remote refs among refs before they are diffed. A failed fetch is
+// reported but not fatal: the review goes ahead on the local copies.
+func fetchRemoteRefs(stderr io.Writer, repoRoot string, refs ...string) {
+	if err := git.FetchRemoteRefs(repoRoot, refs...); err != nil {
+		fmt.Fprintf(stderr, "reviewer: fetch failed, diffing the local remote refs: %v\n", err)
+	}
+}
+
+func printSummary(out io.Writer, result review.Result) {
+	stats := result.Verdict.Stats
+	fmt.Fprintf(out, "Verdict: %s\n", result.Verdict.Decision)
+	if result.Verdict.Summary != "" {
+		fmt.Fprintf(out, "Summary: %s\n", result.Verdict.Summary)
+	}
+	fmt.Fprintf(out, "Stats: NIT=%d, SUGGESTION=%d, ISSUE=%d, BLOCKER=%d\n", stats.Nit, stats.Suggestion, stats.Issue, stats.Blocker)
+	failed := make([]string, 0, len(result.FileErrors))
+	for path := range result.FileErrors {
+		failed = append(failed, path)
+	}
+	sort.Strings(failed)
+	for _, path := range failed {
+		fmt.Fprintf(out, "Failed: %s: %s\n", path, result.FileErrors[path])
+	}
+
+	comments := append([]review.Comment(nil), result.Comments...)
+	sort.SliceStable(comments, func(i, j int) bool {
+		if comments[i].FilePath != comments[j].FilePath {
+			return comments[i].FilePath < comments[j].FilePath
+		}
+		return comments[i].StartLine < comments[j].StartLine
+	})
+	if len(comments) > 0 {
+		fmt.Fprintln(out)
+	}
+	for _, comment := range comments {
+		fmt.Fprintf(out, "[%s] %s:%d %s\n", comment.Severity, comment.FilePath, comment.StartLine, comment.Title)
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/review"
+)
+
+func TestGateFailure_whenFilesFailedToReview_shouldFailUnlessNever(t *testing.T) {
+	// arrange
+	result := review.Result{
+		Verdict:    review.Verdict{Decision: review.DecisionGo},
+		FileErrors: map[string]string{"a.go": "timeout", "b.go": "context length exceeded"},
+	}
+
+	// act
+	reason, failed := gateFailure(result, failOnBlocker)
+	_, issueFailed := gateFailure(result, failOnIssue)
+	_, neverFailed := gateFailure(result, failOnNever)
+
+	// assert
+	if !failed || reason != "2 file(s) not reviewed" {
+		t.Fatalf("expected the blocker gate to fail on unreviewed files, got %q, %v", reason, failed)
+	}
+	if !issueFailed {
+		t.Fatalf("expected the issue gate to fail on unreviewed files")
+	}
+	if neverFailed {
+		t.Fatalf("expected never to pass regardless of unreviewed files")
+	}
+}
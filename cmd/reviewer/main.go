@@ -5,22 +5,84 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/techitung-arunyawee/code-reviewer-2/internal/app"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/git"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/llm"
 	"github.com/techitung-arunyawee/code-reviewer-2/internal/logger"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/review"
 )
 
 func main() {
-	debug := flag.Bool("debug", false, "Enable debug logging")
+	debug := flag.Bool("debug", false, "Enable debug logging, including LLM responses in llm-responses.log in the cache dir")
 	version := flag.Bool("version", false, "Show version")
 	base := flag.String("base", "", "Base branch")
 	branch := flag.String("branch", "", "Review branch")
 	model := flag.String("model", "", "Model name")
-	guideline := flag.String("guideline", "", "Guideline profile path")
+	provider := flag.String("provider", "", "LLM provider: openrouter (default), openai or anthropic (implied by claude-* models)")
+	guideline := flag.String("guideline", "", "Guideline profile path, or a glob such as docs/guidelines/*.md")
+	check := flag.Bool("check", false, "Run preflight checks and exit")
+	replayLog := flag.String("replay", "", "Re-send the requests recorded in this llm-requests.log (\"default\" for the one in the cache dir), print the responses and exit")
+	contextLines := flag.Int("context", -1, "Diff context lines (default 3, or the saved config value)")
+	diffMode := flag.String("diff-mode", "", "Branch diff range: merge-base (base...branch, default) or direct (base..branch)")
+	fetch := flag.Bool("fetch", false, "Run git fetch before diffing against a remote ref such as origin/main (or set fetchRemote in the config)")
+	var include, exclude globListFlag
+	flag.Var(&include, "include", "Only review paths matching this glob (repeatable, comma-separated)")
+	flag.Var(&exclude, "exclude", "Skip paths matching this glob (repeatable, comma-separated); wins over --include")
+	temperature := flag.Float64("temperature", -1, "Sampling temperature for reviews, 0-2 (default 0.2, or the saved config value)")
+	minSeverity := flag.String("min-severity", "", "Lowest severity to report: NIT (default, or the saved config value), SUGGESTION, ISSUE or BLOCKER")
+	output := flag.String("output", "", "Write the review report to this file (format from --format or the .json/.sarif extension, Markdown otherwise)")
+	format := flag.String("format", "", "Report format for --output: markdown, json or sarif")
+	noCache := flag.Bool("no-cache", false, "Ignore and don't update cached per-file review results")
+	staged := flag.Bool("staged", false, "Review staged changes without the TUI (e.g. from a pre-commit hook)")
+	diffFile := flag.String("diff-file", "", "Review the unified diff in this patch file instead of running git diff (\"-\" reads stdin with --no-tui)")
+	revisionRange := flag.String("range", "", "Review this commit range (A..B or A...B) instead of --base...--branch")
+	commit := flag.String("commit", "", "Review the changes of a single commit (SHA^..SHA)")
+	noTUI := flag.Bool("no-tui", false, "Review --base...--branch without the TUI, print a summary and exit (for CI and scripts)")
+	dryRun := flag.Bool("dry-run", false, "With --no-tui or --staged, print and save the comments publishing would post, without posting")
+	failOn := flag.String("fail-on", failOnNever, "With --no-tui or --staged, exit 3 on: blocker (unreviewed files, NO_GO verdict or any blocker), issue (also any issue) or never")
 	flag.Parse()
 
+	if isFlagSet("context") && *contextLines < 0 {
+		fmt.Fprintln(os.Stderr, "--context must be non-negative")
+		os.Exit(2)
+	}
+	if isFlagSet("temperature") {
+		if err := review.ValidateTemperature(*temperature); err != nil {
+			fmt.Fprintf(os.Stderr, "--temperature: %v\n", err)
+			os.Exit(2)
+		}
+	}
+	if _, err := review.ParseMinSeverity(*minSeverity); err != nil {
+		fmt.Fprintf(os.Stderr, "--min-severity: %v\n", err)
+		os.Exit(2)
+	}
+	if _, err := llm.ParseProvider(*provider); err != nil {
+		fmt.Fprintf(os.Stderr, "--provider: %v\n", err)
+		os.Exit(2)
+	}
+	if _, err := review.ParseReportFormat(*format, *output); err != nil {
+		fmt.Fprintf(os.Stderr, "--format: %v\n", err)
+		os.Exit(2)
+	}
+	if *format != "" && *output == "" {
+		fmt.Fprintln(os.Stderr, "--format requires --output")
+		os.Exit(2)
+	}
+	if _, err := git.ParseDiffMode(*diffMode); err != nil {
+		fmt.Fprintf(os.Stderr, "--diff-mode: %v\n", err)
+		os.Exit(2)
+	}
+	for _, pattern := range append(append([]string(nil), include...), exclude...) {
+		if err := git.ValidateGlob(pattern); err != nil {
+			fmt.Fprintf(os.Stderr, "--include/--exclude: %v\n", err)
+			os.Exit(2)
+		}
+	}
+
 	if *version {
 		fmt.Println("reviewer version v0.1.0")
 		os.Exit(0)
@@ -32,8 +94,117 @@ func main() {
 		os.Exit(1)
 	}
 	defer logFile.Close()
+	llm.EnableResponseLog(*debug)
+
+	if *check {
+		os.Exit(runCheck(os.Stdout, checkOptions{Base: *base, Branch: *branch, Provider: *provider, Guideline: *guideline}))
+	}
+	if *replayLog != "" {
+		os.Exit(runReplay(os.Stdout, os.Stderr, replayOptions{Log: *replayLog, Provider: *provider}))
+	}
+
+	if err := validateFailOn(*failOn); err != nil {
+		fmt.Fprintf(os.Stderr, "--fail-on: %v\n", err)
+		os.Exit(2)
+	}
+	if isFlagSet("fail-on") && !*staged && !*noTUI {
+		fmt.Fprintln(os.Stderr, "--fail-on requires --no-tui or --staged")
+		os.Exit(2)
+	}
+	if *dryRun && !*staged && !*noTUI {
+		fmt.Fprintln(os.Stderr, "--dry-run requires --no-tui or --staged (use d on the Publish tab in the TUI)")
+		os.Exit(2)
+	}
+	if *staged && *noTUI {
+		fmt.Fprintln(os.Stderr, "--staged and --no-tui are mutually exclusive")
+		os.Exit(2)
+	}
+	if *commit != "" {
+		if strings.Contains(*commit, "..") {
+			fmt.Fprintln(os.Stderr, "--commit takes a single commit; use --range for A..B")
+			os.Exit(2)
+		}
+		if *revisionRange != "" {
+			fmt.Fprintln(os.Stderr, "--range and --commit are mutually exclusive")
+			os.Exit(2)
+		}
+		// A single commit is the range form ParseRevisionRange reads as SHA^..SHA.
+		*revisionRange = *commit
+	}
+	if *revisionRange != "" {
+		if _, err := git.ParseRevisionRange(*revisionRange); err != nil {
+			fmt.Fprintf(os.Stderr, "--range: %v\n", err)
+			os.Exit(2)
+		}
+	}
+	sources := 0
+	for _, set := range []bool{*staged, *diffFile != "", *revisionRange != ""} {
+		if set {
+			sources++
+		}
+	}
+	if sources > 1 {
+		fmt.Fprintln(os.Stderr, "--staged, --diff-file and --range/--commit are mutually exclusive")
+		os.Exit(2)
+	}
+	if *diffFile == git.StdinPatch && !*noTUI {
+		// The TUI reads keys from stdin, so the patch can't come from there too.
+		fmt.Fprintln(os.Stderr, "--diff-file - requires --no-tui")
+		os.Exit(2)
+	}
+	if *staged || *noTUI {
+		source := sourceBranches
+		switch {
+		case *staged:
+			source = sourceStaged
+		case *diffFile != "":
+			source = sourcePatch
+		case *revisionRange != "":
+			source = sourceRange
+		}
+		os.Exit(runHeadless(os.Stdout, os.Stderr, headlessOptions{
+			Source:       source,
+			DiffFile:     *diffFile,
+			Range:        *revisionRange,
+			Base:         *base,
+			Branch:       *branch,
+			Model:        *model,
+			Provider:     *provider,
+			Guideline:    *guideline,
+			ContextLines: *contextLines,
+			DiffMode:     *diffMode,
+			Fetch:        *fetch,
+			Include:      include,
+			Exclude:      exclude,
+			NoCache:      *noCache,
+			Temperature:  *temperature,
+			MinSeverity:  *minSeverity,
+			Output:       *output,
+			Format:       *format,
+			FailOn:       *failOn,
+			DryRun:       *dryRun,
+		}))
+	}
 
-	program := tea.NewProgram(app.NewModel(*base, *branch, *model, *guideline), tea.WithAltScreen())
+	program := tea.NewProgram(app.NewModel(app.Options{
+		DiffFile:     *diffFile,
+		Range:        *revisionRange,
+		Base:         *base,
+		Branch:       *branch,
+		Model:        *model,
+		Provider:     *provider,
+		Guideline:    *guideline,
+		ContextLines: *contextLines,
+		DiffMode:     *diffMode,
+		Fetch:        *fetch,
+		Include:      include,
+		Exclude:      exclude,
+		NoCache:      *noCache,
+		Temperature:  *temperature,
+		MinSeverity:  *minSeverity,
+		Output:       *output,
+		Format:       *format,
+	}), tea.WithAltScreen())
 	if _, err := program.Run(); err != nil {
 		log.Fatal(err)
 	}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/config"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/llm"
+)
+
+// defaultReplayLog makes --replay read the request log in the cache dir.
+const defaultReplayLog = "default"
+
+type replayOptions struct {
+	// Log is the request log to replay; defaultReplayLog means llm.RequestLogPath.
+	Log      string
+	Provider string
+}
+
+// runReplay re-sends every request recorded in a request log to the configured provider and prints
+// each response to stdout, so a review's prompts can be re-run without rebuilding them from a diff.
+// Entries recorded against another endpoint are skipped, since their payloads use that provider's
+// wire format. It returns the process exit code: 0 when every request succeeded, 1 otherwise.
+func runReplay(stdout, stderr io.Writer, opts replayOptions) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := replay(ctx, stdout, stderr, opts); err != nil {
+		fmt.Fprintf(stderr, "Replay failed: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func replay(ctx context.Context, stdout, stderr io.Writer, opts replayOptions) error {
+	path := opts.Log
+	if path == defaultReplayLog {
+		logPath, err := llm.RequestLogPath()
+		if err != nil {
+			return err
+		}
+		path = logPath
+	}
+	entries, err := llm.ReadRequestLog(path)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Fprintf(stderr, "No requests recorded in %s.\n", path)
+		return nil
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	cfg.Provider = firstNonEmpty(opts.Provider, cfg.Provider)
+	provider := llm.ProviderForModel(cfg.Provider, cfg.LastModel)
+	apiKey := strings.TrimSpace(config.ProviderAPIKey(provider))
+	if apiKey == "" {
+		return errors.New("missing " + config.ProviderKeyEnv(provider))
+	}
+	client, err := llm.NewClientFromConfig(cfg, apiKey)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for i, entry := range entries {
+		if entry.Endpoint != client.Endpoint() {
+			fmt.Fprintf(stderr, "[%d/%d] skipped: recorded for %s, not %s\n", i+1, len(entries), entry.Endpoint, client.Endpoint())
+			continue
+		}
+		fmt.Fprintf(stderr, "[%d/%d] replaying request recorded %s\n", i+1, len(entries), entry.Timestamp)
+		content, err := client.Replay(ctx, entry.Payload)
+		if err != nil {
+			failed++
+			fmt.Fprintf(stderr, "[%d/%d] failed: %v\n", i+1, len(entries), err)
+			continue
+		}
+		fmt.Fprintf(stdout, "=== Request %d/%d (recorded %s)\n%s\n", i+1, len(entries), entry.Timestamp, content)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d request(s) failed", failed, len(entries))
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/review"
+)
+
+type reviewHistoryMsg struct {
+	previous review.Result
+	found    bool
+	// retry is set when the recorded result came from retrying failed files, whose previous result
+	// is the same run.
+	retry bool
+	err   error
+}
+
+// recordReviewCmd loads the last stored result for the review and stores res in its place.
+func recordReviewCmd(repoRoot, label string, res review.Result, retry bool) tea.Cmd {
+	return func() tea.Msg {
+		previous, found, err := review.LoadPreviousResult(repoRoot, label, res.GuidelineHash)
+		if err != nil {
+			found = false
+		}
+		if saveErr := review.SaveResult(repoRoot, label, res); err == nil {
+			err = saveErr
+		}
+		return reviewHistoryMsg{previous: previous, found: found, retry: retry, err: err}
+	}
+}
+
+// openCompareView shows the current comments against the previous review of the same branch.
+func (m *Model) openCompareView() {
+	if m.previousResult == nil {
+		m.statusMessage = "no earlier review of this branch to compare with"
+		return
+	}
+	m.compareView.Width = max(m.width-4, 20)
+	m.compareView.Height = max(m.height-8, 5)
+	m.compareView.SetContent(renderComparison(review.CompareResults(*m.previousResult, m.reviewResult)))
+	m.compareView.GotoTop()
+	m.compareOpen = true
+}
+
+func (m *Model) updateCompareView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q", "v":
+		m.compareOpen = false
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.compareView, cmd = m.compareView.Update(msg)
+	return m, cmd
+}
+
+func renderComparison(comparison review.Comparison) string {
+	sections := []struct {
+		title    string
+		comments []review.Comment
+		style    lipgloss.Style
+	}{
+		{"New", comparison.New, diffAddStyle},
+		{"Resolved", comparison.Resolved, diffDelStyle},
+		{"Unchanged", comparison.Unchanged, gutterStyle},
+	}
+	var lines []string
+	for _, section := range sections {
+		lines = append(lines, lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("%s (%d)", section.title, len(section.comments))))
+		for _, comment := range section.comments {
+			lines = append(lines, section.style.Render(fmt.Sprintf("  %-10s %s:%d  %s", comment.Severity, comment.FilePath, comment.StartLine, comment.Title)))
+		}
+		lines = append(lines, "")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m Model) renderCompareView() string {
+	title := lipgloss.NewStyle().Bold(true).Padding(1, 0, 0, 0).Render(
+		fmt.Sprintf("Compared with the review of %s", m.previousResult.GeneratedAt.Local().Format("2006-01-02 15:04")))
+	hints := lipgloss.NewStyle().Foreground(theme.Muted).Render("↑/↓ scroll • esc close")
+	return lipgloss.JoinVertical(lipgloss.Left, title, "", m.compareView.View(), hints)
+}
@@ -0,0 +1,67 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/git"
+)
+
+// renderDiffStat summarises files like git diff --stat: one line per file with its changed line
+// count and a +/- bar scaled to width, then the totals.
+func renderDiffStat(files []git.DiffFile, width int) string {
+	nameWidth, countWidth, most := 0, 1, 0
+	for _, file := range files {
+		added, deleted := file.LineCounts()
+		nameWidth = max(nameWidth, len([]rune(file.Path)))
+		countWidth = max(countWidth, len(fmt.Sprint(added+deleted)))
+		most = max(most, added+deleted)
+	}
+	nameWidth = min(nameWidth, max(width/2, 20))
+	barWidth := max(width-nameWidth-countWidth-5, 10)
+
+	lines := make([]string, 0, len(files)+2)
+	totalAdded, totalDeleted := 0, 0
+	for _, file := range files {
+		name := truncateMiddle(file.Path, nameWidth)
+		if file.Binary {
+			lines = append(lines, fmt.Sprintf(" %-*s | %*s", nameWidth, name, countWidth, "Bin"))
+			continue
+		}
+		added, deleted := file.LineCounts()
+		totalAdded += added
+		totalDeleted += deleted
+		plus, minus := added, deleted
+		if most > barWidth {
+			plus, minus = scaleBar(added, most, barWidth), scaleBar(deleted, most, barWidth)
+		}
+		lines = append(lines, fmt.Sprintf(" %-*s | %*d %s%s", nameWidth, name, countWidth, added+deleted,
+			diffAddStyle.Render(strings.Repeat("+", plus)), diffDelStyle.Render(strings.Repeat("-", minus))))
+	}
+	lines = append(lines, "", fmt.Sprintf(" %d file(s) changed, %d insertion(s)(+), %d deletion(s)(-)",
+		len(files), totalAdded, totalDeleted))
+	return strings.Join(lines, "\n")
+}
+
+// scaleBar shrinks count to a bar of at most width characters, keeping any change visible.
+func scaleBar(count, most, width int) int {
+	if count == 0 {
+		return 0
+	}
+	return max(count*width/most, 1)
+}
+
+// truncateMiddle shortens s to width by replacing its middle with "…", so both the top directory
+// and the file name stay visible.
+func truncateMiddle(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	if width <= 1 {
+		return "…"
+	}
+	head := (width - 1) / 2
+	tail := width - 1 - head
+	return string(runes[:head]) + "…" + string(runes[len(runes)-tail:])
+}
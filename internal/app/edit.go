@@ -0,0 +1,234 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/review"
+)
+
+// newCommentIndex is the commentEditIndex of a comment being written from scratch.
+const newCommentIndex = -1
+
+type commentEditField int
+
+const (
+	editFieldFile commentEditField = iota
+	editFieldLine
+	editFieldSeverity
+	editFieldTitle
+	editFieldBody
+)
+
+// startCommentEdit opens the selected comment's title and body for editing in the detail pane.
+func (m *Model) startCommentEdit() tea.Cmd {
+	index, ok := m.selectedCommentIndex()
+	if !ok {
+		return nil
+	}
+	comment := m.reviewResult.Comments[index]
+	m.resetCommentEditor(comment.Title, comment.Body)
+	m.commentEditIndex = index
+	return m.focusCommentEditField(editFieldTitle)
+}
+
+// startNewComment opens an empty form for a comment written by hand on one of the reviewed files.
+func (m *Model) startNewComment() tea.Cmd {
+	if len(m.commentableFiles()) == 0 {
+		m.statusMessage = "no reviewed files to comment on"
+		return nil
+	}
+	m.resetCommentEditor("", "")
+	m.commentEditIndex = newCommentIndex
+	m.commentFileCursor = 0
+	m.commentSeverity = review.SeverityIssue
+	m.commentLineInput = textinput.New()
+	m.commentLineInput.Prompt = "Line: "
+	m.commentLineInput.CharLimit = 7
+	m.commentLineInput.Width = 8
+	return m.focusCommentEditField(editFieldFile)
+}
+
+func (m *Model) resetCommentEditor(title, body string) {
+	m.commentTitleInput = textinput.New()
+	m.commentTitleInput.Prompt = "Title: "
+	m.commentTitleInput.CharLimit = 200
+	m.commentTitleInput.Width = max(m.commentsDetailView.Width-len(m.commentTitleInput.Prompt)-1, 10)
+	m.commentTitleInput.SetValue(title)
+
+	m.commentBodyInput = textarea.New()
+	m.commentBodyInput.ShowLineNumbers = false
+	m.commentBodyInput.CharLimit = 0
+	m.commentBodyInput.SetWidth(max(m.commentsDetailView.Width, 10))
+	m.commentBodyInput.SetHeight(max(m.commentsDetailView.Height-8, 3))
+	m.commentBodyInput.SetValue(body)
+
+	m.commentEditing = true
+	m.commentsPanelFocus = panelFocusRight
+}
+
+// commentableFiles lists the files a manual comment can be placed on: reviewed text files.
+func (m Model) commentableFiles() []string {
+	files, _ := review.SplitIgnored(m.diffFiles, m.reviewIgnore)
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		if !file.Binary {
+			paths = append(paths, file.Path)
+		}
+	}
+	return paths
+}
+
+func (m Model) commentEditFields() []commentEditField {
+	if m.commentEditIndex == newCommentIndex {
+		return []commentEditField{editFieldFile, editFieldLine, editFieldSeverity, editFieldTitle, editFieldBody}
+	}
+	return []commentEditField{editFieldTitle, editFieldBody}
+}
+
+func (m *Model) focusCommentEditField(field commentEditField) tea.Cmd {
+	m.commentEditField = field
+	m.commentLineInput.Blur()
+	m.commentTitleInput.Blur()
+	m.commentBodyInput.Blur()
+	switch field {
+	case editFieldLine:
+		return m.commentLineInput.Focus()
+	case editFieldTitle:
+		return m.commentTitleInput.Focus()
+	case editFieldBody:
+		return m.commentBodyInput.Focus()
+	}
+	return nil
+}
+
+func (m *Model) updateCommentEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.commentEditing = false
+		m.statusMessage = "edit discarded"
+		return m, nil
+	case "ctrl+s":
+		m.saveCommentEdit()
+		return m, nil
+	case "tab", "shift+tab":
+		fields := m.commentEditFields()
+		step := 1
+		if msg.String() == "shift+tab" {
+			step = len(fields) - 1
+		}
+		next := (slices.Index(fields, m.commentEditField) + step) % len(fields)
+		return m, m.focusCommentEditField(fields[next])
+	}
+
+	var cmd tea.Cmd
+	switch m.commentEditField {
+	case editFieldFile:
+		m.commentFileCursor = cycleCursor(m.commentFileCursor, len(m.commentableFiles()), msg.String())
+	case editFieldSeverity:
+		rank := cycleCursor(review.SeverityRank(m.commentSeverity), len(review.Severities), msg.String())
+		m.commentSeverity = review.Severities[rank]
+	case editFieldLine:
+		m.commentLineInput, cmd = m.commentLineInput.Update(msg)
+	case editFieldTitle:
+		m.commentTitleInput, cmd = m.commentTitleInput.Update(msg)
+	case editFieldBody:
+		m.commentBodyInput, cmd = m.commentBodyInput.Update(msg)
+	}
+	return m, cmd
+}
+
+// cycleCursor moves cursor through n choices on up/down or left/right, wrapping around.
+func cycleCursor(cursor, n int, key string) int {
+	if n == 0 {
+		return 0
+	}
+	switch key {
+	case "down", "right", "j", "l":
+		return (cursor + 1) % n
+	case "up", "left", "k", "h":
+		return (cursor - 1 + n) % n
+	}
+	return cursor
+}
+
+// saveCommentEdit writes the edited title and body back into the review result, which is what
+// the Publish tab and exports render. An edited comment keeps its ID, so its report fingerprint and
+// any previously published summary still refer to it; a new comment is appended with
+// StableCommentID and selected for publishing.
+func (m *Model) saveCommentEdit() {
+	title := strings.TrimSpace(m.commentTitleInput.Value())
+	body := strings.TrimSpace(m.commentBodyInput.Value())
+	if title == "" || body == "" {
+		m.statusMessage = "title and body cannot be empty"
+		return
+	}
+
+	if m.commentEditIndex == newCommentIndex {
+		line, err := strconv.Atoi(strings.TrimSpace(m.commentLineInput.Value()))
+		if err != nil || line < 1 {
+			m.statusMessage = "line must be a positive number"
+			return
+		}
+		comment := review.Comment{
+			FilePath:  m.commentableFiles()[m.commentFileCursor],
+			StartLine: line,
+			EndLine:   line,
+			Severity:  m.commentSeverity,
+			Title:     title,
+			Body:      body,
+			Tags:      []string{"manual"},
+			Publish:   true,
+		}
+		comment.ID = review.StableCommentID(comment)
+		m.reviewResult.Comments = append(m.reviewResult.Comments, comment)
+		m.reviewResult.Verdict.Stats = review.ComputeStats(m.reviewResult.Comments)
+		m.statusMessage = "comment added"
+	} else {
+		comment := m.reviewResult.Comments[m.commentEditIndex]
+		comment.Title = title
+		comment.Body = body
+		m.reviewResult.Comments[m.commentEditIndex] = comment
+		m.statusMessage = "comment updated"
+	}
+	m.commentEditing = false
+	m.invalidateCommentRows()
+	m.refreshCommentsTable()
+}
+
+func (m Model) renderCommentEditor() string {
+	lines := make([]string, 0)
+	if m.commentEditIndex == newCommentIndex {
+		cursor := func(field commentEditField) string {
+			if m.commentEditField == field {
+				return "> "
+			}
+			return "  "
+		}
+		lines = append(lines,
+			lipgloss.NewStyle().Bold(true).Render("New comment"),
+			fmt.Sprintf("%sFile: %s", cursor(editFieldFile), m.commentableFiles()[m.commentFileCursor]),
+			cursor(editFieldLine)+m.commentLineInput.View(),
+			fmt.Sprintf("%sSeverity: %s", cursor(editFieldSeverity), m.commentSeverity),
+			"",
+		)
+	}
+	hint := "Tab to switch field, ctrl+s to save, Esc to discard."
+	if m.commentEditIndex == newCommentIndex {
+		hint = "Tab to switch field, ←/→ to pick file and severity, ctrl+s to save, Esc to discard."
+	}
+	lines = append(lines,
+		m.commentTitleInput.View(),
+		"",
+		m.commentBodyInput.View(),
+		lipgloss.NewStyle().Foreground(theme.Muted).Render(hint),
+	)
+	return strings.Join(lines, "\n")
+}
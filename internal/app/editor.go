@@ -0,0 +1,87 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type editorClosedMsg struct {
+	err error
+}
+
+// editorCommand builds the command that opens path at line in editor, the value of $VISUAL or
+// $EDITOR (which may carry its own arguments). Editors that take file:line get that form; the rest
+// get the +line argument vi, emacs and nano understand.
+func editorCommand(editor, path string, line int) (*exec.Cmd, error) {
+	fields := strings.Fields(editor)
+	if len(fields) == 0 {
+		return nil, errors.New("no editor configured; set $VISUAL or $EDITOR")
+	}
+	args := fields[1:]
+	switch filepath.Base(fields[0]) {
+	case "code", "code-insiders", "codium", "cursor":
+		args = append(args, "--goto", fmt.Sprintf("%s:%d", path, line))
+	case "subl", "hx", "zed":
+		args = append(args, fmt.Sprintf("%s:%d", path, line))
+	default:
+		args = append(args, fmt.Sprintf("+%d", line), path)
+	}
+	return exec.Command(fields[0], args...), nil
+}
+
+// openInEditorCmd suspends the TUI while the user's editor has file (relative to repoRoot) open
+// at line.
+func openInEditorCmd(repoRoot, file string, line int) (tea.Cmd, error) {
+	editor := os.Getenv("VISUAL")
+	if strings.TrimSpace(editor) == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	cmd, err := editorCommand(editor, filepath.Join(repoRoot, file), max(line, 1))
+	if err != nil {
+		return nil, err
+	}
+	return tea.ExecProcess(cmd, func(err error) tea.Msg { return editorClosedMsg{err: err} }), nil
+}
+
+// openSelectedInEditor opens the file under the cursor: the selected comment's lines in the
+// Comments tab, or the selected file's first hunk in the Diff tab.
+func (m *Model) openSelectedInEditor() tea.Cmd {
+	var file string
+	var line int
+	switch m.tabs[m.active] {
+	case "Comments":
+		index, ok := m.selectedCommentIndex()
+		if !ok {
+			return nil
+		}
+		comment := m.reviewResult.Comments[index]
+		file, line = comment.FilePath, comment.StartLine
+	case "Diff":
+		if m.diffFile < 0 || m.diffFile >= len(m.diffFiles) {
+			return nil
+		}
+		selected := m.diffFiles[m.diffFile]
+		if selected.Deleted {
+			m.statusErr = fmt.Errorf("%s was deleted", selected.Path)
+			return nil
+		}
+		file = selected.Path
+		if len(selected.Hunks) > 0 {
+			line = selected.Hunks[0].NewStart
+		}
+	default:
+		return nil
+	}
+	cmd, err := openInEditorCmd(m.repoRoot, file, line)
+	if err != nil {
+		m.statusErr = err
+		return nil
+	}
+	return cmd
+}
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/config"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/git"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/review"
+)
+
+type tokenEstimateMsg struct {
+	tokens int
+	err    error
+}
+
+// estimateTokensCmd sizes the review of files off the UI goroutine, since loading the guidelines
+// may read files or fetch URLs.
+func estimateTokensCmd(files []git.DiffFile, cfg config.Config) tea.Cmd {
+	return func() tea.Msg {
+		guidelines, err := review.LoadGuidelines(cfg.Guidelines, cfg.FreeGuideline)
+		return tokenEstimateMsg{tokens: review.EstimateTokens(files, guidelines), err: err}
+	}
+}
+
+// requestReview starts a review unless its estimated size is over the configured threshold, in
+// which case it asks first (see updateReviewConfirm).
+func (m *Model) requestReview() tea.Cmd {
+	threshold := review.TokenWarningThreshold(m.cfg.TokenWarningThreshold)
+	if threshold > 0 && m.tokenEstimate > threshold && !m.reviewRunning && m.reviewResult.GeneratedAt.IsZero() {
+		m.reviewConfirm = true
+		return nil
+	}
+	return m.maybeStartReview()
+}
+
+func (m *Model) updateReviewConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "y", "enter":
+		m.reviewConfirm = false
+		return m, m.maybeStartReview()
+	case "n", "esc":
+		m.reviewConfirm = false
+		m.statusMessage = "review not started; press r in the Config tab to run it"
+	}
+	return m, nil
+}
+
+func (m Model) renderReviewConfirm() string {
+	title := lipgloss.NewStyle().Bold(true).Foreground(theme.Warning).Render("Large review")
+	return lipgloss.JoinVertical(lipgloss.Left,
+		"",
+		title,
+		"",
+		fmt.Sprintf("This review would send about %d tokens of prompts, over the %d-token threshold.",
+			m.tokenEstimate, review.TokenWarningThreshold(m.cfg.TokenWarningThreshold)),
+		"Narrow it with --include/--exclude or .reviewignore, or raise tokenWarningThreshold in the config.",
+		"",
+		"Start the review anyway? (y/n)",
+	)
+}
+
+// describeTokenEstimate is the Config tab line for the current estimate.
+func (m Model) describeTokenEstimate() string {
+	if m.tokenEstimate == 0 {
+		return "Estimated prompt tokens: unknown"
+	}
+	threshold := review.TokenWarningThreshold(m.cfg.TokenWarningThreshold)
+	if threshold == 0 {
+		return fmt.Sprintf("Estimated prompt tokens: ~%d (no confirmation)", m.tokenEstimate)
+	}
+	return fmt.Sprintf("Estimated prompt tokens: ~%d (asks above %d)", m.tokenEstimate, threshold)
+}
@@ -0,0 +1,123 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/config"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/git"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/llm"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/review"
+)
+
+// commentExplanation is one follow-up question about a comment and the model's answer.
+type commentExplanation struct {
+	question string
+	reply    string
+}
+
+type explainReplyMsg struct {
+	commentID   string
+	explanation commentExplanation
+	err         error
+}
+
+// startExplain opens a question prompt for the selected comment in the detail pane.
+func (m *Model) startExplain() tea.Cmd {
+	if _, ok := m.selectedCommentIndex(); !ok {
+		return nil
+	}
+	if m.explainRunning {
+		m.statusMessage = "still waiting for the previous answer"
+		return nil
+	}
+	m.explainInput = textinput.New()
+	m.explainInput.Prompt = "Ask: "
+	m.explainInput.Placeholder = "Why is this a problem?"
+	m.explainInput.CharLimit = 500
+	m.explainInput.Width = max(m.commentsDetailView.Width-len(m.explainInput.Prompt)-1, 10)
+	m.explainAsking = true
+	m.commentsPanelFocus = panelFocusRight
+	return m.explainInput.Focus()
+}
+
+func (m *Model) updateExplainInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.explainAsking = false
+		return m, nil
+	case "enter":
+		question := strings.TrimSpace(m.explainInput.Value())
+		if question == "" {
+			return m, nil
+		}
+		m.explainAsking = false
+		return m, m.askExplain(question)
+	}
+	var cmd tea.Cmd
+	m.explainInput, cmd = m.explainInput.Update(msg)
+	return m, cmd
+}
+
+// askExplain sends question about the selected comment, with its file's diff, as a single turn.
+func (m *Model) askExplain(question string) tea.Cmd {
+	index, ok := m.selectedCommentIndex()
+	if !ok {
+		return nil
+	}
+	comment := m.reviewResult.Comments[index]
+	var file *git.DiffFile
+	for i := range m.diffFiles {
+		if m.diffFiles[i].Path == comment.FilePath {
+			file = &m.diffFiles[i]
+		}
+	}
+	if file == nil {
+		m.statusErr = fmt.Errorf("%s is not in the current diff", comment.FilePath)
+		return nil
+	}
+	apiKey := m.apiKey()
+	if apiKey == "" {
+		m.statusErr = errors.New("missing " + config.ProviderKeyEnv(m.providerName()))
+		return nil
+	}
+	m.explainRunning = true
+	m.statusMessage = "asking the model..."
+	return explainCmd(m.cfg, apiKey, comment, *file, question)
+}
+
+func explainCmd(cfg config.Config, apiKey string, comment review.Comment, file git.DiffFile, question string) tea.Cmd {
+	return func() tea.Msg {
+		client, err := llm.NewClientFromConfig(cfg, apiKey)
+		if err != nil {
+			return explainReplyMsg{commentID: comment.ID, err: err}
+		}
+		reply, err := review.Explain(context.Background(), client, cfg.LastModel, cfg.Temperature, comment, file, question)
+		return explainReplyMsg{
+			commentID:   comment.ID,
+			explanation: commentExplanation{question: question, reply: reply},
+			err:         err,
+		}
+	}
+}
+
+func (m Model) renderExplainInput() string {
+	hint := lipgloss.NewStyle().Foreground(theme.Muted).Render(
+		"Enter to ask about this comment (one follow-up, sent with the file's diff), Esc to cancel.")
+	return strings.Join([]string{m.commentsDetailView.View(), "", m.explainInput.View(), hint}, "\n")
+}
+
+// renderExplanation is the detail pane section for a comment's follow-up, if it has one.
+func (m Model) renderExplanation(comment review.Comment, width int) []string {
+	explanation, ok := m.explanations[comment.ID]
+	if !ok {
+		return nil
+	}
+	return []string{"", "Follow-up:", "Q: " + explanation.question, renderMarkdown(explanation.reply, width)}
+}
@@ -0,0 +1,127 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/git"
+)
+
+const diffHighlightStyle = "monokai"
+
+var (
+	diffAddStyle  = lipgloss.NewStyle().Foreground(darkTheme.Success)
+	diffDelStyle  = lipgloss.NewStyle().Foreground(darkTheme.Error)
+	diffHunkStyle = lipgloss.NewStyle().Foreground(darkTheme.Hunk)
+	gutterStyle   = lipgloss.NewStyle().Foreground(darkTheme.Muted)
+	diffAddTint   = darkTheme.AddTint
+	diffDelTint   = darkTheme.DelTint
+)
+
+// diffHighlighter colors the lines of one file's diff. Tokens are colored by the lexer matching
+// the file name; files without one fall back to plain green/red for added/deleted lines. Lines are
+// tokenised one at a time, so constructs spanning lines (block comments, raw strings) are colored
+// on a best-effort basis.
+type diffHighlighter struct {
+	lexer chroma.Lexer
+	style *chroma.Style
+}
+
+func newDiffHighlighter(path string) diffHighlighter {
+	lexer := lexers.Match(path)
+	if lexer != nil {
+		lexer = chroma.Coalesce(lexer)
+	}
+	return diffHighlighter{lexer: lexer, style: styles.Get(diffHighlightStyle)}
+}
+
+func (h diffHighlighter) formatLine(line git.DiffLine) string {
+	text := strings.ReplaceAll(line.Text, "\t", "    ")
+	switch line.Kind {
+	case git.DiffLineAdd:
+		if h.lexer == nil {
+			return diffAddStyle.Render("+" + text)
+		}
+		return diffAddStyle.Background(diffAddTint).Render("+") + h.tokens(text, diffAddTint)
+	case git.DiffLineDel:
+		if h.lexer == nil {
+			return diffDelStyle.Render("-" + text)
+		}
+		return diffDelStyle.Background(diffDelTint).Render("-") + h.tokens(text, diffDelTint)
+	default:
+		if h.lexer == nil {
+			return " " + text
+		}
+		return " " + h.tokens(text, "")
+	}
+}
+
+// tokens renders text with token colors, on background when set.
+func (h diffHighlighter) tokens(text string, background lipgloss.Color) string {
+	base := lipgloss.NewStyle()
+	if background != "" {
+		base = base.Background(background)
+	}
+	iterator, err := h.lexer.Tokenise(nil, text)
+	if err != nil {
+		return base.Render(text)
+	}
+	var sb strings.Builder
+	for _, token := range iterator.Tokens() {
+		value := strings.TrimRight(token.Value, "\n")
+		if value == "" {
+			continue
+		}
+		style := base
+		if entry := h.style.Get(token.Type); entry.Colour.IsSet() {
+			style = style.Foreground(lipgloss.Color(entry.Colour.String()))
+		}
+		sb.WriteString(style.Render(value))
+	}
+	return sb.String()
+}
+
+// diffGutterWidth is the digit count of the largest line number in file, so the gutter lines up
+// across every hunk.
+func diffGutterWidth(file git.DiffFile) int {
+	largest := 0
+	for _, hunk := range file.Hunks {
+		for _, line := range hunk.Lines {
+			largest = max(largest, line.OldLine, line.NewLine)
+		}
+	}
+	return len(strconv.Itoa(largest))
+}
+
+// formatGutter renders the old- and new-side line numbers of line; the parser leaves the side a
+// line doesn't exist on as 0, which is shown blank.
+func formatGutter(line git.DiffLine, width int) string {
+	number := func(n int) string {
+		if n <= 0 {
+			return strings.Repeat(" ", width)
+		}
+		return fmt.Sprintf("%*d", width, n)
+	}
+	return gutterStyle.Render(number(line.OldLine) + " " + number(line.NewLine) + " │")
+}
+
+// wrapDiffText splits a diff line's text (tabs expanded as formatLine does) into pieces of at most
+// width runes; an empty line stays one piece.
+func wrapDiffText(text string, width int) []string {
+	runes := []rune(strings.ReplaceAll(text, "\t", "    "))
+	if width <= 0 || len(runes) <= width {
+		return []string{string(runes)}
+	}
+	pieces := make([]string, 0, len(runes)/width+1)
+	for len(runes) > width {
+		pieces = append(pieces, string(runes[:width]))
+		runes = runes[width:]
+	}
+	return append(pieces, string(runes))
+}
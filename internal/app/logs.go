@@ -0,0 +1,128 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/llm"
+)
+
+// logsTabEntries is how many of the most recent requests the Logs tab shows.
+const logsTabEntries = 50
+
+type logsLoadedMsg struct {
+	content string
+	err     error
+}
+
+// loadLogsCmd renders the last logsTabEntries requests, newest first, with the status of the
+// latest logged response to each. Statuses only exist while response logging is on (--debug).
+func loadLogsCmd() tea.Cmd {
+	return func() tea.Msg {
+		requestPath, err := llm.RequestLogPath()
+		if err != nil {
+			return logsLoadedMsg{err: err}
+		}
+		requests, err := llm.ReadRequestLog(requestPath)
+		if errors.Is(err, fs.ErrNotExist) {
+			return logsLoadedMsg{content: "No LLM requests logged yet."}
+		}
+		if err != nil {
+			return logsLoadedMsg{err: err}
+		}
+
+		statuses := map[string]string{}
+		if responsePath, err := llm.ResponseLogPath(); err == nil {
+			responses, _ := llm.ReadResponseLog(responsePath)
+			for _, response := range responses {
+				statuses[response.ID] = responseStatus(response)
+			}
+		}
+
+		lines := make([]string, 0, logsTabEntries)
+		for i := len(requests) - 1; i >= 0 && len(lines) < logsTabEntries; i-- {
+			request := requests[i]
+			status, ok := statuses[request.ID]
+			if !ok {
+				status = "-"
+			}
+			lines = append(lines, fmt.Sprintf("%-25s  %-7s  %-40s  %s", request.Timestamp, status, requestFile(request.Payload), request.Endpoint))
+		}
+		return logsLoadedMsg{content: strings.Join(lines, "\n")}
+	}
+}
+
+func responseStatus(response llm.ResponseLogEntry) string {
+	if response.Status == 0 {
+		return "error"
+	}
+	return strconv.Itoa(response.Status)
+}
+
+var diffHeaderPattern = regexp.MustCompile(`(?m)^diff --git a/\S+ b/(\S+)$`)
+
+// requestFile names the file a logged request reviewed, from the diff header in its prompt; verdict
+// requests carry no diff.
+func requestFile(payload json.RawMessage) string {
+	var decoded struct {
+		Messages []struct {
+			Content string `json:"content"`
+		} `json:"messages"`
+	}
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		return "?"
+	}
+	for _, message := range decoded.Messages {
+		if match := diffHeaderPattern.FindStringSubmatch(message.Content); match != nil {
+			return match[1]
+		}
+	}
+	return "(verdict)"
+}
+
+func (m *Model) updateLogsTab(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "right", "l":
+		m.active = (m.active + 1) % len(m.tabs)
+		return m, nil
+	case "left", "h":
+		m.active = (m.active - 1 + len(m.tabs)) % len(m.tabs)
+		return m, nil
+	case "?":
+		m.showHelp = true
+		return m, nil
+	case "r":
+		return m, loadLogsCmd()
+	}
+	var cmd tea.Cmd
+	m.logsView, cmd = m.logsView.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) updateLogsLayout() {
+	m.logsView.Width = max(m.width-4, 20)
+	m.logsView.Height = max(m.height-8, 5)
+}
+
+func (m Model) renderLogsView() string {
+	header := lipgloss.NewStyle().Bold(true).Padding(1, 0, 0, 0).Render(fmt.Sprintf("Recent LLM requests (last %d, newest first)", logsTabEntries))
+	columns := lipgloss.NewStyle().Foreground(theme.Muted).Render(
+		fmt.Sprintf("%-25s  %-7s  %-40s  %s", "Timestamp", "Status", "File", "Endpoint"))
+	body := m.logsView.View()
+	if m.logsErr != nil {
+		body = lipgloss.NewStyle().Foreground(theme.Error).Render(fmt.Sprintf("Error reading logs: %v", m.logsErr))
+	}
+	hints := lipgloss.NewStyle().Foreground(theme.Muted).Render(
+		"↑/↓ scroll • r reload • statuses are logged with --debug")
+	return lipgloss.JoinVertical(lipgloss.Left, header, columns, body, "", hints)
+}
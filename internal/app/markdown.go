@@ -0,0 +1,34 @@
+package app
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/glamour"
+)
+
+// markdownRenderers caches one glamour renderer per wrap width; building one parses a full style
+// sheet, which is too slow to repeat on every cursor move. Bubble Tea calls Update and View from a
+// single goroutine, so the map needs no lock.
+var markdownRenderers = map[int]*glamour.TermRenderer{}
+
+// renderMarkdown renders text for a pane width columns wide, falling back to the raw text when
+// rendering fails.
+func renderMarkdown(text string, width int) string {
+	renderer, ok := markdownRenderers[width]
+	if !ok {
+		var err error
+		renderer, err = glamour.NewTermRenderer(
+			glamour.WithStandardStyle("dark"),
+			glamour.WithWordWrap(max(width, 20)),
+		)
+		if err != nil {
+			return text
+		}
+		markdownRenderers[width] = renderer
+	}
+	rendered, err := renderer.Render(text)
+	if err != nil {
+		return text
+	}
+	return strings.Trim(rendered, "\n")
+}
@@ -1,25 +1,32 @@
 package app
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
 
-	"github.com/techitung-arunyawee/code-reviewer-2/internal/bitbucket"
 	"github.com/techitung-arunyawee/code-reviewer-2/internal/config"
 	"github.com/techitung-arunyawee/code-reviewer-2/internal/git"
 	"github.com/techitung-arunyawee/code-reviewer-2/internal/llm"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/publish"
 	"github.com/techitung-arunyawee/code-reviewer-2/internal/review"
 )
 
@@ -32,18 +39,26 @@ type Model struct {
 	inWizard   bool
 	wizardStep wizardStep
 	repoRoot   string
-	branches   []string
+	// noRepo is set when the working directory is not in a git repository; repoRoot is then the
+	// working directory and only a patch file can be reviewed.
+	noRepo     bool
+	branches   []git.Branch
 	cursor     int
+	diffSource diffSource
 	baseBranch string
 	branch     string
 	err        error
 	cfg        config.Config
 
-	diffText  string
-	diffFiles []git.DiffFile
-	diffErr   error
-	diffFile  int
-	diffView  viewport.Model
+	diffText        string
+	diffFiles       []git.DiffFile
+	diffErr         error
+	diffFile        int
+	diffView        viewport.Model
+	diffStatOpen    bool // the diff pane shows renderDiffStat instead of the selected file
+	diffWrap        bool // long diff lines are soft-wrapped to the pane width
+	diffFilteredOut int
+	reviewIgnore    []string
 
 	guidelineOptions  []string
 	guidelineSelected map[string]bool
@@ -51,6 +66,9 @@ type Model struct {
 	guidelineErr      error
 	guidelineHash     string
 	pathInput         textinput.Model
+	patchInput        textinput.Model
+	rangeInput        textinput.Model
+	sourceInputErr    error
 	freeTextInput     textinput.Model
 	keyInput          textinput.Model
 	modelInput        textinput.Model
@@ -64,51 +82,167 @@ type Model struct {
 	reviewResult   review.Result
 	reviewProgress reviewProgressMsg
 	reviewUpdates  <-chan tea.Msg
+	// reviewStreaming maps files whose responses are still streaming to characters received.
+	reviewStreaming map[string]int
+	// reviewSpinner ticks while reviewRunning; each tick also redraws the elapsed time.
+	reviewSpinner   spinner.Model
+	reviewStartedAt time.Time
+	// tokenEstimate is review.EstimateTokens for the current diff; reviewConfirm is set while the
+	// user is asked whether to start a review over the warning threshold.
+	tokenEstimate int
+	reviewConfirm bool
+	// suggestionPatch is the patch for a comment's suggestion awaiting confirmation; suggestionFile
+	// is the file it changes.
+	suggestionPatch string
+	suggestionFile  string
 
 	commentsTable          table.Model
 	commentsIndexMap       []int
-	commentsFileFilter     textinput.Model
+	commentsTableOffset    int // first row renderCommentsTable draws; see scrollCommentsTable
+	commentsRowCache       []table.Row
+	commentsSearchKeys     []string
+	commentsSearch         textinput.Model
 	commentsFilterActive   bool
 	commentsSeverityFilter review.Severity
+	commentsTagFilter      string
+	commentsOrder          review.CommentOrder
 	commentsTableWidth     int
 	commentsTableHeight    int
 	commentsDetailView     viewport.Model
+	commentEditing         bool
+	commentEditIndex       int
+	commentEditField       commentEditField
+	commentTitleInput      textinput.Model
+	commentBodyInput       textarea.Model
+	commentLineInput       textinput.Model
+	commentFileCursor      int
+	commentSeverity        review.Severity
 	commentsPanelFocus     panelFocus
+	commentsPendingG       bool
 	diffPanelFocus         panelFocus
 
+	// explainAsking is set while a follow-up question is typed into explainInput; explanations
+	// holds the answers by comment ID.
+	explainAsking  bool
+	explainInput   textinput.Model
+	explainRunning bool
+	explanations   map[string]commentExplanation
+
+	// previousResult is the last stored review of the same branch, from before the current one;
+	// compareOpen shows the comments against it.
+	previousResult *review.Result
+	compareOpen    bool
+	compareView    viewport.Model
+
+	// priorPublish holds the Publish toggles from before a re-run ("r"), by comment ID, until the
+	// new result arrives.
+	priorPublish map[string]bool
+
+	// regenerateRunning is set while the selected comment is being redone by the model.
+	regenerateRunning bool
+
+	verdictErr error
+
+	sessions     []reviewSession
+	sessionIndex int
+	sessionErr   error
+
 	publishWorkspaceInput textinput.Model
 	publishRepoSlugInput  textinput.Model
 	publishPRIDInput      textinput.Model
 	publishTokenInput     textinput.Model
-	publishToken          string
 	publishRunning        bool
 	publishError          error
 	publishResultID       string
+	// publishVerifying and publishVerified track the t connection check; publishVerified is its
+	// success line.
+	publishVerifying bool
+	publishVerified  string
+	// publishDryRun makes p render a preview instead of calling the code host.
+	publishDryRun      bool
+	publishPreview     viewport.Model
+	publishPreviewPath string
+
+	logsView viewport.Model
+	logsErr  error
+
+	// gitRemote is origin's parsed URL, used to prefill the Publish tab; nil when unknown.
+	gitRemote *git.Remote
 
 	showHelp bool
 	cancel   context.CancelFunc
 
-	initialBase      string
-	initialBranch    string
-	initialModel     string
-	initialGuideline string
-}
-
-func NewModel(base, branch, model, guideline string) Model {
+	initialDiffFile     string
+	initialRange        string
+	initialBase         string
+	initialBranch       string
+	initialModel        string
+	initialProvider     string
+	initialTemperature  float64
+	initialMinSeverity  string
+	initialGuideline    string
+	initialContextLines int
+	initialDiffMode     string
+	initialFetch        bool
+	initialInclude      []string
+	initialExclude      []string
+
+	// noCache bypasses the on-disk file review cache (--no-cache).
+	noCache bool
+	// outputPath is where exports in outputFormat go (--output, --format).
+	outputPath   string
+	outputFormat review.ReportFormat
+	// statusMessage and statusErr are one-shot status bar notes (exports, clipboard copies),
+	// cleared on the next key press.
+	statusMessage string
+	statusErr     error
+}
+
+// Options carries command-line overrides into the model. Zero values mean "not set",
+// except ContextLines and Temperature where a negative value means "not set".
+type Options struct {
+	// DiffFile and Range preselect reviewing this patch file or commit range in the wizard.
+	DiffFile     string
+	Range        string
+	Base         string
+	Branch       string
+	Model        string
+	Provider     string
+	Guideline    string
+	ContextLines int
+	DiffMode     string
+	Fetch        bool
+	Include      []string
+	Exclude      []string
+	NoCache      bool
+	Temperature  float64
+	// MinSeverity overrides the configured severity floor.
+	MinSeverity string
+	// Output is where exports of the matching format go; empty uses review.DefaultReportName in the repo.
+	Output string
+	// Format overrides the format implied by Output's extension.
+	Format string
+}
+
+func NewModel(opts Options) Model {
 	pathInput := textinput.New()
-	pathInput.Placeholder = "path/to/guideline.md"
+	pathInput.Placeholder = "path/to/guideline.md, docs/guidelines/*.md or an https:// URL"
+	patchInput := textinput.New()
+	patchInput.Placeholder = "path/to/change.patch"
+	rangeInput := textinput.New()
+	rangeInput.Placeholder = "v1.2.0..v1.3.0 or a commit SHA"
 	freeTextInput := textinput.New()
 	freeTextInput.Placeholder = "Free-text guideline (optional)"
 	keyInput := textinput.New()
-	keyInput.Placeholder = "OpenRouter API Key"
+	keyInput.Placeholder = "LLM provider API key"
 	keyInput.EchoMode = textinput.EchoPassword
 	keyInput.EchoCharacter = '*'
 	branchFilterInput := textinput.New()
 	branchFilterInput.Placeholder = "Filter branches"
 	modelInput := textinput.New()
 	modelInput.Placeholder = "Model (e.g. openai/gpt-4o-mini)"
-	commentsFileFilter := textinput.New()
-	commentsFileFilter.Placeholder = "Filter by file path"
+	commentsSearch := textinput.New()
+	commentsSearch.Placeholder = "Search file, title or body"
 
 	publishWorkspaceInput := textinput.New()
 	publishWorkspaceInput.Placeholder = "Bitbucket Workspace (e.g. acme)"
@@ -133,6 +267,8 @@ func NewModel(base, branch, model, guideline string) Model {
 		}),
 		table.WithFocused(true),
 	)
+	// main validates --format, so an error here only means no explicit output was requested.
+	outputFormat, _ := review.ParseReportFormat(opts.Format, opts.Output)
 
 	return Model{
 		tabs: []string{
@@ -140,73 +276,168 @@ func NewModel(base, branch, model, guideline string) Model {
 			"Comments",
 			"Verdict",
 			"Publish",
+			"Logs",
 			"Config",
 		},
-		inWizard:           true,
-		wizardStep:         wizardRepo,
-		pathInput:          pathInput,
-		freeTextInput:      freeTextInput,
-		keyInput:           keyInput,
-		branchFilterInput:  branchFilterInput,
-		modelInput:         modelInput,
-		diffView:           diffView,
-		diffPanelFocus:     panelFocusLeft,
-		commentsFileFilter: commentsFileFilter,
-		commentsTable:      commentsTable,
-		commentsDetailView: commentsDetailView,
-		commentsPanelFocus: panelFocusLeft,
+		inWizard:              true,
+		wizardStep:            wizardRepo,
+		pathInput:             pathInput,
+		patchInput:            patchInput,
+		rangeInput:            rangeInput,
+		freeTextInput:         freeTextInput,
+		keyInput:              keyInput,
+		branchFilterInput:     branchFilterInput,
+		modelInput:            modelInput,
+		diffView:              diffView,
+		diffPanelFocus:        panelFocusLeft,
+		commentsSearch:        commentsSearch,
+		commentsTable:         commentsTable,
+		commentsDetailView:    commentsDetailView,
+		commentsPanelFocus:    panelFocusLeft,
 		publishWorkspaceInput: publishWorkspaceInput,
 		publishRepoSlugInput:  publishRepoSlugInput,
 		publishPRIDInput:      publishPRIDInput,
 		publishTokenInput:     publishTokenInput,
-		initialBase:      base,
-		initialBranch:    branch,
-		initialModel:     model,
-		initialGuideline: guideline,
-		modelOptions: []string{
-			review.DefaultModel,
-			"Custom...",
-		},
+		publishPreview:        viewport.New(0, 0),
+		logsView:              viewport.New(0, 0),
+		initialDiffFile:       opts.DiffFile,
+		initialRange:          opts.Range,
+		initialBase:           opts.Base,
+		initialBranch:         opts.Branch,
+		initialModel:          opts.Model,
+		initialProvider:       opts.Provider,
+		noCache:               opts.NoCache,
+		initialTemperature:    opts.Temperature,
+		initialMinSeverity:    opts.MinSeverity,
+		outputPath:            opts.Output,
+		outputFormat:          outputFormat,
+		initialGuideline:      opts.Guideline,
+		initialContextLines:   opts.ContextLines,
+		initialDiffMode:       opts.DiffMode,
+		initialFetch:          opts.Fetch,
+		initialInclude:        opts.Include,
+		initialExclude:        opts.Exclude,
+		modelOptions:          modelOptionsFor(nil),
+	}
+}
+
+// customModelOption ends the model picker and switches to free-text model entry.
+const customModelOption = "Custom..."
+
+// modelOptionsFor builds the model picker from the configured list, falling back to
+// review.DefaultModel, and always ends it with customModelOption.
+func modelOptionsFor(models []string) []string {
+	options := make([]string, 0, len(models)+1)
+	seen := map[string]bool{customModelOption: true}
+	for _, model := range models {
+		model = strings.TrimSpace(model)
+		if model == "" || seen[model] {
+			continue
+		}
+		seen[model] = true
+		options = append(options, model)
+	}
+	if len(options) == 0 {
+		options = append(options, review.DefaultModel)
 	}
+	return append(options, customModelOption)
 }
 
 func (m Model) Init() tea.Cmd {
 	slog.Info("Starting code-reviewer-2")
-	return tea.Batch(loadConfigCmd(), detectRepoCmd())
+	return tea.Batch(loadConfigCmd(), detectRepoCmd(), loadLogsCmd())
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case configLoadedMsg:
 		m.cfg = msg.cfg
+		selected, err := loadTheme(m.cfg)
+		if err != nil {
+			// Shown once the wizard is done; the default theme is used meanwhile.
+			slog.Warn("Invalid theme config", "error", err)
+			m.statusErr = err
+		}
+		applyTheme(selected)
 		if m.initialBase != "" {
 			m.cfg.LastBase = m.initialBase
 		}
 		if m.initialBranch != "" {
 			m.cfg.LastBranch = m.initialBranch
 		}
+		// The repository may have been detected before the config, with the default order.
+		sortBranches(m.branches, m.cfg.BranchSort == branchSortName)
+		if m.initialDiffFile != "" {
+			m.cfg.LastSource = string(sourcePatch)
+			m.patchInput.SetValue(m.initialDiffFile)
+		}
+		if m.initialRange != "" {
+			m.cfg.LastSource = string(sourceRange)
+			m.rangeInput.SetValue(m.initialRange)
+		}
 		if m.initialModel != "" {
 			m.cfg.LastModel = m.initialModel
 		}
-		m.publishWorkspaceInput.SetValue(msg.cfg.PublishWorkspace)
+		if m.initialProvider != "" {
+			m.cfg.Provider = m.initialProvider
+		}
+		if m.initialContextLines >= 0 {
+			contextLines := m.initialContextLines
+			m.cfg.ContextLines = &contextLines
+		}
+		if m.initialTemperature >= 0 {
+			temperature := m.initialTemperature
+			m.cfg.Temperature = &temperature
+		}
+		if m.initialDiffMode != "" {
+			m.cfg.DiffMode = m.initialDiffMode
+		}
+		if m.initialFetch {
+			m.cfg.FetchRemote = true
+		}
+		if m.initialMinSeverity != "" {
+			m.cfg.MinSeverity = m.initialMinSeverity
+		}
+		if m.initialInclude != nil {
+			m.cfg.Include = m.initialInclude
+		}
+		if m.initialExclude != nil {
+			m.cfg.Exclude = m.initialExclude
+		}
+		m.publishWorkspaceInput.SetValue(*m.publishOwnerSlot())
 		m.publishRepoSlugInput.SetValue(msg.cfg.PublishRepoSlug)
 		if msg.cfg.PublishPRID != 0 {
 			m.publishPRIDInput.SetValue(fmt.Sprintf("%d", msg.cfg.PublishPRID))
 		}
+		m.applyPublishPlaceholders()
+		m.prefillPublishFromRemote()
+		m.modelOptions = modelOptionsFor(m.cfg.Models)
 		return m, nil
 	case configSavedMsg:
 		return m, nil
 	case diffLoadedMsg:
 		m.diffText = msg.raw
 		m.diffFiles = msg.files
+		m.diffFilteredOut = msg.filteredOut
+		m.reviewIgnore = msg.ignore
 		m.diffErr = msg.err
+		if msg.fetchErr != nil {
+			m.statusErr = fmt.Errorf("fetch failed, diffed the local remote refs: %w", msg.fetchErr)
+		}
 		if msg.err == nil {
 			m.diffFile = 0
 			m.updateDiffViewportContent()
 			m.updateDiffViewportLayout()
-			return m, m.maybeStartReview()
+			files, _ := review.SplitIgnored(m.diffFiles, m.reviewIgnore)
+			return m, estimateTokensCmd(files, m.cfg)
 		}
 		return m, nil
+	case tokenEstimateMsg:
+		if msg.err != nil {
+			slog.Warn("Estimated review size without guidelines", "error", msg.err)
+		}
+		m.tokenEstimate = msg.tokens
+		return m, m.requestReview()
 	case guidelinesScannedMsg:
 		m.guidelineOptions = msg.paths
 		m.guidelineErr = msg.err
@@ -214,21 +445,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		selectedGuidelines := m.cfg.Guidelines
 		if m.initialGuideline != "" {
-			resolved, err := review.ResolveGuidelinePath(m.repoRoot, m.initialGuideline)
+			resolved, err := review.ResolveGuidelinePaths(m.repoRoot, m.initialGuideline)
 			if err == nil {
-				selectedGuidelines = []string{resolved}
-				// Ensure the specified guideline is in the options even if not found by scan
-				found := false
-				for _, p := range m.guidelineOptions {
-					if p == resolved {
-						found = true
-						break
+				selectedGuidelines = resolved
+				// Ensure the specified guidelines are in the options even if not found by scan
+				for _, path := range resolved {
+					if !m.hasGuidelineOption(path) {
+						m.guidelineOptions = append(m.guidelineOptions, path)
 					}
 				}
-				if !found {
-					m.guidelineOptions = append(m.guidelineOptions, resolved)
-					sort.Strings(m.guidelineOptions)
-				}
+				sort.Strings(m.guidelineOptions)
 			}
 		}
 
@@ -253,47 +479,181 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.reviewErr = nil
 		m.reviewUpdates = msg.updates
 		m.reviewProgress = reviewProgressMsg{}
+		m.reviewStreaming = make(map[string]int)
 		m.cancel = msg.cancel
-		return m, listenReviewCmd(msg.updates)
+		m.reviewStartedAt = time.Now()
+		m.reviewSpinner = spinner.New(spinner.WithSpinner(spinner.Dot))
+		return m, tea.Batch(listenReviewCmd(msg.updates), m.reviewSpinner.Tick)
+	case spinner.TickMsg:
+		if !m.reviewRunning {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.reviewSpinner, cmd = m.reviewSpinner.Update(msg)
+		return m, cmd
+	case reviewStreamMsg:
+		if m.reviewStreaming != nil {
+			m.reviewStreaming[msg.file] = msg.received
+		}
+		if m.reviewUpdates != nil {
+			return m, listenReviewCmd(m.reviewUpdates)
+		}
+		return m, nil
 	case reviewProgressMsg:
 		m.reviewProgress = msg
+		delete(m.reviewStreaming, msg.file)
 		if m.reviewUpdates != nil {
 			return m, listenReviewCmd(m.reviewUpdates)
 		}
 		return m, nil
+	case reportExportedMsg:
+		if msg.err != nil {
+			m.statusErr = fmt.Errorf("export failed: %w", msg.err)
+		} else {
+			m.statusMessage = "report written to " + msg.path
+		}
+		return m, nil
+	case suggestionPatchMsg:
+		if msg.err != nil {
+			m.statusErr = fmt.Errorf("suggestion: %w", msg.err)
+			return m, nil
+		}
+		m.suggestionPatch = msg.patch
+		m.suggestionFile = msg.file
+		return m, nil
+	case suggestionSavedMsg:
+		switch {
+		case msg.err != nil:
+			m.statusErr = fmt.Errorf("suggestion: %w", msg.err)
+		case msg.applied:
+			m.statusMessage = fmt.Sprintf("suggestion applied to %s", msg.path)
+		default:
+			m.statusMessage = fmt.Sprintf("patch written to %s; apply it with git apply", msg.path)
+		}
+		return m, nil
+	case explainReplyMsg:
+		m.explainRunning = false
+		if msg.err != nil {
+			m.statusErr = fmt.Errorf("follow-up failed: %w", msg.err)
+			return m, nil
+		}
+		if m.explanations == nil {
+			m.explanations = map[string]commentExplanation{}
+		}
+		m.explanations[msg.commentID] = msg.explanation
+		m.statusMessage = "answer added to the comment's details"
+		m.updateCommentsDetailContent(false)
+		return m, nil
+	case editorClosedMsg:
+		if msg.err != nil {
+			m.statusErr = fmt.Errorf("editor: %w", msg.err)
+		}
+		return m, nil
+	case commentRegeneratedMsg:
+		m.regenerateRunning = false
+		switch {
+		case msg.err != nil:
+			m.statusErr = fmt.Errorf("regenerate failed: %w", msg.err)
+		case m.replaceComment(msg.comment):
+			m.statusMessage = "comment regenerated"
+		default:
+			m.statusMessage = "the comment was dismissed before it was regenerated"
+		}
+		return m, nil
+	case commentCopiedMsg:
+		if msg.err != nil {
+			slog.Debug("Clipboard unavailable", "error", msg.err)
+			m.statusMessage = "clipboard unavailable; nothing copied"
+		} else {
+			m.statusMessage = "comment copied to clipboard"
+		}
+		return m, nil
 	case reviewCompletedMsg:
 		m.reviewRunning = false
 		m.reviewUpdates = nil
+		if msg.retry && msg.err != nil {
+			slog.Error("Retry failed", "error", msg.err)
+			m.statusErr = fmt.Errorf("retry failed: %w", msg.err)
+			return m, loadLogsCmd()
+		}
 		m.reviewErr = msg.err
 		if msg.err != nil {
 			slog.Error("Review failed", "error", msg.err)
 		} else {
 			slog.Info("Review completed", "comments", len(msg.result.Comments))
 			m.reviewResult = msg.result
+			review.RestorePublishSelections(m.reviewResult.Comments, m.priorPublish)
+			m.priorPublish = nil
+			m.verdictErr = nil
+			m.commentEditing = false
+			m.invalidateCommentRows()
 			m.refreshCommentsTable()
 			m.updateCommentsTableLayout()
+			label := m.diffSource.describe(m.baseBranch, m.branch)
+			return m, tea.Batch(loadLogsCmd(), recordReviewCmd(m.repoRoot, label, m.reviewResult, msg.retry))
+		}
+		return m, loadLogsCmd()
+	case reviewHistoryMsg:
+		if msg.err != nil {
+			slog.Warn("Review history unavailable", "error", msg.err)
+		}
+		if msg.found && !msg.retry {
+			m.previousResult = &msg.previous
+		}
+		return m, nil
+	case logsLoadedMsg:
+		m.logsErr = msg.err
+		m.logsView.SetContent(msg.content)
+		return m, nil
+	case publishVerifiedMsg:
+		m.publishVerifying = false
+		m.publishError = msg.err
+		m.publishResultID = ""
+		m.publishVerified = ""
+		if msg.err == nil {
+			m.publishVerified = fmt.Sprintf("Connection OK: the token can read this %s pull request.", msg.title)
 		}
 		return m, nil
 	case publishStartedMsg:
 		m.publishRunning = true
 		m.publishError = nil
 		m.publishResultID = ""
+		m.publishVerified = ""
 		m.cancel = msg.cancel
 		return m, nil
+	case publishPreviewMsg:
+		m.publishRunning = false
+		m.publishError = msg.err
+		m.publishResultID = ""
+		m.publishVerified = ""
+		m.publishPreviewPath = msg.path
+		m.publishPreview.SetContent(msg.preview)
+		m.publishPreview.GotoTop()
+		m.updatePublishPreviewLayout()
+		return m, nil
 	case publishCompletedMsg:
 		m.publishRunning = false
 		m.publishError = msg.err
 		m.publishResultID = msg.resultID
+		if errors.Is(msg.err, context.Canceled) {
+			m.publishError = errors.New("publish cancelled")
+		}
 		if msg.err != nil {
 			slog.Error("Publish failed", "error", msg.err)
 		} else {
 			slog.Info("Publish successful", "id", msg.resultID)
 			// Update config with non-secret publish settings
-			m.cfg.PublishWorkspace = m.publishWorkspaceInput.Value()
+			*m.publishOwnerSlot() = m.publishWorkspaceInput.Value()
 			m.cfg.PublishRepoSlug = m.publishRepoSlugInput.Value()
 			var prID int
 			fmt.Sscanf(m.publishPRIDInput.Value(), "%d", &prID)
 			m.cfg.PublishPRID = prID
+			if msg.summaryKey != "" && msg.summaryID != "" {
+				if m.cfg.PublishedComments == nil {
+					m.cfg.PublishedComments = map[string]string{}
+				}
+				m.cfg.PublishedComments[msg.summaryKey] = msg.summaryID
+			}
 			return m, saveConfigCmd(m.cfg)
 		}
 		return m, nil
@@ -303,14 +663,29 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		m.repoRoot = msg.root
+		m.noRepo = msg.noRepo
 		m.branches = msg.branches
+		sortBranches(m.branches, m.cfg.BranchSort == branchSortName)
 		m.err = nil
+		return m, detectRemoteCmd(msg.root)
+	case remoteDetectedMsg:
+		if msg.err != nil {
+			slog.Debug("No usable origin remote", "error", msg.err)
+			return m, nil
+		}
+		m.gitRemote = &msg.remote
+		m.prefillPublishFromRemote()
 		return m, nil
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
 		m.updateDiffViewportLayout()
+		if m.diffWrap {
+			m.updateDiffViewportContent()
+		}
 		m.updateCommentsTableLayout()
+		m.updatePublishPreviewLayout()
+		m.updateLogsLayout()
 		return m, nil
 	case tea.KeyMsg:
 		if m.showHelp {
@@ -320,15 +695,41 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.inWizard {
 			return m.updateWizard(msg)
 		}
+		m.sessionErr = nil
+		m.statusMessage, m.statusErr = "", nil
+		if m.reviewConfirm {
+			return m.updateReviewConfirm(msg)
+		}
+		if m.suggestionPatch != "" {
+			return m.updateSuggestionConfirm(msg)
+		}
+		if m.compareOpen {
+			return m.updateCompareView(msg)
+		}
+		if m.commentEditing && m.tabs[m.active] == "Comments" {
+			return m.updateCommentEdit(msg)
+		}
+		if m.explainAsking && m.tabs[m.active] == "Comments" {
+			return m.updateExplainInput(msg)
+		}
+		if m.updateSessionKeys(msg) {
+			return m, nil
+		}
 		if m.tabs[m.active] == "Diff" {
 			return m.updateDiffTab(msg)
 		}
 		if m.tabs[m.active] == "Comments" {
 			return m.updateCommentsTab(msg)
 		}
+		if m.tabs[m.active] == "Verdict" {
+			return m.updateVerdictTab(msg)
+		}
 		if m.tabs[m.active] == "Publish" {
 			return m.updatePublishTab(msg)
 		}
+		if m.tabs[m.active] == "Logs" {
+			return m.updateLogsTab(msg)
+		}
 		if m.tabs[m.active] == "Config" {
 			return m.updateConfigTab(msg)
 		}
@@ -392,7 +793,7 @@ func (m Model) View() string {
 
 func (m Model) renderTabs() string {
 	activeStyle := lipgloss.NewStyle().Bold(true).Padding(0, 1)
-	inactiveStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Padding(0, 1)
+	inactiveStyle := lipgloss.NewStyle().Foreground(theme.Muted).Padding(0, 1)
 
 	rendered := make([]string, 0, len(m.tabs))
 	for i, tab := range m.tabs {
@@ -410,8 +811,11 @@ type wizardStep int
 
 const (
 	wizardRepo wizardStep = iota
+	wizardSource
+	wizardSourceInput
 	wizardBaseBranch
 	wizardBranch
+	wizardDiffMode
 	wizardModel
 	wizardModelInput
 	wizardGuidelines
@@ -434,7 +838,8 @@ type configLoadedMsg struct {
 
 type repoDetectedMsg struct {
 	root     string
-	branches []string
+	noRepo   bool
+	branches []git.Branch
 	err      error
 }
 
@@ -443,9 +848,13 @@ type configSavedMsg struct {
 }
 
 type diffLoadedMsg struct {
-	raw   string
-	files []git.DiffFile
-	err   error
+	raw         string
+	files       []git.DiffFile
+	filteredOut int
+	ignore      []string
+	err         error
+	// fetchErr is set when fetching remote refs failed and the diff used the local copies.
+	fetchErr error
 }
 
 type guidelinesScannedMsg struct {
@@ -471,18 +880,48 @@ type reviewProgressMsg struct {
 	lastError string
 }
 
+type reportExportedMsg struct {
+	path string
+	err  error
+}
+
+type commentCopiedMsg struct {
+	err error
+}
+
+type reviewStreamMsg struct {
+	file     string
+	received int
+}
+
 type reviewCompletedMsg struct {
 	result review.Result
 	err    error
+	// retry marks a failed-files-only run; its errors leave the prior result in place.
+	retry bool
 }
 
 type publishStartedMsg struct {
 	cancel context.CancelFunc
 }
 
+type remoteDetectedMsg struct {
+	remote git.Remote
+	err    error
+}
+
+type publishPreviewMsg struct {
+	preview string
+	path    string
+	err     error
+}
+
 type publishCompletedMsg struct {
 	resultID string
-	err      error
+	// summaryKey and summaryID remember the summary comment so the next publish updates it.
+	summaryKey string
+	summaryID  string
+	err        error
 }
 
 func loadConfigCmd() tea.Cmd {
@@ -501,15 +940,16 @@ func detectRepoCmd() tea.Cmd {
 
 		repoInfo, err := git.DetectRepoRoot(cwd)
 		if err != nil {
-			return repoDetectedMsg{err: err}
+			// Outside a repository there is nothing to diff, but a patch file can still be reviewed.
+			slog.Debug("No git repository", "path", cwd, "error", err)
+			return repoDetectedMsg{root: cwd, noRepo: true}
 		}
 
-		branches, err := git.ListBranches(repoInfo.RootPath)
+		branches, err := git.ListBranchesByRecency(repoInfo.RootPath)
 		if err != nil {
 			return repoDetectedMsg{err: err}
 		}
 
-		sort.Strings(branches)
 		return repoDetectedMsg{root: repoInfo.RootPath, branches: branches}
 	}
 }
@@ -520,22 +960,48 @@ func saveConfigCmd(cfg config.Config) tea.Cmd {
 	}
 }
 
-func generateDiffCmd(repoRoot, baseBranch, branch string) tea.Cmd {
+func generateDiffCmd(source diffSource, repoRoot, baseBranch, branch string, opts git.DiffOptions, include, exclude []string, fetch bool) tea.Cmd {
 	return func() tea.Msg {
-		diff, err := git.GenerateDiff(repoRoot, baseBranch, branch)
+		var fetchErr error
+		if fetch {
+			// A failed fetch only means the diff may be stale, so it is reported but not fatal.
+			fetchErr = git.FetchRemoteRefs(repoRoot, sourceRefs(source, baseBranch, branch)...)
+		}
+		diff, err := generateSourceDiff(source, repoRoot, baseBranch, branch, opts)
 		if err != nil {
-			return diffLoadedMsg{err: err}
+			return diffLoadedMsg{err: err, fetchErr: fetchErr}
 		}
 
 		files, err := git.ParseUnifiedDiff(diff)
 		if err != nil {
-			return diffLoadedMsg{raw: diff, err: err}
+			return diffLoadedMsg{raw: diff, err: err, fetchErr: fetchErr}
+		}
+
+		kept, err := git.FilterDiffFiles(files, include, exclude)
+		if err != nil {
+			return diffLoadedMsg{raw: diff, err: err, fetchErr: fetchErr}
+		}
+
+		ignore, err := review.LoadReviewIgnore(repoRoot)
+		if err != nil {
+			return diffLoadedMsg{raw: diff, err: fmt.Errorf("%s: %w", review.ReviewIgnoreFile, err), fetchErr: fetchErr}
 		}
 
-		return diffLoadedMsg{raw: diff, files: files}
+		return diffLoadedMsg{raw: diff, files: kept, filteredOut: len(files) - len(kept), ignore: ignore, fetchErr: fetchErr}
 	}
 }
 
+func (m Model) diffOptions() git.DiffOptions {
+	opts := git.DefaultDiffOptions()
+	if m.cfg.ContextLines != nil {
+		opts.ContextLines = *m.cfg.ContextLines
+	}
+	if mode, err := git.ParseDiffMode(m.cfg.DiffMode); err == nil {
+		opts.Mode = mode
+	}
+	return opts
+}
+
 func scanGuidelinesCmd(repoRoot string, extra []string) tea.Cmd {
 	return func() tea.Msg {
 		paths, err := review.ScanGuidelineFiles(repoRoot, extra)
@@ -567,14 +1033,26 @@ func (m Model) updateWizard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch m.wizardStep {
 	case wizardRepo:
 		if msg.String() == "enter" {
-			m.wizardStep = wizardBaseBranch
-			m.cursor = m.initialBranchIndex(m.cfg.LastBase)
-			m.branchFilterInput.SetValue("")
-			m.branchFilterInput.SetCursor(0)
-			m.branchFilterInput.Focus()
-		}
+			m.enterSourceStep()
+		}
+	case wizardSource:
+		return m.updateSourceStep(msg)
+	case wizardSourceInput:
+		return m.updateSourceInputStep(msg)
+	case wizardDiffMode:
+		return m.updateDiffModeStep(msg)
 	case wizardBaseBranch:
 		switch msg.String() {
+		case "esc":
+			if len(m.sessions) > 0 {
+				m.cancelNewSession()
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.branchFilterInput, cmd = m.branchFilterInput.Update(msg)
+			return m, cmd
+		case "ctrl+s":
+			m.toggleBranchSort()
 		case "up", "k":
 			m.cursor = clamp(m.cursor-1, 0, len(m.filteredBranches())-1)
 		case "down", "j":
@@ -584,7 +1062,7 @@ func (m Model) updateWizard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if len(filtered) == 0 {
 				return m, nil
 			}
-			m.baseBranch = filtered[m.cursor]
+			m.baseBranch = filtered[m.cursor].Name
 			m.wizardStep = wizardBranch
 			m.cursor = m.initialBranchIndex(m.cfg.LastBranch)
 			m.branchFilterInput.SetValue("")
@@ -599,6 +1077,8 @@ func (m Model) updateWizard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 	case wizardBranch:
 		switch msg.String() {
+		case "ctrl+s":
+			m.toggleBranchSort()
 		case "up", "k":
 			m.cursor = clamp(m.cursor-1, 0, len(m.filteredBranches())-1)
 		case "down", "j":
@@ -614,10 +1094,8 @@ func (m Model) updateWizard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if len(filtered) == 0 {
 				return m, nil
 			}
-			m.branch = filtered[m.cursor]
-			m.wizardStep = wizardModel
-			m.modelCursor = m.initialModelIndex(m.cfg.LastModel)
-			m.branchFilterInput.Blur()
+			m.branch = filtered[m.cursor].Name
+			m.enterDiffModeStep()
 			return m, nil
 		default:
 			var cmd tea.Cmd
@@ -632,17 +1110,21 @@ func (m Model) updateWizard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		case "down", "j":
 			m.modelCursor = clamp(m.modelCursor+1, 0, len(m.modelOptions)-1)
 		case "b":
-			m.wizardStep = wizardModel
-			m.modelCursor = m.initialModelIndex(m.cfg.LastModel)
-			m.branchFilterInput.SetValue("")
-			m.branchFilterInput.SetCursor(0)
-			m.branchFilterInput.Focus()
+			if m.diffSource.usesInput() {
+				m.enterSourceInputStep()
+				return m, nil
+			}
+			if !m.diffSource.usesBranches() {
+				m.enterSourceStep()
+				return m, nil
+			}
+			m.enterDiffModeStep()
 		case "enter":
 			if len(m.modelOptions) == 0 {
 				return m, nil
 			}
 			selected := m.modelOptions[m.modelCursor]
-			if selected == "Custom..." {
+			if selected == customModelOption {
 				m.wizardStep = wizardModelInput
 				m.modelInput.SetValue(m.cfg.LastModel)
 				m.modelInput.Focus()
@@ -714,23 +1196,27 @@ func (m Model) updateWizard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.wizardStep = wizardGuidelines
 			return m, nil
 		case "enter":
-			resolved, err := review.ResolveGuidelinePath(m.repoRoot, m.pathInput.Value())
+			resolved, err := review.ResolveGuidelinePaths(m.repoRoot, m.pathInput.Value())
 			if err != nil {
 				m.guidelineErr = err
 				return m, nil
 			}
-			if err := validateGuidelinePath(resolved); err != nil {
-				m.guidelineErr = err
-				return m, nil
-			}
-			if !m.hasGuidelineOption(resolved) {
-				m.guidelineOptions = append(m.guidelineOptions, resolved)
-				sort.Strings(m.guidelineOptions)
+			for _, path := range resolved {
+				if err := validateGuidelinePath(path); err != nil {
+					m.guidelineErr = err
+					return m, nil
+				}
 			}
 			if m.guidelineSelected == nil {
 				m.guidelineSelected = make(map[string]bool)
 			}
-			m.guidelineSelected[resolved] = true
+			for _, path := range resolved {
+				if !m.hasGuidelineOption(path) {
+					m.guidelineOptions = append(m.guidelineOptions, path)
+				}
+				m.guidelineSelected[path] = true
+			}
+			sort.Strings(m.guidelineOptions)
 			m.guidelineErr = nil
 			m.wizardStep = wizardGuidelines
 			return m, hashGuidelinesCmd(m.selectedGuidelines(), m.cfg.FreeGuideline)
@@ -751,19 +1237,22 @@ func (m Model) updateWizard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, nil
 		case "enter":
 			m.cfg.FreeGuideline = strings.TrimSpace(m.freeTextInput.Value())
-			m.cfg.LastBase = m.baseBranch
-			m.cfg.LastBranch = m.branch
-			if strings.TrimSpace(config.OpenRouterAPIKey()) == "" && strings.TrimSpace(m.openRouterKey) == "" {
+			if m.diffSource.usesBranches() {
+				m.cfg.LastBase = m.baseBranch
+				m.cfg.LastBranch = m.branch
+			}
+			if strings.TrimSpace(config.ProviderAPIKey(m.providerName())) == "" && strings.TrimSpace(m.openRouterKey) == "" {
 				m.wizardStep = wizardOpenRouterKey
 				m.keyInput.Reset()
 				m.keyInput.Focus()
 				return m, nil
 			}
 			m.inWizard = false
+			m.beginSession()
 			return m, tea.Batch(
 				saveConfigCmd(m.cfg),
 				hashGuidelinesCmd(m.cfg.Guidelines, m.cfg.FreeGuideline),
-				generateDiffCmd(m.repoRoot, m.baseBranch, m.branch),
+				generateDiffCmd(m.diffSource, m.repoRoot, m.baseBranch, m.branch, m.diffOptions(), m.cfg.Include, m.cfg.Exclude, m.cfg.FetchRemote),
 			)
 		default:
 			var cmd tea.Cmd
@@ -785,10 +1274,11 @@ func (m Model) updateWizard(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 			m.inWizard = false
+			m.beginSession()
 			return m, tea.Batch(
 				saveConfigCmd(m.cfg),
 				hashGuidelinesCmd(m.cfg.Guidelines, m.cfg.FreeGuideline),
-				generateDiffCmd(m.repoRoot, m.baseBranch, m.branch),
+				generateDiffCmd(m.diffSource, m.repoRoot, m.baseBranch, m.branch, m.diffOptions(), m.cfg.Include, m.cfg.Exclude, m.cfg.FetchRemote),
 			)
 		default:
 			var cmd tea.Cmd
@@ -809,7 +1299,10 @@ func (m Model) renderWizard() string {
 	switch m.wizardStep {
 	case wizardRepo:
 		repoLine := "Detecting repository..."
-		if m.repoRoot != "" {
+		switch {
+		case m.noRepo:
+			repoLine = fmt.Sprintf("Not a git repository: %s (only a patch file can be reviewed)", m.repoRoot)
+		case m.repoRoot != "":
 			repoLine = fmt.Sprintf("Repository: %s", m.repoRoot)
 		}
 		return lipgloss.JoinVertical(
@@ -819,6 +1312,12 @@ func (m Model) renderWizard() string {
 			"",
 			"Press Enter to continue.",
 		)
+	case wizardSource:
+		return m.renderSourcePicker()
+	case wizardSourceInput:
+		return m.renderSourceInput()
+	case wizardDiffMode:
+		return m.renderDiffModePicker()
 	case wizardBaseBranch:
 		return m.renderBranchPicker("Select base branch", m.baseBranch)
 	case wizardBranch:
@@ -841,7 +1340,16 @@ func (m Model) renderWizard() string {
 }
 
 func (m Model) renderActiveView() string {
-	if m.reviewErr != nil && m.tabs[m.active] != "Config" {
+	if m.reviewConfirm {
+		return m.renderReviewConfirm()
+	}
+	if m.suggestionPatch != "" {
+		return m.renderSuggestionConfirm()
+	}
+	if m.compareOpen {
+		return m.renderCompareView()
+	}
+	if m.reviewErr != nil && m.tabs[m.active] != "Config" && m.tabs[m.active] != "Logs" {
 		return m.renderErrorView(m.reviewErr, "Press r (in Config tab) to re-run review.")
 	}
 
@@ -854,6 +1362,8 @@ func (m Model) renderActiveView() string {
 		return m.renderVerdictView()
 	case "Publish":
 		return m.renderPublishView()
+	case "Logs":
+		return m.renderLogsView()
 	case "Config":
 		return m.renderConfigView()
 	default:
@@ -872,9 +1382,27 @@ func (m *Model) updateConfigTab(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.active = (m.active - 1 + len(m.tabs)) % len(m.tabs)
 		return m, nil
 	case "r":
+		m.priorPublish = review.PublishSelections(m.reviewResult.Comments)
 		m.reviewResult = review.Result{}
-		m.reviewRunning = true
-		return m, m.maybeStartReview()
+		m.reviewProgress = reviewProgressMsg{}
+		return m, m.requestReview()
+	case "R":
+		return m, m.retryFailedFiles()
+	case "f":
+		m.cfg.FileHints = !m.cfg.FileHints
+		return m, saveConfigCmd(m.cfg)
+	case "x":
+		m.cfg.FileContext = !m.cfg.FileContext
+		return m, saveConfigCmd(m.cfg)
+	case "m":
+		m.cfg.MergeOverlapping = !m.cfg.MergeOverlapping
+		return m, saveConfigCmd(m.cfg)
+	case "+", "=":
+		m.cfg.MaxConcurrency = clamp(m.maxConcurrency()+1, 1, maxReviewConcurrency)
+		return m, saveConfigCmd(m.cfg)
+	case "-":
+		m.cfg.MaxConcurrency = clamp(m.maxConcurrency()-1, 1, maxReviewConcurrency)
+		return m, saveConfigCmd(m.cfg)
 	}
 	return m, nil
 }
@@ -887,15 +1415,20 @@ func (m Model) renderPublishView() string {
 		return "\n  No review results to publish. Please run a review first."
 	}
 
-	header := lipgloss.NewStyle().Bold(true).Padding(1, 0).Render("Publish to Bitbucket Cloud")
+	fields := m.publishFields()
+	header := lipgloss.NewStyle().Bold(true).Padding(1, 0).Render("Publish to " + fields.title)
 
 	var statusLine string
 	if m.publishRunning {
-		statusLine = lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Render("Publishing...")
+		statusLine = lipgloss.NewStyle().Foreground(theme.Accent).Render("Publishing...")
 	} else if m.publishError != nil {
-		statusLine = lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render(fmt.Sprintf("Error: %v", m.publishError))
+		statusLine = lipgloss.NewStyle().Foreground(theme.Error).Render(fmt.Sprintf("Error: %v", m.publishError))
 	} else if m.publishResultID != "" {
-		statusLine = lipgloss.NewStyle().Foreground(lipgloss.Color("10")).Render(fmt.Sprintf("Success! Comment ID: %s", m.publishResultID))
+		statusLine = lipgloss.NewStyle().Foreground(theme.Success).Render(fmt.Sprintf("Success! Comment ID: %s", m.publishResultID))
+	} else if m.publishVerifying {
+		statusLine = lipgloss.NewStyle().Foreground(theme.Accent).Render("Checking connection...")
+	} else if m.publishVerified != "" {
+		statusLine = lipgloss.NewStyle().Foreground(theme.Success).Render(m.publishVerified)
 	}
 
 	// Calculate counts
@@ -909,31 +1442,43 @@ func (m Model) renderPublishView() string {
 
 	summary := fmt.Sprintf("Summary: %d comments total, %d selected for publishing.", total, selected)
 	if selected == 0 {
-		summary += lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render(" (Nothing will be published)")
+		summary += lipgloss.NewStyle().Foreground(theme.Error).Render(" (Nothing will be published)")
+	}
+
+	mode := "Mode:      one summary comment"
+	if m.cfg.PublishInline && publish.SupportsInline(m.publishProvider()) {
+		mode = "Mode:      inline comments on diff lines + verdict summary"
+	} else if m.cfg.PublishInline {
+		mode = "Mode:      one summary comment (" + fields.title + " has no inline support yet)"
+	}
+	dryRun := "Dry run:   off"
+	if m.publishDryRun {
+		dryRun = "Dry run:   on (p previews the payload; nothing is posted)"
 	}
 
 	form := lipgloss.JoinVertical(lipgloss.Left,
-		"Workspace:", m.publishWorkspaceInput.View(),
-		"Repo Slug:", m.publishRepoSlugInput.View(),
-		"PR ID:    ", m.publishPRIDInput.View(),
+		fields.ownerLabel, m.publishWorkspaceInput.View(),
+		fields.repoLabel, m.publishRepoSlugInput.View(),
+		fields.prLabel, m.publishPRIDInput.View(),
 		"Token:    ", m.publishTokenInput.View(),
+		"",
+		mode,
+		dryRun,
 	)
 
-	hint := "Tab to cycle, Enter to confirm input, p to Publish to Bitbucket."
+	hint := "Tab to cycle, Enter to confirm input, v to switch provider, i to toggle inline mode, d to toggle dry run, t to test the connection, p to Publish to " + fields.title + "."
 	if m.publishRunning {
 		hint = "Publishing..."
 	}
 
-	return lipgloss.JoinVertical(lipgloss.Left,
-		header,
-		summary,
-		"",
-		form,
-		"",
-		statusLine,
-		"",
-		hint,
-	)
+	sections := []string{header, summary, "", form, "", statusLine, "", hint}
+	if m.publishPreviewPath != "" {
+		sections = append(sections, "",
+			lipgloss.NewStyle().Bold(true).Render("Preview (pgup/pgdn to scroll), saved to "+m.publishPreviewPath),
+			m.publishPreview.View(),
+		)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
 }
 
 func (m Model) renderDiffView() string {
@@ -941,7 +1486,16 @@ func (m Model) renderDiffView() string {
 		return m.renderErrorView(m.diffErr, "Check your branches and try again.")
 	}
 	if len(m.diffFiles) == 0 {
-		return m.renderErrorView(errors.New("no changes detected"), "Make sure you selected the correct branches and have committed your changes.")
+		hint := "Make sure you selected the correct branches and have committed your changes."
+		switch {
+		case m.diffFilteredOut > 0:
+			hint = fmt.Sprintf("All %d changed file(s) were hidden by the include/exclude globs.", m.diffFilteredOut)
+		case m.diffSource == sourceWorkingTree:
+			hint = "There are no uncommitted changes to tracked files."
+		case m.diffSource == sourceStaged:
+			hint = "Nothing is staged; use git add first."
+		}
+		return m.renderErrorView(errors.New("no changes detected"), hint)
 	}
 
 	leftWidth, rightWidth := m.diffPaneWidths()
@@ -949,8 +1503,8 @@ func (m Model) renderDiffView() string {
 	m.diffView.Width = rightWidth
 	m.diffView.Height = height
 
-	focusedStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("62"))
-	unfocusedStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("241"))
+	focusedStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(theme.Accent)
+	unfocusedStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(theme.Muted)
 
 	leftPaneStyle := unfocusedStyle
 	rightPaneStyle := unfocusedStyle
@@ -972,9 +1526,16 @@ func (m Model) renderDiffView() string {
 
 func (m Model) renderFileList(height int) string {
 	visibleCount := height
+	footer := ""
+	if m.diffFilteredOut > 0 {
+		footer = lipgloss.NewStyle().Foreground(theme.Muted).Render(
+			fmt.Sprintf("%d file(s) hidden by include/exclude", m.diffFilteredOut))
+		visibleCount--
+	}
 	if visibleCount < 5 {
 		visibleCount = 5
 	}
+	leftWidth, _ := m.diffPaneWidths()
 	start, end := clampWindow(m.diffFile, len(m.diffFiles), visibleCount)
 	lines := make([]string, 0, end-start)
 	for i := start; i < end; i++ {
@@ -983,12 +1544,50 @@ func (m Model) renderFileList(height int) string {
 		if i == m.diffFile {
 			cursor = "> "
 		}
-		lines = append(lines, cursor+file.Path)
+		added, deleted := file.LineCounts()
+		counts := fmt.Sprintf("+%d/-%d", added, deleted)
+		if file.Binary {
+			counts = ""
+		}
+		ignored := ""
+		if git.MatchAnyGlob(m.reviewIgnore, file.Path) {
+			ignored = " (ignored)"
+		}
+		// Keep the counts on the line by shortening the label's middle; 2 columns go to the border.
+		label := truncateMiddle(diffFileLabel(file), max(leftWidth-2-len(cursor)-len(counts)-len(ignored)-1, 10))
+		if ignored != "" {
+			lines = append(lines, lipgloss.NewStyle().Foreground(theme.Muted).Render(cursor+label+" "+counts+ignored))
+			continue
+		}
+		if counts != "" {
+			counts = diffAddStyle.Render(fmt.Sprintf("+%d", added)) + "/" + diffDelStyle.Render(fmt.Sprintf("-%d", deleted))
+		}
+		lines = append(lines, cursor+label+" "+counts)
+	}
+	if footer != "" {
+		lines = append(lines, footer)
 	}
 
 	return strings.Join(lines, "\n")
 }
 
+func diffFileLabel(file git.DiffFile) string {
+	label := file.Path
+	if file.Renamed && file.OldPath != "" {
+		label = fmt.Sprintf("%s → %s", file.OldPath, file.Path)
+	}
+	if file.Deleted {
+		label += " (deleted)"
+	}
+	if file.Binary {
+		label += " (binary)"
+	}
+	if file.ModeChanged() {
+		label += fmt.Sprintf(" (mode %s → %s)", file.OldMode, file.NewMode)
+	}
+	return label
+}
+
 func (m Model) renderFileDiff() string {
 	if m.diffFile < 0 || m.diffFile >= len(m.diffFiles) {
 		return ""
@@ -996,10 +1595,40 @@ func (m Model) renderFileDiff() string {
 
 	file := m.diffFiles[m.diffFile]
 	lines := make([]string, 0)
+	if file.Renamed {
+		lines = append(lines, fmt.Sprintf("renamed from %s", file.OldPath))
+		if len(file.Hunks) == 0 {
+			lines = append(lines, "(no content changes)")
+		}
+		lines = append(lines, "")
+	}
+	if file.ModeChanged() {
+		lines = append(lines, fmt.Sprintf("mode changed %s → %s", file.OldMode, file.NewMode), "")
+	}
+	if file.Binary {
+		lines = append(lines, "Binary file changed; not shown and not sent for review.")
+	}
+	highlighter := newDiffHighlighter(file.Path)
+	gutterWidth := diffGutterWidth(file)
+	// The text column is what is left of the pane after its border, the gutter and the +/- prefix.
+	_, paneWidth := m.diffPaneWidths()
+	textWidth := paneWidth - 2 - (2*gutterWidth + 3) - 1
 	for _, hunk := range file.Hunks {
-		lines = append(lines, hunk.Header)
+		lines = append(lines, diffHunkStyle.Render(hunk.Header))
 		for _, line := range hunk.Lines {
-			lines = append(lines, formatDiffLine(line))
+			if !m.diffWrap {
+				lines = append(lines, formatGutter(line, gutterWidth)+highlighter.formatLine(line))
+				continue
+			}
+			// Continuation lines keep the +/- prefix under a blank gutter.
+			for i, chunk := range wrapDiffText(line.Text, textWidth) {
+				part := line
+				part.Text = chunk
+				if i > 0 {
+					part.OldLine, part.NewLine = 0, 0
+				}
+				lines = append(lines, formatGutter(part, gutterWidth)+highlighter.formatLine(part))
+			}
 		}
 		lines = append(lines, "")
 	}
@@ -1007,17 +1636,6 @@ func (m Model) renderFileDiff() string {
 	return strings.Join(lines, "\n")
 }
 
-func formatDiffLine(line git.DiffLine) string {
-	switch line.Kind {
-	case git.DiffLineAdd:
-		return "+" + line.Text
-	case git.DiffLineDel:
-		return "-" + line.Text
-	default:
-		return " " + line.Text
-	}
-}
-
 func (m Model) renderBranchPicker(title, selected string) string {
 	header := lipgloss.NewStyle().Bold(true).Render(title)
 	if len(m.branches) == 0 {
@@ -1035,25 +1653,39 @@ func (m Model) renderBranchPicker(title, selected string) string {
 		)
 	}
 
-	visibleCount := m.branchVisibleCount()
+	// Leave room for the Local and Remote section headings.
+	visibleCount := max(m.branchVisibleCount()-2, 3)
 	start, end := clampWindow(m.cursor, len(filtered), visibleCount)
-	lines := make([]string, 0, end-start)
+	sectionStyle := lipgloss.NewStyle().Foreground(theme.Muted)
+	now := time.Now()
+	lines := make([]string, 0, end-start+2)
 	for i := start; i < end; i++ {
 		branch := filtered[i]
+		if i == start || branch.Remote != filtered[i-1].Remote {
+			section := "Local"
+			if branch.Remote {
+				section = "Remote"
+			}
+			lines = append(lines, sectionStyle.Render(section))
+		}
 		cursor := "  "
 		if i == m.cursor {
 			cursor = "> "
 		}
-		label := branch
-		if branch == selected {
-			label = fmt.Sprintf("%s (current)", branch)
+		label := branch.Name
+		if branch.Name == selected {
+			label = fmt.Sprintf("%s (current)", branch.Name)
 		}
-		lines = append(lines, cursor+label)
+		lines = append(lines, fmt.Sprintf("%s%-40s %s", cursor, label, sectionStyle.Render(relativeTime(branch.CommittedAt, now))))
 	}
 
-	hint := "Type to filter, ↑/↓ to move, Enter to select."
+	order := "most recent commit first"
+	if m.cfg.BranchSort == branchSortName {
+		order = "name"
+	}
+	hint := fmt.Sprintf("Type to filter, ↑/↓ to move, ctrl+s to sort (by %s), Enter to select.", order)
 	if m.wizardStep == wizardBranch {
-		hint = "Type to filter, ↑/↓ to move, Enter to select, b to go back."
+		hint = fmt.Sprintf("Type to filter, ↑/↓ to move, ctrl+s to sort (by %s), Enter to select, b to go back.", order)
 	}
 	status := fmt.Sprintf("Showing %d-%d of %d (filtered from %d)", start+1, end, len(filtered), len(m.branches))
 	return lipgloss.JoinVertical(lipgloss.Top, header, "Filter: "+m.branchFilterInput.View(), "", strings.Join(lines, "\n"), "", status, "", hint)
@@ -1064,8 +1696,8 @@ func (m Model) initialBranchIndex(branch string) int {
 		return 0
 	}
 	filtered := m.filteredBranches()
-	for i, name := range filtered {
-		if name == branch {
+	for i, candidate := range filtered {
+		if candidate.Name == branch {
 			return i
 		}
 	}
@@ -1166,16 +1798,35 @@ func (m Model) renderFreeGuidelineInput() string {
 }
 
 func (m Model) renderOpenRouterKeyInput() string {
-	header := lipgloss.NewStyle().Bold(true).Render("OpenRouter API key")
+	header := lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("API key (%s is not set)", config.ProviderKeyEnv(m.providerName())))
 	body := m.keyInput.View()
+	// Keys are deliberately never written to disk; point at the env var instead.
+	tip := lipgloss.NewStyle().Foreground(theme.Muted).Render(fmt.Sprintf(
+		"The key is kept for this session only. Export %s in your shell profile or .envrc to skip this step.",
+		config.ProviderKeyEnv(m.providerName())))
 	hint := "Enter to continue, b to go back."
-	return lipgloss.JoinVertical(lipgloss.Top, header, body, "", hint)
+	return lipgloss.JoinVertical(lipgloss.Top, header, body, "", tip, hint)
 }
 
 func (m Model) renderConfigView() string {
 	lines := []string{
+		fmt.Sprintf("Reviewing: %s", m.diffSource.describe(m.baseBranch, m.branch)),
 		fmt.Sprintf("Base branch: %s", m.baseBranch),
 		fmt.Sprintf("Review branch: %s", m.branch),
+		fmt.Sprintf("Diff context lines: %d", m.diffOptions().ContextLines),
+		m.describeTokenEstimate(),
+	}
+	if m.diffSource.usesBranches() {
+		lines = append(lines, fmt.Sprintf("Diff mode: %s", describeDiffMode(m.diffOptions().Mode)))
+	}
+	if m.cfg.FetchRemote {
+		lines = append(lines, "Fetch remote refs before diffing: on")
+	}
+	if len(m.cfg.Include) > 0 {
+		lines = append(lines, fmt.Sprintf("Include globs: %s", strings.Join(m.cfg.Include, ", ")))
+	}
+	if len(m.cfg.Exclude) > 0 {
+		lines = append(lines, fmt.Sprintf("Exclude globs: %s", strings.Join(m.cfg.Exclude, ", ")))
 	}
 	if m.reviewResult.Model != "" {
 		lines = append(lines, fmt.Sprintf("Model: %s", m.reviewResult.Model))
@@ -1184,6 +1835,13 @@ func (m Model) renderConfigView() string {
 	} else {
 		lines = append(lines, fmt.Sprintf("Model: %s", review.DefaultModel))
 	}
+	lines = append(lines, fmt.Sprintf("Provider: %s", m.providerName()))
+	lines = append(lines, fmt.Sprintf("Parallel file reviews: %d (+/- to adjust)", m.maxConcurrency()))
+	if m.cfg.Temperature != nil {
+		lines = append(lines, fmt.Sprintf("Temperature: %g", *m.cfg.Temperature))
+	} else {
+		lines = append(lines, fmt.Sprintf("Temperature: %g (default)", review.DefaultTemperature))
+	}
 	if m.guidelineHash == "" {
 		lines = append(lines, "Guideline hash: (none)")
 	} else {
@@ -1199,6 +1857,39 @@ func (m Model) renderConfigView() string {
 		}
 	}
 
+	if m.cfg.FileHints {
+		lines = append(lines, "Per-file hints: on (<path>.review.md)")
+	} else {
+		lines = append(lines, "Per-file hints: off")
+	}
+	if m.cfg.FileContext {
+		lines = append(lines, fmt.Sprintf("Surrounding file context: on (±%d lines per hunk)", review.FileContextWindow))
+	} else {
+		lines = append(lines, "Surrounding file context: off")
+	}
+	if m.cfg.PromptTemplate != "" {
+		lines = append(lines, "Prompt template: "+m.cfg.PromptTemplate)
+	} else {
+		lines = append(lines, "Prompt template: built-in")
+	}
+	if policy, err := review.ParseNoGoPolicy(m.cfg.NoGoPolicy); err != nil {
+		lines = append(lines, fmt.Sprintf("NO_GO policy: invalid (%v)", err))
+	} else {
+		lines = append(lines, fmt.Sprintf("NO_GO policy: %s (or when the model says NO_GO)", policy))
+	}
+	if floor, err := review.ParseMinSeverity(m.cfg.MinSeverity); err != nil {
+		lines = append(lines, fmt.Sprintf("Severity floor: invalid (%v)", err))
+	} else if floor == review.SeverityNit {
+		lines = append(lines, "Severity floor: NIT (report everything)")
+	} else {
+		lines = append(lines, fmt.Sprintf("Severity floor: %s (less severe findings are omitted)", floor))
+	}
+	if m.cfg.MergeOverlapping {
+		lines = append(lines, "Merge overlapping comments: on")
+	} else {
+		lines = append(lines, "Merge overlapping comments: off")
+	}
+
 	if m.cfg.FreeGuideline != "" {
 		lines = append(lines, "", "Free-text guideline:", m.cfg.FreeGuideline)
 	}
@@ -1210,6 +1901,14 @@ func (m Model) renderCommentsView() string {
 	if m.reviewRunning {
 		return m.renderReviewStatus("Reviewing comments...")
 	}
+	if m.commentEditing && m.commentEditIndex == newCommentIndex && len(m.commentsIndexMap) == 0 {
+		return m.renderCommentEditor()
+	}
+	if m.reviewResult.GeneratedAt.IsZero() && len(m.diffFiles) > 0 {
+		if files, _ := review.SplitIgnored(m.diffFiles, m.reviewIgnore); len(files) == 0 {
+			return fmt.Sprintf("All changed files match %s; nothing was sent for review.", review.ReviewIgnoreFile)
+		}
+	}
 	if len(m.reviewResult.Comments) == 0 {
 		if m.reviewResult.Dropped > 0 || len(m.reviewResult.FileErrors) > 0 {
 			return lipgloss.JoinVertical(
@@ -1235,8 +1934,8 @@ func (m Model) renderCommentsView() string {
 
 	leftWidth, rightWidth := m.commentsPaneWidths()
 
-	focusedStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("62"))
-	unfocusedStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("241"))
+	focusedStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(theme.Accent)
+	unfocusedStyle := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(theme.Muted)
 
 	leftPaneStyle := unfocusedStyle
 	rightPaneStyle := unfocusedStyle
@@ -1247,8 +1946,14 @@ func (m Model) renderCommentsView() string {
 		rightPaneStyle = focusedStyle
 	}
 
-	tableView := m.commentsTable.View()
+	tableView := m.renderCommentsTable()
 	detailView := m.commentsDetailView.View()
+	if m.commentEditing {
+		detailView = m.renderCommentEditor()
+	}
+	if m.explainAsking {
+		detailView = m.renderExplainInput()
+	}
 	panes := lipgloss.JoinHorizontal(lipgloss.Top,
 		leftPaneStyle.Width(leftWidth).Render(tableView),
 		rightPaneStyle.Width(rightWidth).Render(detailView),
@@ -1257,6 +1962,45 @@ func (m Model) renderCommentsView() string {
 	return lipgloss.JoinVertical(lipgloss.Top, m.renderCommentsWarnings(), m.renderCommentsFilters(), panes, "", m.renderCommentsHints())
 }
 
+func (m *Model) updateVerdictTab(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "right", "l":
+		m.active = (m.active + 1) % len(m.tabs)
+		return m, nil
+	case "left", "h":
+		m.active = (m.active - 1 + len(m.tabs)) % len(m.tabs)
+		return m, nil
+	case "?":
+		m.showHelp = true
+		return m, nil
+	case "esc":
+		if (m.reviewRunning || m.publishRunning) && m.cancel != nil {
+			m.cancel()
+			m.reviewRunning = false
+			m.publishRunning = false
+		}
+		return m, nil
+	case "e":
+		return m, m.exportReportCmd(review.ReportMarkdown)
+	case "J":
+		return m, m.exportReportCmd(review.ReportJSON)
+	case "d":
+		if m.reviewRunning || m.reviewResult.Verdict.Decision == "" {
+			return m, nil
+		}
+		verdict, err := review.OverrideDecision(m.reviewResult.Verdict, !m.cfg.AllowBlockerOverride)
+		m.verdictErr = err
+		if err == nil {
+			slog.Info("Verdict overridden", "decision", verdict.Decision)
+			m.reviewResult.Verdict = verdict
+		}
+		return m, nil
+	}
+	return m, nil
+}
+
 func (m Model) renderVerdictView() string {
 	if m.reviewRunning {
 		return m.renderReviewStatus("Reviewing verdict...")
@@ -1266,8 +2010,12 @@ func (m Model) renderVerdictView() string {
 	}
 
 	verdict := m.reviewResult.Verdict
+	decision := string(verdict.Decision)
+	if verdict.Manual {
+		decision += " (manual override)"
+	}
 	lines := []string{
-		fmt.Sprintf("Decision: %s", verdict.Decision),
+		fmt.Sprintf("Decision: %s", decision),
 		fmt.Sprintf("Summary: %s", verdict.Summary),
 	}
 	if len(verdict.Rationale) > 0 {
@@ -1277,10 +2025,17 @@ func (m Model) renderVerdictView() string {
 		}
 	}
 	lines = append(lines, "", fmt.Sprintf("Stats: NIT=%d, SUGGESTION=%d, ISSUE=%d, BLOCKER=%d", verdict.Stats.Nit, verdict.Stats.Suggestion, verdict.Stats.Issue, verdict.Stats.Blocker))
+	if m.verdictErr != nil {
+		lines = append(lines, "", lipgloss.NewStyle().Foreground(theme.Error).Render(fmt.Sprintf("Error: %v", m.verdictErr)))
+	}
+	lines = append(lines, "", "d to toggle GO/NO_GO manually, e/J to export the report as Markdown/JSON.")
 	return strings.Join(lines, "\n")
 }
 
 func (m Model) renderReviewStatus(heading string) string {
+	if m.reviewRunning && !m.reviewStartedAt.IsZero() {
+		heading = fmt.Sprintf("%s %s %s", m.reviewSpinner.View(), heading, time.Since(m.reviewStartedAt).Round(time.Second))
+	}
 	if m.reviewProgress.total == 0 {
 		return heading
 	}
@@ -1295,6 +2050,18 @@ func (m Model) renderReviewStatus(heading string) string {
 			status = fmt.Sprintf("%s - %s", status, shortenMessage(m.reviewProgress.lastError, m.width-2))
 		}
 	}
+	if len(m.reviewStreaming) > 0 {
+		files := make([]string, 0, len(m.reviewStreaming))
+		for file := range m.reviewStreaming {
+			files = append(files, file)
+		}
+		sort.Strings(files)
+		lines := []string{status, ""}
+		for _, file := range files {
+			lines = append(lines, fmt.Sprintf("streaming… %s (%d chars)", file, m.reviewStreaming[file]))
+		}
+		status = strings.Join(lines, "\n")
+	}
 	return status
 }
 
@@ -1310,6 +2077,12 @@ func (m *Model) updateDiffViewportLayout() {
 }
 
 func (m *Model) updateDiffViewportContent() {
+	if m.diffStatOpen {
+		_, width := m.diffPaneWidths()
+		m.diffView.SetContent(renderDiffStat(m.diffFiles, width-2))
+		m.diffView.SetYOffset(0)
+		return
+	}
 	m.diffView.SetContent(m.renderFileDiff())
 	m.diffView.SetYOffset(0)
 }
@@ -1341,17 +2114,44 @@ func (m *Model) updateCommentsTab(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		case "esc", "enter":
 			m.commentsFilterActive = false
-			m.commentsFileFilter.Blur()
+			m.commentsSearch.Blur()
 			m.commentsTable.Focus()
 			return m, nil
 		default:
+			before := m.commentsSearch.Value()
 			var cmd tea.Cmd
-			m.commentsFileFilter, cmd = m.commentsFileFilter.Update(msg)
-			m.refreshCommentsTable()
+			m.commentsSearch, cmd = m.commentsSearch.Update(msg)
+			if m.commentsSearch.Value() != before {
+				m.refreshCommentsTable()
+			}
 			return m, cmd
 		}
 	}
 
+	// A first "g" waits for a second one (gg); any other key drops it.
+	pendingG := m.commentsPendingG
+	m.commentsPendingG = false
+	switch msg.String() {
+	case "g":
+		if pendingG {
+			m.moveComments(m.commentsTable.GotoTop, func() { m.commentsDetailView.GotoTop() })
+		} else {
+			m.commentsPendingG = true
+		}
+		return m, nil
+	case "G":
+		m.moveComments(m.commentsTable.GotoBottom, func() { m.commentsDetailView.GotoBottom() })
+		return m, nil
+	case "ctrl+d":
+		m.moveComments(func() { m.commentsTable.MoveDown(max(m.commentsTable.Height()/2, 1)) },
+			func() { m.commentsDetailView.HalfViewDown() })
+		return m, nil
+	case "ctrl+u":
+		m.moveComments(func() { m.commentsTable.MoveUp(max(m.commentsTable.Height()/2, 1)) },
+			func() { m.commentsDetailView.HalfViewUp() })
+		return m, nil
+	}
+
 	switch msg.String() {
 	case "ctrl+c", "q":
 		return m, tea.Quit
@@ -1366,16 +2166,38 @@ func (m *Model) updateCommentsTab(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	case "/":
 		m.commentsFilterActive = true
-		m.commentsFileFilter.Focus()
+		m.commentsSearch.Focus()
 		m.commentsTable.Blur()
 		return m, nil
 	case "s":
 		m.cycleSeverityFilter()
 		m.refreshCommentsTable()
 		return m, nil
+	case "a", "n":
+		m.setFilteredCommentsPublish(msg.String() == "a")
+		m.refreshCommentsTable()
+		return m, nil
+	case "P":
+		if m.commentsSeverityFilter == "" {
+			m.statusMessage = "pick a severity with s first; P publishes that severity and above"
+			return m, nil
+		}
+		count := m.publishAtOrAbove(m.commentsSeverityFilter)
+		m.refreshCommentsTable()
+		m.statusMessage = fmt.Sprintf("%d comment(s) at %s or above selected for publishing", count, m.commentsSeverityFilter)
+		return m, nil
+	case "o":
+		m.commentsOrder = m.commentsOrder.Next()
+		m.refreshCommentsTable()
+		return m, nil
+	case "t":
+		m.cycleTagFilter()
+		m.refreshCommentsTable()
+		return m, nil
 	case "c":
 		m.commentsSeverityFilter = ""
-		m.commentsFileFilter.SetValue("")
+		m.commentsTagFilter = ""
+		m.commentsSearch.SetValue("")
 		m.refreshCommentsTable()
 		return m, nil
 	case " ":
@@ -1385,10 +2207,39 @@ func (m *Model) updateCommentsTab(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 	case "r":
+		m.priorPublish = review.PublishSelections(m.reviewResult.Comments)
 		m.reviewResult = review.Result{}
-		m.reviewRunning = true
 		m.reviewProgress = reviewProgressMsg{}
-		return m, m.maybeStartReview()
+		return m, m.requestReview()
+	case "R":
+		return m, m.retryFailedFiles()
+	case "e":
+		return m, m.exportReportCmd(review.ReportMarkdown)
+	case "J":
+		return m, m.exportReportCmd(review.ReportJSON)
+	case "E":
+		return m, m.startCommentEdit()
+	case "+":
+		return m, m.startNewComment()
+	case "x":
+		m.dismissSelectedComment()
+		return m, nil
+	case "y":
+		if index, ok := m.selectedCommentIndex(); ok {
+			return m, copyCommentCmd(m.reviewResult.Comments[index])
+		}
+		return m, nil
+	case "p":
+		return m, m.prepareSuggestionPatch()
+	case "w":
+		return m, m.startExplain()
+	case "ctrl+g":
+		return m, m.regenerateSelectedComment()
+	case "v":
+		m.openCompareView()
+		return m, nil
+	case "ctrl+o":
+		return m, m.openSelectedInEditor()
 	}
 
 	if m.commentsPanelFocus == panelFocusRight {
@@ -1400,6 +2251,7 @@ func (m *Model) updateCommentsTab(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	beforeIndex, _ := m.selectedCommentIndex()
 	var cmd tea.Cmd
 	m.commentsTable, cmd = m.commentsTable.Update(msg)
+	m.scrollCommentsTable()
 	afterIndex, _ := m.selectedCommentIndex()
 	if beforeIndex != afterIndex {
 		m.updateCommentsDetailContent(true)
@@ -1407,14 +2259,23 @@ func (m *Model) updateCommentsTab(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
-func (m *Model) cycleSeverityFilter() {
-	sequence := []review.Severity{
-		"",
-		review.SeverityBlocker,
-		review.SeverityIssue,
-		review.SeveritySuggestion,
-		review.SeverityNit,
+// moveComments runs moveTable on the comments table, or moveDetail on the detail pane when it has
+// focus, and refreshes the detail pane when the selection changed.
+func (m *Model) moveComments(moveTable, moveDetail func()) {
+	if m.commentsPanelFocus == panelFocusRight {
+		moveDetail()
+		return
+	}
+	before, _ := m.selectedCommentIndex()
+	moveTable()
+	m.scrollCommentsTable()
+	if after, _ := m.selectedCommentIndex(); after != before {
+		m.updateCommentsDetailContent(true)
 	}
+}
+
+func (m *Model) cycleSeverityFilter() {
+	sequence := append([]review.Severity{""}, review.Severities...)
 	current := 0
 	for i, value := range sequence {
 		if value == m.commentsSeverityFilter {
@@ -1426,14 +2287,96 @@ func (m *Model) cycleSeverityFilter() {
 	m.commentsSeverityFilter = sequence[next]
 }
 
+// cycleTagFilter steps through "all" and then each tag present in the review, alphabetically.
+func (m *Model) cycleTagFilter() {
+	sequence := append([]string{""}, commentTags(m.reviewResult.Comments)...)
+	current := 0
+	for i, value := range sequence {
+		if value == m.commentsTagFilter {
+			current = i
+			break
+		}
+	}
+	m.commentsTagFilter = sequence[(current+1)%len(sequence)]
+}
+
+// commentTags returns the distinct tags of comments, lowercased and sorted.
+func commentTags(comments []review.Comment) []string {
+	seen := make(map[string]bool)
+	tags := make([]string, 0)
+	for _, comment := range comments {
+		for _, tag := range comment.Tags {
+			tag = strings.ToLower(strings.TrimSpace(tag))
+			if tag != "" && !seen[tag] {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+func hasTag(comment review.Comment, tag string) bool {
+	for _, candidate := range comment.Tags {
+		if strings.EqualFold(strings.TrimSpace(candidate), tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// dismissSelectedComment removes the selected comment from the result, so it is neither shown,
+// counted nor published.
+func (m *Model) dismissSelectedComment() {
+	index, ok := m.selectedCommentIndex()
+	if !ok {
+		return
+	}
+	m.reviewResult.Comments = slices.Delete(slices.Clone(m.reviewResult.Comments), index, index+1)
+	m.reviewResult.Verdict.Stats = review.ComputeStats(m.reviewResult.Comments)
+	m.reviewResult.Dismissed++
+	m.invalidateCommentRows()
+	m.refreshCommentsTable()
+}
+
 func (m *Model) toggleSelectedCommentPublish() {
 	index, ok := m.selectedCommentIndex()
 	if !ok {
 		return
 	}
+	m.setCommentPublish(index, !m.reviewResult.Comments[index].Publish)
+}
+
+// setFilteredCommentsPublish marks every comment passing the current filters for publishing, or
+// clears them all; hidden comments keep their choice.
+func (m *Model) setFilteredCommentsPublish(publish bool) {
+	for _, index := range m.commentsIndexMap {
+		m.setCommentPublish(index, publish)
+	}
+}
+
+// publishAtOrAbove selects every comment at threshold or more severe for publishing and deselects
+// the rest, regardless of the current filters. It returns how many were selected.
+func (m *Model) publishAtOrAbove(threshold review.Severity) int {
+	count := 0
+	for index, comment := range m.reviewResult.Comments {
+		publish := comment.Severity.AtLeast(threshold)
+		if publish {
+			count++
+		}
+		m.setCommentPublish(index, publish)
+	}
+	return count
+}
+
+func (m *Model) setCommentPublish(index int, publish bool) {
 	current := m.reviewResult.Comments[index]
-	current.Publish = !current.Publish
+	current.Publish = publish
 	m.reviewResult.Comments[index] = current
+	if index < len(m.commentsRowCache) {
+		m.commentsRowCache[index] = commentRow(current)
+	}
 }
 
 func (m *Model) refreshCommentsTable() {
@@ -1448,6 +2391,7 @@ func (m *Model) refreshCommentsTable() {
 	if m.commentsTable.Cursor() >= len(rows) {
 		m.commentsTable.SetCursor(len(rows) - 1)
 	}
+	m.scrollCommentsTable()
 	m.updateCommentsDetailContent(true)
 }
 
@@ -1477,8 +2421,39 @@ func (m *Model) updatePublishTab(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.cyclePublishFocus()
 			return m, nil
 		}
+	case "v":
+		if !m.publishWorkspaceInput.Focused() && !m.publishRepoSlugInput.Focused() && !m.publishPRIDInput.Focused() && !m.publishTokenInput.Focused() {
+			m.cyclePublishProvider()
+			return m, saveConfigCmd(m.cfg)
+		}
+	case "d":
+		if !m.publishWorkspaceInput.Focused() && !m.publishRepoSlugInput.Focused() && !m.publishPRIDInput.Focused() && !m.publishTokenInput.Focused() {
+			m.publishDryRun = !m.publishDryRun
+			return m, nil
+		}
+	case "pgup", "pgdown":
+		if m.publishPreviewPath != "" {
+			var cmd tea.Cmd
+			m.publishPreview, cmd = m.publishPreview.Update(msg)
+			return m, cmd
+		}
+	case "t":
+		if !m.publishWorkspaceInput.Focused() && !m.publishRepoSlugInput.Focused() && !m.publishPRIDInput.Focused() && !m.publishTokenInput.Focused() {
+			m.publishVerifying = true
+			m.publishVerified = ""
+			m.publishError = nil
+			return m, m.verifyPublishCmd()
+		}
+	case "i":
+		if !m.publishWorkspaceInput.Focused() && !m.publishRepoSlugInput.Focused() && !m.publishPRIDInput.Focused() && !m.publishTokenInput.Focused() {
+			m.cfg.PublishInline = !m.cfg.PublishInline
+			return m, saveConfigCmd(m.cfg)
+		}
 	case "p":
 		if !m.publishWorkspaceInput.Focused() && !m.publishRepoSlugInput.Focused() && !m.publishPRIDInput.Focused() && !m.publishTokenInput.Focused() {
+			if m.publishDryRun {
+				return m, m.previewPublishCmd()
+			}
 			ctx, cancel := context.WithCancel(context.Background())
 			return m, tea.Batch(
 				func() tea.Msg { return publishStartedMsg{cancel: cancel} },
@@ -1527,7 +2502,7 @@ func (m *Model) cyclePublishFocus() {
 		m.publishPRIDInput.Focus()
 	} else if m.publishPRIDInput.Focused() {
 		m.publishPRIDInput.Blur()
-		if config.BitbucketToken() == "" {
+		if publishEnvToken(m.publishProvider()) == "" {
 			m.publishTokenInput.Focus()
 		} else {
 			m.publishWorkspaceInput.Focus()
@@ -1553,6 +2528,7 @@ func (m *Model) updateCommentsTableLayout() {
 	m.commentsTableHeight = height
 	m.commentsTable.SetWidth(leftWidth - 2)
 	m.commentsTable.SetHeight(height - 2)
+	m.scrollCommentsTable()
 	m.commentsDetailView.Width = rightWidth - 2
 	m.commentsDetailView.Height = height - 2
 	m.updateCommentsDetailContent(false)
@@ -1579,38 +2555,126 @@ func (m *Model) updateCommentsTableLayout() {
 	m.commentsTable.SetColumns(cols)
 }
 
-func (m Model) buildCommentRows() ([]table.Row, []int) {
-	rows := make([]table.Row, 0, len(m.reviewResult.Comments))
-	indices := make([]int, 0, len(m.reviewResult.Comments))
-	fileFilter := strings.ToLower(strings.TrimSpace(m.commentsFileFilter.Value()))
+// buildCommentRows filters and orders the cached per-comment rows. Rows and lowercased paths are built once per
+// review result so filter keystrokes only scan, instead of re-formatting every row (~4 allocs/comment).
+func (m *Model) buildCommentRows() ([]table.Row, []int) {
+	comments := m.reviewResult.Comments
+	if len(m.commentsRowCache) != len(comments) {
+		m.commentsRowCache = make([]table.Row, len(comments))
+		m.commentsSearchKeys = make([]string, len(comments))
+		for i, comment := range comments {
+			m.commentsRowCache[i] = commentRow(comment)
+			m.commentsSearchKeys[i] = strings.ToLower(comment.FilePath + "\n" + comment.Title + "\n" + comment.Body)
+		}
+	}
+
+	rows := make([]table.Row, 0, len(comments))
+	indices := make([]int, 0, len(comments))
+	query := strings.ToLower(strings.TrimSpace(m.commentsSearch.Value()))
 
-	for i, comment := range m.reviewResult.Comments {
+	for i, comment := range comments {
 		if m.commentsSeverityFilter != "" && comment.Severity != m.commentsSeverityFilter {
 			continue
 		}
-		if fileFilter != "" && !strings.Contains(strings.ToLower(comment.FilePath), fileFilter) {
+		if query != "" && !strings.Contains(m.commentsSearchKeys[i], query) {
+			continue
+		}
+		if m.commentsTagFilter != "" && !hasTag(comment, m.commentsTagFilter) {
 			continue
 		}
-		line := fmt.Sprintf("%d", comment.StartLine)
-		if comment.EndLine > comment.StartLine {
-			line = fmt.Sprintf("%d-%d", comment.StartLine, comment.EndLine)
-		}
-		publish := "yes"
-		if !comment.Publish {
-			publish = "no"
-		}
-		rows = append(rows, table.Row{
-			string(comment.Severity),
-			comment.FilePath,
-			line,
-			comment.Title,
-			publish,
-		})
 		indices = append(indices, i)
 	}
+	sort.SliceStable(indices, func(a, b int) bool {
+		return review.CompareComments(comments[indices[a]], comments[indices[b]], m.commentsOrder) < 0
+	})
+	for _, i := range indices {
+		rows = append(rows, m.commentsRowCache[i])
+	}
 	return rows, indices
 }
 
+func (m *Model) invalidateCommentRows() {
+	m.commentsRowCache = nil
+	m.commentsSearchKeys = nil
+}
+
+func commentRow(comment review.Comment) table.Row {
+	line := fmt.Sprintf("%d", comment.StartLine)
+	if comment.EndLine > comment.StartLine {
+		line = fmt.Sprintf("%d-%d", comment.StartLine, comment.EndLine)
+	}
+	publish := "yes"
+	if !comment.Publish {
+		publish = "no"
+	}
+	return table.Row{
+		string(comment.Severity),
+		comment.FilePath,
+		line,
+		comment.Title,
+		publish,
+	}
+}
+
+// renderCommentsTable draws m.commentsTable's columns, rows and cursor. bubbles/table styles every
+// cell alike, so the rows are drawn here to color each Sev cell by its comment's severity; the
+// selected row keeps the table's highlight instead.
+func (m Model) renderCommentsTable() string {
+	styles := table.DefaultStyles()
+	columns := m.commentsTable.Columns()
+	rows := m.commentsTable.Rows()
+	cursor := m.commentsTable.Cursor()
+
+	cells := make([]string, 0, len(columns))
+	for _, column := range columns {
+		cells = append(cells, styles.Header.Render(fitTableCell(column.Title, column.Width)))
+	}
+	header := lipgloss.JoinHorizontal(lipgloss.Top, cells...)
+
+	var lines []string
+	end := min(m.commentsTableOffset+m.commentsTable.Height(), len(rows))
+	for r := m.commentsTableOffset; r < end; r++ {
+		sevStyle := styles.Cell
+		if r != cursor && r < len(m.commentsIndexMap) {
+			if color, ok := theme.severity(m.reviewResult.Comments[m.commentsIndexMap[r]].Severity); ok {
+				sevStyle = sevStyle.Foreground(color)
+			}
+		}
+		cells = cells[:0]
+		for i, value := range rows[r] {
+			style := styles.Cell
+			if i == 0 {
+				style = sevStyle
+			}
+			cells = append(cells, style.Render(fitTableCell(value, columns[i].Width)))
+		}
+		row := lipgloss.JoinHorizontal(lipgloss.Top, cells...)
+		if r == cursor {
+			row = styles.Selected.Render(row)
+		}
+		lines = append(lines, row)
+	}
+	// Pad and clip the rows like the table's viewport, so the pane keeps its size.
+	for len(lines) < m.commentsTable.Height() {
+		lines = append(lines, "")
+	}
+	return header + "\n" + lipgloss.NewStyle().MaxWidth(m.commentsTable.Width()).Render(strings.Join(lines, "\n"))
+}
+
+// fitTableCell pads or truncates value to width, as bubbles/table does.
+func fitTableCell(value string, width int) string {
+	return lipgloss.NewStyle().Width(width).MaxWidth(width).Inline(true).Render(runewidth.Truncate(value, width, "…"))
+}
+
+// scrollCommentsTable keeps the cursor inside the rows renderCommentsTable draws, scrolling as
+// little as possible.
+func (m *Model) scrollCommentsTable() {
+	height := max(m.commentsTable.Height(), 1)
+	cursor := m.commentsTable.Cursor()
+	offset := max(min(m.commentsTableOffset, cursor), cursor-height+1)
+	m.commentsTableOffset = max(min(offset, len(m.commentsTable.Rows())-height), 0)
+}
+
 func (m Model) selectedCommentIndex() (int, bool) {
 	if len(m.commentsIndexMap) == 0 {
 		return 0, false
@@ -1628,6 +2692,7 @@ func (m Model) renderCommentDetailContent(width int) string {
 		return "No comment selected."
 	}
 	comment := m.reviewResult.Comments[index]
+	query := strings.TrimSpace(m.commentsSearch.Value())
 	lineRange := fmt.Sprintf("%d", comment.StartLine)
 	if comment.EndLine > comment.StartLine {
 		lineRange = fmt.Sprintf("%d-%d", comment.StartLine, comment.EndLine)
@@ -1636,20 +2701,30 @@ func (m Model) renderCommentDetailContent(width int) string {
 	if !comment.Publish {
 		publishLabel = "excluded"
 	}
+	// While searching, the body stays plain so matches can be highlighted.
+	body := highlightMatches(comment.Body, query)
+	if query == "" {
+		body = renderMarkdown(comment.Body, width)
+	}
 	lines := []string{
 		fmt.Sprintf("Severity: %s", comment.Severity),
 		fmt.Sprintf("File: %s", comment.FilePath),
 		fmt.Sprintf("Lines: %s", lineRange),
 		fmt.Sprintf("Publish: %s", publishLabel),
+		fmt.Sprintf("Rule: %s", cmp.Or(comment.Rule, "model judgment")),
 		"",
 		"Title:",
-		comment.Title,
+		highlightMatches(comment.Title, query),
 		"",
 		"Body:",
-		comment.Body,
+		body,
 	}
 	if comment.Suggestion != nil && strings.TrimSpace(*comment.Suggestion) != "" {
-		lines = append(lines, "", "Suggestion:", *comment.Suggestion)
+		suggestion := *comment.Suggestion
+		if !strings.Contains(suggestion, "```") {
+			suggestion = "```\n" + suggestion + "\n```"
+		}
+		lines = append(lines, "", "Suggestion:", renderMarkdown(suggestion, width))
 	}
 	if comment.Evidence != nil && strings.TrimSpace(*comment.Evidence) != "" {
 		lines = append(lines, "", "Evidence:", *comment.Evidence)
@@ -1657,6 +2732,7 @@ func (m Model) renderCommentDetailContent(width int) string {
 	if len(comment.Tags) > 0 {
 		lines = append(lines, "", "Tags:", strings.Join(comment.Tags, ", "))
 	}
+	lines = append(lines, m.renderExplanation(comment, width)...)
 	content := strings.Join(lines, "\n")
 	if width <= 0 {
 		return content
@@ -1664,24 +2740,80 @@ func (m Model) renderCommentDetailContent(width int) string {
 	return lipgloss.NewStyle().Width(width).Render(content)
 }
 
+var searchMatchStyle = lipgloss.NewStyle().Reverse(true)
+
+// highlightMatches marks every case-insensitive occurrence of query in text. Text whose lowercase
+// form changes byte length is returned as is, since match offsets would not line up.
+func highlightMatches(text, query string) string {
+	lowerText, lowerQuery := strings.ToLower(text), strings.ToLower(query)
+	if lowerQuery == "" || len(lowerText) != len(text) {
+		return text
+	}
+	var sb strings.Builder
+	for {
+		i := strings.Index(lowerText, lowerQuery)
+		if i < 0 {
+			sb.WriteString(text)
+			return sb.String()
+		}
+		end := i + len(lowerQuery)
+		sb.WriteString(text[:i])
+		sb.WriteString(searchMatchStyle.Render(text[i:end]))
+		text, lowerText = text[end:], lowerText[end:]
+	}
+}
+
+// copyCommentCmd puts a plain-text rendering of comment on the system clipboard. Without a
+// clipboard (e.g. headless Linux with no xclip/xsel) it reports the error instead of failing.
+func copyCommentCmd(comment review.Comment) tea.Cmd {
+	text := commentClipboardText(comment)
+	return func() tea.Msg {
+		return commentCopiedMsg{err: clipboard.WriteAll(text)}
+	}
+}
+
+func commentClipboardText(comment review.Comment) string {
+	location := fmt.Sprintf("%s:%d", comment.FilePath, comment.StartLine)
+	if comment.EndLine > comment.StartLine {
+		location = fmt.Sprintf("%s-%d", location, comment.EndLine)
+	}
+	parts := []string{
+		fmt.Sprintf("[%s] %s", comment.Severity, comment.Title),
+		location,
+		"",
+		comment.Body,
+	}
+	if comment.Suggestion != nil && strings.TrimSpace(*comment.Suggestion) != "" {
+		parts = append(parts, "", "Suggestion:", *comment.Suggestion)
+	}
+	return strings.Join(parts, "\n")
+}
+
 func (m Model) renderCommentsFilters() string {
 	severity := "ALL"
 	if m.commentsSeverityFilter != "" {
 		severity = string(m.commentsSeverityFilter)
 	}
-	fileValue := strings.TrimSpace(m.commentsFileFilter.Value())
+	searchValue := strings.TrimSpace(m.commentsSearch.Value())
 	if m.commentsFilterActive {
-		fileValue = m.commentsFileFilter.View()
-	} else if fileValue == "" {
-		fileValue = "(none)"
+		searchValue = m.commentsSearch.View()
+	} else if searchValue == "" {
+		searchValue = "(none)"
+	}
+	tag := "ALL"
+	if m.commentsTagFilter != "" {
+		tag = m.commentsTagFilter
 	}
-	return fmt.Sprintf("Severity: %s | File: %s", severity, fileValue)
+	return fmt.Sprintf("Severity: %s | Tag: %s | Search: %s | Sort: %s", severity, tag, searchValue, m.commentsOrder)
 }
 
 func (m Model) renderCommentsWarnings() string {
 	warnings := make([]string, 0)
 	if m.reviewResult.Dropped > 0 {
-		warnings = append(warnings, fmt.Sprintf("Warning: %d comment(s) dropped due to missing file/line/title/body.", m.reviewResult.Dropped))
+		warnings = append(warnings, fmt.Sprintf("Warning: %d comment(s) dropped: missing file/line/title/body or outside the diff.", m.reviewResult.Dropped))
+	}
+	if m.reviewResult.Dismissed > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d comment(s) dismissed.", m.reviewResult.Dismissed))
 	}
 	if len(m.reviewResult.FileErrors) > 0 {
 		var failedFiles []string
@@ -1689,8 +2821,8 @@ func (m Model) renderCommentsWarnings() string {
 			failedFiles = append(failedFiles, filepath.Base(path))
 		}
 		sort.Strings(failedFiles)
-		warnings = append(warnings, lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render(
-			fmt.Sprintf("Failed to review %d file(s): %s", len(failedFiles), strings.Join(failedFiles, ", ")),
+		warnings = append(warnings, lipgloss.NewStyle().Foreground(theme.Error).Render(
+			fmt.Sprintf("Failed to review %d file(s): %s (R to retry them)", len(failedFiles), strings.Join(failedFiles, ", ")),
 		))
 	}
 	if len(warnings) == 0 {
@@ -1701,7 +2833,7 @@ func (m Model) renderCommentsWarnings() string {
 
 func (m Model) renderCommentsHints() string {
 	hints := []string{
-		"↑/↓ to move, Space to toggle publish, s to cycle severity, / to filter file, c to clear filters, Tab to switch panel.",
+		"↑/↓ to move, Space to toggle publish, a/n for all/none, s to cycle severity, t to cycle tag, o to change sort, E to edit, x to dismiss, p to apply a suggestion, w to ask why, ctrl+g to regenerate, gg/G for first/last, v to compare with the last run, / to search, c to clear filters, Tab to switch panel.",
 	}
 	if m.commentsFilterActive {
 		hints = []string{"Typing filter... Enter/Esc to apply."}
@@ -1737,7 +2869,12 @@ func (m Model) hasGuidelineOption(path string) bool {
 	return false
 }
 
+// validateGuidelinePath checks a local guideline file. URLs are fetched when the selection is
+// hashed, which reports an unreachable one.
 func validateGuidelinePath(path string) error {
+	if review.IsGuidelineURL(path) {
+		return nil
+	}
 	info, err := os.Stat(path)
 	if err != nil {
 		return err
@@ -1764,15 +2901,77 @@ func clamp(value, min, max int) int {
 	return value
 }
 
-func (m Model) filteredBranches() []string {
+const branchSortName = "name"
+
+// sortBranches puts local branches before remote ones, for the picker's two sections, and orders
+// each section by name or by latest commit.
+func sortBranches(branches []git.Branch, byName bool) {
+	sort.SliceStable(branches, func(i, j int) bool {
+		if branches[i].Remote != branches[j].Remote {
+			return !branches[i].Remote
+		}
+		if byName {
+			return branches[i].Name < branches[j].Name
+		}
+		return branches[i].CommittedAt.After(branches[j].CommittedAt)
+	})
+}
+
+// toggleBranchSort switches the picker between recency and name order, keeping the highlighted
+// branch selected.
+func (m *Model) toggleBranchSort() {
+	var current string
+	if filtered := m.filteredBranches(); m.cursor < len(filtered) {
+		current = filtered[m.cursor].Name
+	}
+	if m.cfg.BranchSort == branchSortName {
+		m.cfg.BranchSort = ""
+	} else {
+		m.cfg.BranchSort = branchSortName
+	}
+	sortBranches(m.branches, m.cfg.BranchSort == branchSortName)
+	m.cursor = m.initialBranchIndex(current)
+}
+
+// relativeTime renders how long before now t was, at the coarsest useful unit.
+func relativeTime(t, now time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	elapsed := now.Sub(t)
+	plural := func(n int, unit string) string {
+		if n == 1 {
+			return fmt.Sprintf("1 %s ago", unit)
+		}
+		return fmt.Sprintf("%d %ss ago", n, unit)
+	}
+	switch {
+	case elapsed < time.Minute:
+		return "just now"
+	case elapsed < time.Hour:
+		return plural(int(elapsed/time.Minute), "minute")
+	case elapsed < 24*time.Hour:
+		return plural(int(elapsed/time.Hour), "hour")
+	case elapsed < 30*24*time.Hour:
+		return plural(int(elapsed/(24*time.Hour)), "day")
+	case elapsed < 365*24*time.Hour:
+		return plural(int(elapsed/(30*24*time.Hour)), "month")
+	default:
+		return plural(int(elapsed/(365*24*time.Hour)), "year")
+	}
+}
+
+// filteredBranches applies the picker's filter across both local and remote branches, keeping the
+// local-then-remote order.
+func (m Model) filteredBranches() []git.Branch {
 	filter := strings.ToLower(strings.TrimSpace(m.branchFilterInput.Value()))
 	if filter == "" {
 		return m.branches
 	}
 
-	filtered := make([]string, 0, len(m.branches))
+	filtered := make([]git.Branch, 0, len(m.branches))
 	for _, branch := range m.branches {
-		if strings.Contains(strings.ToLower(branch), filter) {
+		if strings.Contains(strings.ToLower(branch.Name), filter) {
 			filtered = append(filtered, branch)
 		}
 	}
@@ -1870,6 +3069,16 @@ func (m *Model) updateDiffTab(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.diffPanelFocus = panelFocusLeft
 		}
 		return m, nil
+	case "S":
+		m.diffStatOpen = !m.diffStatOpen
+		m.updateDiffViewportContent()
+		return m, nil
+	case "ctrl+o":
+		return m, m.openSelectedInEditor()
+	case "w":
+		m.diffWrap = !m.diffWrap
+		m.updateDiffViewportContent()
+		return m, nil
 	}
 
 	if m.diffPanelFocus == panelFocusRight {
@@ -1889,10 +3098,12 @@ func (m *Model) updateDiffTab(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "up", "k":
 		m.diffFile = clamp(m.diffFile-1, 0, len(m.diffFiles)-1)
+		m.diffStatOpen = false
 		m.updateDiffViewportContent()
 		return m, nil
 	case "down", "j":
 		m.diffFile = clamp(m.diffFile+1, 0, len(m.diffFiles)-1)
+		m.diffStatOpen = false
 		m.updateDiffViewportContent()
 		return m, nil
 	}
@@ -1900,39 +3111,137 @@ func (m *Model) updateDiffTab(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// exportReportCmd writes the current result in format to the --output path when its extension
+// matches, otherwise to review.DefaultReportName in the repository root.
+func (m Model) exportReportCmd(format review.ReportFormat) tea.Cmd {
+	if m.reviewRunning || m.reviewResult.GeneratedAt.IsZero() {
+		return nil
+	}
+	path := filepath.Join(m.repoRoot, review.DefaultReportName(format))
+	if m.outputPath != "" && m.outputFormat == format {
+		path = m.outputPath
+	}
+	result := m.reviewResult
+	return func() tea.Msg {
+		return reportExportedMsg{path: path, err: review.WriteReport(path, format, result)}
+	}
+}
+
+// maxReviewConcurrency bounds the Config tab control; beyond this providers mostly rate-limit.
+const maxReviewConcurrency = 16
+
+// maxConcurrency is the effective number of files reviewed in parallel.
+func (m Model) maxConcurrency() int {
+	if m.cfg.MaxConcurrency <= 0 {
+		return review.DefaultMaxConcurrency
+	}
+	return m.cfg.MaxConcurrency
+}
+
+// providerName is the configured LLM provider, or the one implied by the selected model.
+func (m Model) providerName() string {
+	return llm.ProviderForModel(m.cfg.Provider, m.cfg.LastModel)
+}
+
 func (m Model) maybeStartReview() tea.Cmd {
 	if m.reviewRunning || !m.reviewResult.GeneratedAt.IsZero() {
 		return nil
 	}
+	return m.startReview(nil)
+}
+
+// retryFailedFiles re-reviews only the files the current result has errors for, keeping the rest
+// of it (see review.RetryFailed).
+func (m *Model) retryFailedFiles() tea.Cmd {
+	if m.reviewRunning {
+		return nil
+	}
+	if len(m.reviewResult.FileErrors) == 0 {
+		m.statusMessage = "no failed files to retry"
+		return nil
+	}
+	prior := m.reviewResult
+	return m.startReview(&prior)
+}
+
+// apiKey is the key typed into the wizard for this session, or else the provider's env var.
+func (m Model) apiKey() string {
+	if apiKey := strings.TrimSpace(m.openRouterKey); apiKey != "" {
+		return apiKey
+	}
+	return strings.TrimSpace(config.ProviderAPIKey(m.providerName()))
+}
+
+// startReview reviews the current diff, or with prior set only retries prior's failed files.
+func (m Model) startReview(prior *review.Result) tea.Cmd {
 	if len(m.diffFiles) == 0 || m.diffErr != nil {
 		return nil
 	}
-	apiKey := strings.TrimSpace(m.openRouterKey)
+	apiKey := m.apiKey()
 	if apiKey == "" {
-		apiKey = strings.TrimSpace(config.OpenRouterAPIKey())
+		m.reviewErr = errors.New("missing " + config.ProviderKeyEnv(m.providerName()))
+		return nil
 	}
-	if apiKey == "" {
-		m.reviewErr = errors.New("missing OPENROUTER_API_KEY")
+	files, _ := review.SplitIgnored(m.diffFiles, m.reviewIgnore)
+	if len(files) == 0 {
 		return nil
 	}
-	return startReviewCmd(m.diffFiles, m.cfg, m.guidelineHash, apiKey)
+	fileContent := sourceFileReader(m.diffSource, m.repoRoot, m.branch)
+	return startReviewCmd(m.repoRoot, files, m.cfg, m.guidelineHash, apiKey, m.noCache, fileContent, prior)
 }
 
-func startReviewCmd(diffFiles []git.DiffFile, cfg config.Config, guidelineHash string, apiKey string) tea.Cmd {
+func startReviewCmd(repoRoot string, diffFiles []git.DiffFile, cfg config.Config, guidelineHash string, apiKey string, noCache bool, fileContent review.FileContentReader, prior *review.Result) tea.Cmd {
 	return func() tea.Msg {
-		slog.Info("Starting review", "files", len(diffFiles), "model", cfg.LastModel, "hash", guidelineHash)
+		total := len(diffFiles)
+		if prior != nil {
+			total = len(prior.FileErrors)
+		}
+		slog.Info("Starting review", "files", total, "retry", prior != nil, "model", cfg.LastModel, "hash", guidelineHash)
 		updates := make(chan tea.Msg)
 		ctx, cancel := context.WithCancel(context.Background())
 		go func() {
 			defer close(updates)
-			updates <- reviewProgressMsg{completed: 0, total: len(diffFiles), failed: 0, file: "starting"}
-			client := llm.NewClient(apiKey, config.OpenRouterBaseURL())
-			result, err := review.Run(ctx, client, diffFiles, review.RunOptions{
-				Model:          cfg.LastModel,
-				GuidelinePaths: cfg.Guidelines,
-				FreeText:       cfg.FreeGuideline,
-				GuidelineHash:  guidelineHash,
-			}, func(progress review.Progress) {
+			updates <- reviewProgressMsg{completed: 0, total: total, failed: 0, file: "starting"}
+			client, err := llm.NewClientFromConfig(cfg, apiKey)
+			if err != nil {
+				updates <- reviewCompletedMsg{err: err}
+				return
+			}
+			policy, err := review.ParseNoGoPolicy(cfg.NoGoPolicy)
+			if err != nil {
+				updates <- reviewCompletedMsg{err: fmt.Errorf("config noGoPolicy: %w", err)}
+				return
+			}
+			minSeverity, err := review.ParseMinSeverity(cfg.MinSeverity)
+			if err != nil {
+				updates <- reviewCompletedMsg{err: fmt.Errorf("config minSeverity: %w", err)}
+				return
+			}
+			prompts, err := review.LoadRepoPromptTemplates(repoRoot, cfg.PromptTemplate)
+			if err != nil {
+				updates <- reviewCompletedMsg{err: err}
+				return
+			}
+			opts := review.RunOptions{
+				Model:                  cfg.LastModel,
+				GuidelinePaths:         cfg.Guidelines,
+				FreeText:               cfg.FreeGuideline,
+				GuidelineHash:          guidelineHash,
+				FileHints:              cfg.FileHints,
+				OnStream:               streamUpdates(ctx, cfg, updates),
+				NoCache:                noCache,
+				MaxDiffLinesPerRequest: cfg.MaxDiffLinesPerRequest,
+				FileContent:            fileContent,
+				FileContext:            cfg.FileContext,
+				Temperature:            cfg.Temperature,
+				MaxConcurrency:         cfg.MaxConcurrency,
+				MergeOverlapping:       cfg.MergeOverlapping,
+				NoGoPolicy:             &policy,
+				MinSeverity:            minSeverity,
+				Prompts:                prompts,
+				LanguageFocus:          cfg.LanguageFocus,
+			}
+			onProgress := func(progress review.Progress) {
 				select {
 				case <-ctx.Done():
 					return
@@ -1944,18 +3253,36 @@ func startReviewCmd(diffFiles []git.DiffFile, cfg config.Config, guidelineHash s
 					lastError: progress.LastError,
 				}:
 				}
-			})
+			}
+			var result review.Result
+			if prior != nil {
+				result, err = review.RetryFailed(ctx, client, diffFiles, *prior, opts, onProgress)
+			} else {
+				result, err = review.Run(ctx, client, diffFiles, opts, onProgress)
+			}
 			select {
 			case <-ctx.Done():
 				return
 			default:
-				updates <- reviewCompletedMsg{result: result, err: err}
+				updates <- reviewCompletedMsg{result: result, err: err, retry: prior != nil}
 			}
 		}()
 		return reviewStartedMsg{updates: updates, cancel: cancel}
 	}
 }
 
+func streamUpdates(ctx context.Context, cfg config.Config, updates chan<- tea.Msg) func(string, int) {
+	if cfg.DisableStreaming {
+		return nil
+	}
+	return func(file string, received int) {
+		select {
+		case <-ctx.Done():
+		case updates <- reviewStreamMsg{file: file, received: received}:
+		}
+	}
+}
+
 func listenReviewCmd(updates <-chan tea.Msg) tea.Cmd {
 	return func() tea.Msg {
 		msg, ok := <-updates
@@ -1968,54 +3295,49 @@ func listenReviewCmd(updates <-chan tea.Msg) tea.Cmd {
 
 func (m Model) publishReviewCmd(ctx context.Context) tea.Cmd {
 	return func() tea.Msg {
-		slog.Info("Starting publish to Bitbucket")
-		token := strings.TrimSpace(m.publishToken)
-		if token == "" {
-			token = strings.TrimSpace(config.BitbucketToken())
+		slog.Info("Starting publish", "provider", m.publishProvider())
+		publisher, err := m.newPublisher()
+		if err != nil {
+			return publishCompletedMsg{err: err}
 		}
-
-		workspace := strings.TrimSpace(m.publishWorkspaceInput.Value())
-		repoSlug := strings.TrimSpace(m.publishRepoSlugInput.Value())
-		prIDStr := strings.TrimSpace(m.publishPRIDInput.Value())
-
-		var prID int
-		fmt.Sscanf(prIDStr, "%d", &prID)
-
-		if token == "" || workspace == "" || repoSlug == "" || prID == 0 {
-			return publishCompletedMsg{err: errors.New("missing bitbucket configuration (workspace, repo, PR ID, or token)")}
+		opts, err := m.publishOptions()
+		if err != nil {
+			return publishCompletedMsg{err: err}
 		}
+		outcome, err := publish.Publish(ctx, publisher, m.reviewResult, opts)
+		return publishCompletedMsg{resultID: outcome.String(), summaryKey: m.publishSummaryKey(), summaryID: outcome.SummaryID, err: err}
+	}
+}
 
-		cfg := bitbucket.Config{
-			Workspace:   workspace,
-			RepoSlug:    repoSlug,
-			PullRequest: prID,
-			Token:       token,
+// previewPublishCmd renders what publishReviewCmd would post without calling the code host.
+func (m Model) previewPublishCmd() tea.Cmd {
+	opts, err := m.publishOptions()
+	result := m.reviewResult
+	return func() tea.Msg {
+		if err != nil {
+			return publishPreviewMsg{err: err}
 		}
-
-		client := bitbucket.NewClient(cfg)
-		markdown := bitbucket.ComposeMarkdown(m.reviewResult)
-
-		resultID, err := client.PublishComment(ctx, markdown)
-		return publishCompletedMsg{resultID: resultID, err: err}
+		preview, path, err := publish.PreviewPublish(context.Background(), result, opts)
+		return publishPreviewMsg{preview: preview, path: path, err: err}
 	}
 }
 
 func (m Model) renderErrorView(err error, hint string) string {
 	errorStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("9")).
+		Foreground(theme.Error).
 		Bold(true).
 		Padding(0, 0, 1, 0)
 
 	background := lipgloss.NewStyle().
 		Padding(1, 2).
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("9"))
+		BorderForeground(theme.Error)
 
 	content := lipgloss.JoinVertical(lipgloss.Left,
 		errorStyle.Render("ERROR"),
 		m.wrapText(err.Error(), m.width/2),
 		"",
-		lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(hint),
+		lipgloss.NewStyle().Foreground(theme.Muted).Render(hint),
 	)
 
 	return lipgloss.Place(m.width, m.height-2, lipgloss.Center, lipgloss.Center, background.Render(content))
@@ -2040,19 +3362,31 @@ func (m Model) renderStatusBar() string {
 	}
 
 	style := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#C1C1C1")).
-		Background(lipgloss.Color("#353535")).
+		Foreground(theme.BarText).
+		Background(theme.BarSurface).
 		Padding(0, 1)
 
 	modeStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFFFF")).
-		Background(lipgloss.Color("#6124DF")).
+		Foreground(theme.BadgeText).
+		Background(theme.Badge).
 		Bold(true).
 		Padding(0, 1)
 
 	status := "q: quit • ?: help • h/l: tabs"
 	if m.inWizard {
 		status = "q: quit • enter: next • b: back"
+		if len(m.sessions) > 0 {
+			status += " • esc: cancel new session"
+		}
+	} else if m.sessionErr != nil {
+		status = m.sessionErr.Error()
+	} else if m.statusErr != nil {
+		status = m.statusErr.Error()
+	} else if m.statusMessage != "" {
+		status = m.statusMessage
+	} else if len(m.sessions) > 1 {
+		status = fmt.Sprintf("session %d/%d: %s • ctrl+t: next • ctrl+n: new • %s",
+			m.sessionIndex+1, len(m.sessions), m.sessions[m.sessionIndex].label(), status)
 	}
 
 	modeStr := modeStyle.Render(mode)
@@ -2069,37 +3403,79 @@ q, ctrl+c   Quit
 ?           Toggle help
 h, left     Previous tab
 l, right    Next tab
+ctrl+n      Add another branch/PR review session
+ctrl+t      Switch to the next review session
 
 Diff Tab:
 j, down     Next file
 k, up       Previous file
 tab         Switch between file list and diff
 pgup, pgdn  Scroll diff (when focused)
+S           Show the diff stat summary (files and lines changed)
+w           Toggle wrapping of long diff lines
+ctrl+o      Open the selected file in $VISUAL/$EDITOR
 
 Comments Tab:
 j, down     Next comment
 k, up       Previous comment
+gg, G       First / last comment (top / bottom of the detail pane)
+ctrl+d/u    Half a page down / up
 r           Retry review
+R           Retry only the files that failed
 space       Toggle publish inclusion
+a, n        Include all / none of the filtered comments
+P           Publish only the filtered severity and above
 s           Cycle severity filter
-/           Search by file path
+t           Cycle tag filter
+o           Sort by severity, file or line
+/           Search file paths, titles and bodies
 c           Clear filters
+e           Export the report as Markdown
+J           Export the report as JSON
+y           Copy the selected comment to the clipboard
+p           Apply the selected comment's suggestion (or write it as a patch)
+w           Ask the model a follow-up question about the selected comment
+ctrl+g      Regenerate the selected comment with more detail
+v           Compare with the previous review of this branch
+ctrl+o      Open the comment's file at its line in $VISUAL/$EDITOR
+E           Edit the selected comment's title and body
++           Add a comment of your own
+x           Dismiss the selected comment
 tab         Switch between table and detail
 
+Verdict Tab:
+d           Override decision (GO/NO_GO)
+e           Export the report as Markdown
+J           Export the report as JSON
+
 Publish Tab:
 tab         Cycle input fields
+v           Switch provider (Bitbucket/GitHub/GitLab)
+i           Toggle inline comments vs one summary comment
+d           Toggle dry run (preview instead of posting)
+t           Test the connection (token and pull request)
+pgup, pgdn  Scroll the dry-run preview
 p           Execute publishing
 
+Logs Tab:
+↑/↓         Scroll recent LLM requests
+r           Reload the request log
+
 Config Tab:
 r           Re-run review (keep config)
+R           Retry only the files that failed
+f           Toggle per-file hints (<path>.review.md)
+x           Toggle surrounding file context in prompts
+m           Toggle merging overlapping comments
++/-         Raise/lower parallel file reviews
 
 Press any key to close help.`
 
 	overlayStyle := lipgloss.NewStyle().
 		BorderStyle(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("62")).
+		BorderForeground(theme.Accent).
 		Padding(1, 2).
-		Background(lipgloss.Color("#1A1A1A"))
+		Background(theme.HelpSurface)
 
 	overlay := overlayStyle.Render(helpText)
 
@@ -0,0 +1,54 @@
+package app
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/review"
+)
+
+// benchmarkCommentsModel is a Model holding a 2000-comment review with an active file filter.
+func benchmarkCommentsModel() Model {
+	m := NewModel(Options{})
+	comments := make([]review.Comment, 2000)
+	severities := []review.Severity{review.SeverityBlocker, review.SeverityIssue, review.SeveritySuggestion, review.SeverityNit}
+	for i := range comments {
+		comments[i] = review.Comment{
+			FilePath:  fmt.Sprintf("internal/pkg%d/file%d.go", i%40, i),
+			StartLine: i%300 + 1,
+			EndLine:   i%300 + 3,
+			Severity:  severities[i%len(severities)],
+			Title:     fmt.Sprintf("Finding %d", i),
+			Body:      "Close the file handle before returning.",
+			Publish:   i%2 == 0,
+		}
+	}
+	m.reviewResult = review.Result{GeneratedAt: time.Now(), Comments: comments}
+	m.commentsSearch.SetValue("pkg1")
+	return m
+}
+
+// BenchmarkBuildCommentRows measures a filter refresh with the row cache warm, as on every filter
+// keystroke after the first.
+func BenchmarkBuildCommentRows(b *testing.B) {
+	m := benchmarkCommentsModel()
+	m.buildCommentRows()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.buildCommentRows()
+	}
+}
+
+// BenchmarkBuildCommentRows_uncached rebuilds the row cache on every refresh, the cost before rows
+// were cached.
+func BenchmarkBuildCommentRows_uncached(b *testing.B) {
+	m := benchmarkCommentsModel()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.invalidateCommentRows()
+		m.buildCommentRows()
+	}
+}
@@ -0,0 +1,333 @@
+package app
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/bitbucket"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/config"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/git"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/github"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/gitlab"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/publish"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/review"
+)
+
+// publishFields describes the Publish tab inputs for one code host. The three location inputs are
+// shared between hosts and persisted as PublishWorkspace (PublishGitLabHost for GitLab),
+// PublishRepoSlug and PublishPRID.
+type publishFields struct {
+	title, ownerName                         string
+	ownerLabel, repoLabel, prLabel           string
+	ownerPlaceholder, repoPlaceholder        string
+	prPlaceholder, tokenPlaceholder, envName string
+}
+
+// publishFieldsFor describes the inputs for provider; flavor only matters for Bitbucket.
+func publishFieldsFor(provider string, flavor bitbucket.APIFlavor) publishFields {
+	switch provider {
+	case publish.ProviderGitLab:
+		return publishFields{
+			title:            "GitLab",
+			ownerName:        "host",
+			ownerLabel:       "Host:     ",
+			repoLabel:        "Project:  ",
+			prLabel:          "MR IID:   ",
+			ownerPlaceholder: "GitLab host (default " + gitlab.DefaultHost + ")",
+			repoPlaceholder:  "Project path or ID (e.g. group/my-repo)",
+			prPlaceholder:    "Merge request IID (e.g. 42)",
+			tokenPlaceholder: "GitLab personal access token",
+			envName:          "GITLAB_TOKEN",
+		}
+	case publish.ProviderGitHub:
+		return publishFields{
+			title:            "GitHub",
+			ownerName:        "owner",
+			ownerLabel:       "Owner:    ",
+			repoLabel:        "Repo:     ",
+			prLabel:          "PR number:",
+			ownerPlaceholder: "GitHub owner or org (e.g. acme)",
+			repoPlaceholder:  "Repository (e.g. my-repo)",
+			prPlaceholder:    "PR number (e.g. 123)",
+			tokenPlaceholder: "GitHub token",
+			envName:          "GITHUB_TOKEN",
+		}
+	}
+	if flavor == bitbucket.FlavorServer {
+		return publishFields{
+			title:            "Bitbucket Server",
+			ownerName:        "project key",
+			ownerLabel:       "Project:  ",
+			repoLabel:        "Repo Slug:",
+			prLabel:          "PR ID:    ",
+			ownerPlaceholder: "Bitbucket Server project key (e.g. PROJ)",
+			repoPlaceholder:  "Repo Slug (e.g. my-repo)",
+			prPlaceholder:    "PR ID (e.g. 123)",
+			tokenPlaceholder: "Bitbucket HTTP access token",
+			envName:          "BITBUCKET_TOKEN",
+		}
+	}
+	return publishFields{
+		title:            "Bitbucket Cloud",
+		ownerName:        "workspace",
+		ownerLabel:       "Workspace:",
+		repoLabel:        "Repo Slug:",
+		prLabel:          "PR ID:    ",
+		ownerPlaceholder: "Bitbucket Workspace (e.g. acme)",
+		repoPlaceholder:  "Repo Slug (e.g. my-repo)",
+		prPlaceholder:    "PR ID (e.g. 123)",
+		tokenPlaceholder: "Bitbucket App Password or Token",
+		envName:          "BITBUCKET_TOKEN",
+	}
+}
+
+// publishFields describes the Publish tab inputs for the selected code host.
+func (m Model) publishFields() publishFields {
+	return publishFieldsFor(m.publishProvider(), bitbucketFlavor(m.cfg))
+}
+
+// bitbucketFlavor is FlavorServer when a self-hosted Bitbucket URL is configured.
+func bitbucketFlavor(cfg config.Config) bitbucket.APIFlavor {
+	if cfg.PublishBitbucketURL != "" {
+		return bitbucket.FlavorServer
+	}
+	return bitbucket.FlavorCloud
+}
+
+// publishProvider is the code host selected on the Publish tab.
+func (m Model) publishProvider() string {
+	provider, err := publish.ParseProvider(m.cfg.PublishProvider)
+	if err != nil {
+		return publish.ProviderBitbucket
+	}
+	return provider
+}
+
+// publishEnvToken reads provider's token from the environment.
+func publishEnvToken(provider string) string {
+	switch provider {
+	case publish.ProviderGitHub:
+		return config.GitHubToken()
+	case publish.ProviderGitLab:
+		return config.GitLabToken()
+	default:
+		return config.BitbucketToken()
+	}
+}
+
+// publishOwnerSlot is the config field backing the first Publish tab input for the selected host.
+func (m *Model) publishOwnerSlot() *string {
+	if m.publishProvider() == publish.ProviderGitLab {
+		return &m.cfg.PublishGitLabHost
+	}
+	return &m.cfg.PublishWorkspace
+}
+
+func (m *Model) applyPublishPlaceholders() {
+	fields := m.publishFields()
+	m.publishWorkspaceInput.Placeholder = fields.ownerPlaceholder
+	m.publishRepoSlugInput.Placeholder = fields.repoPlaceholder
+	m.publishPRIDInput.Placeholder = fields.prPlaceholder
+	m.publishTokenInput.Placeholder = fields.tokenPlaceholder
+}
+
+// detectRemoteCmd reads origin so the Publish tab can be prefilled; repos without one are ignored.
+func detectRemoteCmd(repoRoot string) tea.Cmd {
+	return func() tea.Msg {
+		raw, err := git.RemoteURL(repoRoot, "origin")
+		if err != nil {
+			return remoteDetectedMsg{err: err}
+		}
+		remote, err := git.ParseRemoteURL(raw)
+		return remoteDetectedMsg{remote: remote, err: err}
+	}
+}
+
+// prefillPublishFromRemote fills empty Publish tab fields from origin. It picks the provider too,
+// unless one was saved or location fields were already entered.
+func (m *Model) prefillPublishFromRemote() {
+	if m.gitRemote == nil {
+		return
+	}
+	provider, ok := publish.ProviderForHost(m.gitRemote.Host)
+	if !ok {
+		return
+	}
+	if m.cfg.PublishProvider == "" && m.publishWorkspaceInput.Value() == "" && m.publishRepoSlugInput.Value() == "" {
+		m.cfg.PublishProvider = provider
+		m.applyPublishPlaceholders()
+	}
+	if m.publishProvider() != provider {
+		return
+	}
+
+	owner, repo := m.gitRemote.Owner, m.gitRemote.Repo
+	if provider == publish.ProviderGitLab {
+		owner, repo = m.gitRemote.Host, m.gitRemote.Owner+"/"+m.gitRemote.Repo
+	}
+	if m.publishWorkspaceInput.Value() == "" {
+		m.publishWorkspaceInput.SetValue(owner)
+	}
+	if m.publishRepoSlugInput.Value() == "" {
+		m.publishRepoSlugInput.SetValue(repo)
+	}
+}
+
+// updatePublishPreviewLayout sizes the dry-run preview to the space left under the Publish form.
+func (m *Model) updatePublishPreviewLayout() {
+	m.publishPreview.Width = max(m.width-4, 20)
+	m.publishPreview.Height = max(m.height-28, 5)
+}
+
+func (m *Model) cyclePublishProvider() {
+	*m.publishOwnerSlot() = m.publishWorkspaceInput.Value()
+	current := m.publishProvider()
+	for i, name := range publish.ProviderNames {
+		if name == current {
+			m.cfg.PublishProvider = publish.ProviderNames[(i+1)%len(publish.ProviderNames)]
+			break
+		}
+	}
+	m.publishWorkspaceInput.SetValue(*m.publishOwnerSlot())
+	m.applyPublishPlaceholders()
+}
+
+// publishTarget reads the pull request location from the Publish tab inputs.
+func (m Model) publishTarget() (owner, repo string, prID int) {
+	owner = strings.TrimSpace(m.publishWorkspaceInput.Value())
+	repo = strings.TrimSpace(m.publishRepoSlugInput.Value())
+	fmt.Sscanf(strings.TrimSpace(m.publishPRIDInput.Value()), "%d", &prID)
+	return owner, repo, prID
+}
+
+// publishSummaryKey identifies the target pull request in Config.PublishedComments.
+func (m Model) publishSummaryKey() string {
+	owner, repo, prID := m.publishTarget()
+	return publish.SummaryKey(m.publishProvider(), owner, repo, prID)
+}
+
+// publishOptions reads the publish mode, the summary template and any summary comment left on
+// this pull request earlier.
+func (m Model) publishOptions() (publish.Options, error) {
+	tmpl, err := review.LoadRepoSummaryTemplate(m.repoRoot, m.cfg.PublishTemplate)
+	if err != nil {
+		return publish.Options{}, err
+	}
+	return publish.Options{
+		Inline:    m.cfg.PublishInline && publish.SupportsInline(m.publishProvider()),
+		SummaryID: m.cfg.PublishedComments[m.publishSummaryKey()],
+		Template:  tmpl,
+	}, nil
+}
+
+// PublishTarget is a pull request to publish to. Owner is the Bitbucket workspace (the project key
+// on Server), the GitHub owner or the GitLab host.
+type PublishTarget struct {
+	Provider    string
+	Owner       string
+	Repo        string
+	PullRequest int
+	Token       string
+}
+
+// SavedPublishTarget is the target saved in cfg, with the provider's token from the environment.
+func SavedPublishTarget(cfg config.Config) (PublishTarget, error) {
+	provider, err := publish.ParseProvider(cfg.PublishProvider)
+	if err != nil {
+		return PublishTarget{}, err
+	}
+	owner := cfg.PublishWorkspace
+	if provider == publish.ProviderGitLab {
+		owner = cfg.PublishGitLabHost
+	}
+	return PublishTarget{
+		Provider:    provider,
+		Owner:       strings.TrimSpace(owner),
+		Repo:        strings.TrimSpace(cfg.PublishRepoSlug),
+		PullRequest: cfg.PublishPRID,
+		Token:       strings.TrimSpace(publishEnvToken(provider)),
+	}, nil
+}
+
+// NewPublisher builds the client for target. cfg supplies the self-hosted Bitbucket URL; diff is the
+// reviewed diff, which Bitbucket Server anchors inline comments against.
+func NewPublisher(cfg config.Config, target PublishTarget, diff []git.DiffFile) (publish.Publisher, error) {
+	fields := publishFieldsFor(target.Provider, bitbucketFlavor(cfg))
+	var missing []string
+	if target.Owner == "" && target.Provider != publish.ProviderGitLab {
+		missing = append(missing, fields.ownerName)
+	}
+	if target.Repo == "" {
+		missing = append(missing, "repo")
+	}
+	if target.PullRequest == 0 {
+		missing = append(missing, "PR")
+	}
+	if target.Token == "" {
+		missing = append(missing, "token ("+fields.envName+")")
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing %s configuration: %s", fields.title, strings.Join(missing, ", "))
+	}
+
+	switch target.Provider {
+	case publish.ProviderGitHub:
+		return github.NewClient(github.Config{Owner: target.Owner, Repo: target.Repo, PullRequest: target.PullRequest, Token: target.Token}), nil
+	case publish.ProviderGitLab:
+		return gitlab.NewClient(gitlab.Config{Host: target.Owner, Project: target.Repo, MergeRequest: target.PullRequest, Token: target.Token}), nil
+	}
+	bbCfg := bitbucket.Config{
+		Workspace:   target.Owner,
+		RepoSlug:    target.Repo,
+		PullRequest: target.PullRequest,
+		Token:       target.Token,
+		Flavor:      bitbucketFlavor(cfg),
+		Diff:        diff,
+	}
+	if bbCfg.Flavor == bitbucket.FlavorServer {
+		bbCfg.BaseURL = cfg.PublishBitbucketURL
+	}
+	return bitbucket.NewClient(bbCfg), nil
+}
+
+// newPublisher builds the client for the selected code host from the Publish tab inputs, falling
+// back to the environment for the token.
+func (m Model) newPublisher() (publish.Publisher, error) {
+	owner, repo, prID := m.publishTarget()
+	provider := m.publishProvider()
+	return NewPublisher(m.cfg, PublishTarget{
+		Provider:    provider,
+		Owner:       owner,
+		Repo:        repo,
+		PullRequest: prID,
+		Token:       cmp.Or(strings.TrimSpace(m.publishTokenInput.Value()), strings.TrimSpace(publishEnvToken(provider))),
+	}, m.diffFiles)
+}
+
+type publishVerifiedMsg struct {
+	title string
+	err   error
+}
+
+// verifyPublishCmd checks the Publish tab's token and pull request without posting anything.
+func (m Model) verifyPublishCmd() tea.Cmd {
+	title := m.publishFields().title
+	publisher, err := m.newPublisher()
+	return func() tea.Msg {
+		if err != nil {
+			return publishVerifiedMsg{title: title, err: err}
+		}
+		verifier, ok := publisher.(publish.CredentialVerifier)
+		if !ok {
+			return publishVerifiedMsg{title: title, err: fmt.Errorf("%s has no connection check yet", title)}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		return publishVerifiedMsg{title: title, err: verifier.VerifyCredentials(ctx)}
+	}
+}
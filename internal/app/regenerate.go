@@ -0,0 +1,94 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/config"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/git"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/llm"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/review"
+)
+
+type commentRegeneratedMsg struct {
+	commentID string
+	comment   review.Comment
+	err       error
+}
+
+// regenerateSelectedComment asks the model to redo the selected comment with more detail; the
+// reply replaces it in place (see commentRegeneratedMsg).
+func (m *Model) regenerateSelectedComment() tea.Cmd {
+	index, ok := m.selectedCommentIndex()
+	if !ok {
+		return nil
+	}
+	if m.regenerateRunning {
+		m.statusMessage = "still regenerating the previous comment"
+		return nil
+	}
+	comment := m.reviewResult.Comments[index]
+	var file *git.DiffFile
+	for i := range m.diffFiles {
+		if m.diffFiles[i].Path == comment.FilePath {
+			file = &m.diffFiles[i]
+		}
+	}
+	if file == nil {
+		m.statusErr = fmt.Errorf("%s is not in the current diff", comment.FilePath)
+		return nil
+	}
+	apiKey := m.apiKey()
+	if apiKey == "" {
+		m.statusErr = errors.New("missing " + config.ProviderKeyEnv(m.providerName()))
+		return nil
+	}
+	m.regenerateRunning = true
+	m.statusMessage = "regenerating the comment..."
+	fileContent := sourceFileReader(m.diffSource, m.repoRoot, m.branch)
+	return regenerateCommentCmd(m.repoRoot, m.cfg, apiKey, comment, *file, fileContent)
+}
+
+func regenerateCommentCmd(repoRoot string, cfg config.Config, apiKey string, comment review.Comment, file git.DiffFile, fileContent review.FileContentReader) tea.Cmd {
+	return func() tea.Msg {
+		client, err := llm.NewClientFromConfig(cfg, apiKey)
+		if err != nil {
+			return commentRegeneratedMsg{commentID: comment.ID, err: err}
+		}
+		prompts, err := review.LoadRepoPromptTemplates(repoRoot, cfg.PromptTemplate)
+		if err != nil {
+			return commentRegeneratedMsg{commentID: comment.ID, err: err}
+		}
+		opts := review.RunOptions{
+			Model:          cfg.LastModel,
+			GuidelinePaths: cfg.Guidelines,
+			FreeText:       cfg.FreeGuideline,
+			FileHints:      cfg.FileHints,
+			FileContent:    fileContent,
+			Temperature:    cfg.Temperature,
+			Prompts:        prompts,
+			LanguageFocus:  cfg.LanguageFocus,
+		}
+		regenerated, err := review.RegenerateComment(context.Background(), client, file, comment, opts)
+		return commentRegeneratedMsg{commentID: comment.ID, comment: regenerated, err: err}
+	}
+}
+
+// replaceComment puts a regenerated comment in the place of the one with the same ID, if it is
+// still there.
+func (m *Model) replaceComment(comment review.Comment) bool {
+	for i := range m.reviewResult.Comments {
+		if m.reviewResult.Comments[i].ID == comment.ID {
+			m.reviewResult.Comments[i] = comment
+			m.reviewResult.Verdict.Stats = review.ComputeStats(m.reviewResult.Comments)
+			m.invalidateCommentRows()
+			m.refreshCommentsTable()
+			m.updateCommentsDetailContent(false)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,165 @@
+package app
+
+import (
+	"errors"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/git"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/review"
+)
+
+// reviewSession holds the per-(base, branch) state that is swapped in and out of the
+// Model when switching between reviews loaded in the same run.
+type reviewSession struct {
+	source     diffSource
+	baseBranch string
+	branch     string
+
+	diffText   string
+	diffFiles  []git.DiffFile
+	diffErr    error
+	diffHidden int
+	diffIgnore []string
+	diffFile   int
+	diffOffset int
+
+	reviewResult   review.Result
+	reviewErr      error
+	previousResult *review.Result
+
+	commentsCursor   int
+	commentsOffset   int
+	commentsFilter   string
+	commentsSeverity review.Severity
+	commentsTag      string
+}
+
+func (s reviewSession) label() string {
+	return s.source.describe(s.baseBranch, s.branch)
+}
+
+// beginSession starts a fresh session for the branches picked in the wizard. The previously active
+// session was already captured by startNewSession, before the wizard overwrote the branch fields.
+func (m *Model) beginSession() {
+	m.sessions = append(m.sessions, reviewSession{source: m.diffSource, baseBranch: m.baseBranch, branch: m.branch})
+	m.sessionIndex = len(m.sessions) - 1
+	m.restoreSession(m.sessions[m.sessionIndex])
+}
+
+func (m *Model) snapshotSession() {
+	if m.sessionIndex < 0 || m.sessionIndex >= len(m.sessions) {
+		return
+	}
+	m.sessions[m.sessionIndex] = reviewSession{
+		source:           m.diffSource,
+		baseBranch:       m.baseBranch,
+		branch:           m.branch,
+		diffText:         m.diffText,
+		diffFiles:        m.diffFiles,
+		diffErr:          m.diffErr,
+		diffHidden:       m.diffFilteredOut,
+		diffIgnore:       m.reviewIgnore,
+		diffFile:         m.diffFile,
+		diffOffset:       m.diffView.YOffset,
+		reviewResult:     m.reviewResult,
+		reviewErr:        m.reviewErr,
+		previousResult:   m.previousResult,
+		commentsCursor:   m.commentsTable.Cursor(),
+		commentsOffset:   m.commentsDetailView.YOffset,
+		commentsFilter:   m.commentsSearch.Value(),
+		commentsSeverity: m.commentsSeverityFilter,
+		commentsTag:      m.commentsTagFilter,
+	}
+}
+
+func (m *Model) restoreSession(s reviewSession) {
+	m.diffSource = s.source
+	m.baseBranch = s.baseBranch
+	m.branch = s.branch
+	m.diffText = s.diffText
+	m.diffFiles = s.diffFiles
+	m.diffErr = s.diffErr
+	m.diffFilteredOut = s.diffHidden
+	m.reviewIgnore = s.diffIgnore
+	m.diffFile = s.diffFile
+	m.reviewResult = s.reviewResult
+	m.reviewErr = s.reviewErr
+	m.previousResult = s.previousResult
+	m.compareOpen = false
+	m.reviewProgress = reviewProgressMsg{}
+	m.verdictErr = nil
+	m.publishError = nil
+	m.publishResultID = ""
+	m.commentsSearch.SetValue(s.commentsFilter)
+	m.commentsSeverityFilter = s.commentsSeverity
+	m.commentsTagFilter = s.commentsTag
+	m.commentEditing = false
+	m.explainAsking = false
+	m.reviewConfirm = false
+
+	m.updateDiffViewportContent()
+	m.diffView.SetYOffset(s.diffOffset)
+
+	m.invalidateCommentRows()
+	m.refreshCommentsTable()
+	m.commentsTable.SetCursor(s.commentsCursor)
+	m.scrollCommentsTable()
+	m.updateCommentsDetailContent(true)
+	m.commentsDetailView.SetYOffset(s.commentsOffset)
+}
+
+// sessionBusy reports whether background work is still bound to the active session.
+func (m Model) sessionBusy() bool {
+	if m.reviewRunning || m.publishRunning {
+		return true
+	}
+	return m.diffFiles == nil && m.diffErr == nil
+}
+
+func (m *Model) switchSession(delta int) error {
+	if len(m.sessions) < 2 {
+		return errors.New("no other review sessions; press ctrl+n to add one")
+	}
+	if m.sessionBusy() {
+		return errors.New("wait for the current diff/review/publish to finish before switching")
+	}
+	m.snapshotSession()
+	m.sessionIndex = (m.sessionIndex + delta + len(m.sessions)) % len(m.sessions)
+	m.restoreSession(m.sessions[m.sessionIndex])
+	return nil
+}
+
+// startNewSession reopens the wizard at the diff source step; the current session is kept.
+func (m *Model) startNewSession() error {
+	if m.sessionBusy() {
+		return errors.New("wait for the current diff/review/publish to finish before adding a session")
+	}
+	m.snapshotSession()
+	m.inWizard = true
+	m.enterSourceStep()
+	return nil
+}
+
+// cancelNewSession leaves the wizard and returns to the session that was active before ctrl+n.
+func (m *Model) cancelNewSession() {
+	m.inWizard = false
+	m.branchFilterInput.Blur()
+	if m.sessionIndex >= 0 && m.sessionIndex < len(m.sessions) {
+		m.diffSource = m.sessions[m.sessionIndex].source
+		m.baseBranch = m.sessions[m.sessionIndex].baseBranch
+		m.branch = m.sessions[m.sessionIndex].branch
+	}
+}
+
+func (m *Model) updateSessionKeys(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "ctrl+n":
+		m.sessionErr = m.startNewSession()
+		return true
+	case "ctrl+t":
+		m.sessionErr = m.switchSession(1)
+		return true
+	}
+	return false
+}
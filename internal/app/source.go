@@ -0,0 +1,381 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/git"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/review"
+)
+
+// diffSource selects which git diff the review is built from.
+type diffSource string
+
+const (
+	sourceBranches    diffSource = "branches"
+	sourceWorkingTree diffSource = "working-tree"
+	sourceStaged      diffSource = "staged"
+	// sourcePatch reviews a unified diff read from a file; the patch path takes the branch field's
+	// place and no git repository is needed.
+	sourcePatch diffSource = "patch"
+	// sourceRange reviews a commit range or a single commit (see git.ParseRevisionRange); like
+	// the patch path, the range takes the branch field's place.
+	sourceRange diffSource = "range"
+)
+
+type diffSourceOption struct {
+	source diffSource
+	label  string
+}
+
+var diffSourceOptions = []diffSourceOption{
+	{sourceBranches, "Compare branches (base...branch)"},
+	{sourceWorkingTree, "Working tree (uncommitted changes vs HEAD)"},
+	{sourceStaged, "Staged changes only (git diff --cached)"},
+	{sourceRange, "Commit range or single commit (A..B, A...B or SHA)"},
+	{sourcePatch, "Patch file (unified diff)"},
+}
+
+func parseDiffSource(value string) diffSource {
+	for _, option := range diffSourceOptions {
+		if string(option.source) == value {
+			return option.source
+		}
+	}
+	return sourceBranches
+}
+
+func (s diffSource) usesBranches() bool {
+	return s == sourceBranches || s == ""
+}
+
+// usesInput reports whether the wizard asks for a path or range for this source.
+func (s diffSource) usesInput() bool {
+	return s == sourcePatch || s == sourceRange
+}
+
+func (s diffSource) describe(base, branch string) string {
+	switch s {
+	case sourceWorkingTree:
+		return "working tree"
+	case sourceStaged:
+		return "staged changes"
+	case sourcePatch:
+		return "patch " + filepath.Base(branch)
+	case sourceRange:
+		if !strings.Contains(branch, "..") {
+			return "commit " + branch
+		}
+		return branch
+	default:
+		return fmt.Sprintf("%s...%s", base, branch)
+	}
+}
+
+func generateSourceDiff(source diffSource, repoRoot, baseBranch, branch string, opts git.DiffOptions) (string, error) {
+	switch source {
+	case sourceWorkingTree:
+		return git.GenerateWorkingTreeDiff(repoRoot, opts)
+	case sourceStaged:
+		return git.GenerateStagedDiff(repoRoot, opts)
+	case sourcePatch:
+		return git.ReadPatch(branch)
+	case sourceRange:
+		revisions, err := git.ParseRevisionRange(branch)
+		if err != nil {
+			return "", err
+		}
+		return git.GenerateRangeDiff(repoRoot, revisions.String(), opts)
+	default:
+		return git.GenerateDiff(repoRoot, baseBranch, branch, opts)
+	}
+}
+
+// sourceRefs lists the refs a source diffs, for fetching remote ones first; sources that don't
+// compare commits have none.
+func sourceRefs(source diffSource, baseBranch, branch string) []string {
+	switch {
+	case source.usesBranches():
+		return []string{baseBranch, branch}
+	case source == sourceRange:
+		if revisions, err := git.ParseRevisionRange(branch); err == nil {
+			return []string{revisions.Base, revisions.Head}
+		}
+	}
+	return nil
+}
+
+// sourceFileReader reads full files as of the revision the source reviews: the branch tip, the
+// range's head, the index for staged changes, or the working tree. A patch's revision is unknown,
+// so it has none.
+func sourceFileReader(source diffSource, repoRoot, branch string) review.FileContentReader {
+	if source == sourcePatch {
+		return nil
+	}
+	if source == sourceRange {
+		if revisions, err := git.ParseRevisionRange(branch); err == nil {
+			branch = revisions.Head
+		}
+	}
+	return func(path string) (string, error) {
+		switch source {
+		case sourceWorkingTree:
+			data, err := os.ReadFile(filepath.Join(repoRoot, filepath.FromSlash(path)))
+			return string(data), err
+		case sourceStaged:
+			return git.ShowFile(repoRoot, "", path)
+		default:
+			return git.ShowFile(repoRoot, branch, path)
+		}
+	}
+}
+
+var diffModeOptions = []struct {
+	mode  git.DiffMode
+	label string
+}{
+	{git.DiffModeMergeBase, "Changes on branch since it forked (base...branch)"},
+	{git.DiffModeDirect, "Everything that differs between the tips (base..branch)"},
+}
+
+// describeDiffMode explains what a reviewer is looking at for the given mode.
+func describeDiffMode(mode git.DiffMode) string {
+	if mode == git.DiffModeDirect {
+		return "direct (base..branch): includes base-side changes made since the branch forked, shown reversed"
+	}
+	return "merge-base (base...branch): only the changes introduced on the review branch"
+}
+
+func (m *Model) enterDiffModeStep() {
+	m.wizardStep = wizardDiffMode
+	m.cursor = 0
+	current := m.diffOptions().Mode
+	for i, option := range diffModeOptions {
+		if option.mode == current {
+			m.cursor = i
+		}
+	}
+	m.branchFilterInput.Blur()
+}
+
+func (m Model) updateDiffModeStep(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		m.cursor = clamp(m.cursor-1, 0, len(diffModeOptions)-1)
+	case "down", "j":
+		m.cursor = clamp(m.cursor+1, 0, len(diffModeOptions)-1)
+	case "b":
+		m.wizardStep = wizardBranch
+		m.cursor = m.initialBranchIndex(m.branch)
+		m.branchFilterInput.SetValue("")
+		m.branchFilterInput.SetCursor(0)
+		m.branchFilterInput.Focus()
+	case "f":
+		m.cfg.FetchRemote = !m.cfg.FetchRemote
+	case "enter":
+		m.cfg.DiffMode = string(diffModeOptions[clamp(m.cursor, 0, len(diffModeOptions)-1)].mode)
+		m.enterModelStep()
+	}
+	return m, nil
+}
+
+func (m Model) renderDiffModePicker() string {
+	header := lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("Compare %s with %s", m.branch, m.baseBranch))
+	lines := make([]string, 0, len(diffModeOptions))
+	for i, option := range diffModeOptions {
+		cursor := "  "
+		if i == m.cursor {
+			cursor = "> "
+		}
+		lines = append(lines, cursor+option.label)
+	}
+	fetch := "off"
+	if m.cfg.FetchRemote {
+		fetch = "on"
+	}
+	hint := "Use ↑/↓, Enter to select, f to toggle fetching, b to go back."
+	return lipgloss.JoinVertical(lipgloss.Top, header, strings.Join(lines, "\n"), "",
+		fmt.Sprintf("Fetch remote refs (origin/...) before diffing: %s", fetch), "", hint)
+}
+
+// sourceOptions lists the sources the wizard offers; outside a git repository only a patch file
+// can be reviewed.
+func (m Model) sourceOptions() []diffSourceOption {
+	if !m.noRepo {
+		return diffSourceOptions
+	}
+	for _, option := range diffSourceOptions {
+		if option.source == sourcePatch {
+			return []diffSourceOption{option}
+		}
+	}
+	return nil
+}
+
+func (m Model) initialSourceIndex(source diffSource) int {
+	for i, option := range m.sourceOptions() {
+		if option.source == source {
+			return i
+		}
+	}
+	return 0
+}
+
+func (m *Model) enterSourceStep() {
+	m.wizardStep = wizardSource
+	m.cursor = m.initialSourceIndex(parseDiffSource(m.cfg.LastSource))
+}
+
+func (m *Model) enterBaseBranchStep() {
+	m.wizardStep = wizardBaseBranch
+	m.cursor = m.initialBranchIndex(m.cfg.LastBase)
+	m.branchFilterInput.SetValue("")
+	m.branchFilterInput.SetCursor(0)
+	m.branchFilterInput.Focus()
+}
+
+func (m *Model) enterModelStep() {
+	m.wizardStep = wizardModel
+	m.modelCursor = m.initialModelIndex(m.cfg.LastModel)
+	m.branchFilterInput.Blur()
+}
+
+func (m Model) updateSourceStep(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	options := m.sourceOptions()
+	switch msg.String() {
+	case "up", "k":
+		m.cursor = clamp(m.cursor-1, 0, len(options)-1)
+	case "down", "j":
+		m.cursor = clamp(m.cursor+1, 0, len(options)-1)
+	case "esc":
+		if len(m.sessions) > 0 {
+			m.cancelNewSession()
+		}
+	case "enter":
+		m.diffSource = options[clamp(m.cursor, 0, len(options)-1)].source
+		m.cfg.LastSource = string(m.diffSource)
+		if m.diffSource.usesInput() {
+			m.enterSourceInputStep()
+			return m, nil
+		}
+		if m.diffSource.usesBranches() {
+			m.enterBaseBranchStep()
+			return m, nil
+		}
+		m.baseBranch = "HEAD"
+		m.branch = ""
+		m.enterModelStep()
+	}
+	return m, nil
+}
+
+func (m Model) renderSourcePicker() string {
+	header := lipgloss.NewStyle().Bold(true).Render("What do you want to review?")
+	options := m.sourceOptions()
+	lines := make([]string, 0, len(options))
+	for i, option := range options {
+		cursor := "  "
+		if i == m.cursor {
+			cursor = "> "
+		}
+		lines = append(lines, cursor+option.label)
+	}
+	hint := "Use ↑/↓, Enter to select."
+	if len(m.sessions) > 0 {
+		hint = "Use ↑/↓, Enter to select, Esc to cancel."
+	}
+	return lipgloss.JoinVertical(lipgloss.Top, header, strings.Join(lines, "\n"), "", hint)
+}
+
+// sourceInput is the text input asked for by sources that take an argument (usesInput).
+func (m *Model) sourceInput() *textinput.Model {
+	if m.diffSource == sourceRange {
+		return &m.rangeInput
+	}
+	return &m.patchInput
+}
+
+func (m *Model) enterSourceInputStep() {
+	m.wizardStep = wizardSourceInput
+	m.sourceInputErr = nil
+	m.sourceInput().Focus()
+}
+
+// resolveSourceInput validates what was typed for the source: a patch must be a readable file
+// (kept as an absolute path) and both ends of a range must resolve to commits.
+func (m Model) resolveSourceInput(value string) (string, error) {
+	if m.diffSource == sourceRange {
+		revisions, err := git.ParseRevisionRange(value)
+		if err != nil {
+			return "", err
+		}
+		for _, ref := range []string{revisions.Base, revisions.Head} {
+			if err := git.VerifyRef(m.repoRoot, ref); err != nil {
+				return "", err
+			}
+		}
+		return value, nil
+	}
+	path, err := filepath.Abs(value)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("%s is a directory", path)
+	}
+	return path, nil
+}
+
+func (m Model) updateSourceInputStep(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	input := m.sourceInput()
+	switch msg.String() {
+	case "esc":
+		input.Blur()
+		m.enterSourceStep()
+		return m, nil
+	case "enter":
+		value := strings.TrimSpace(input.Value())
+		if value == "" {
+			return m, nil
+		}
+		resolved, err := m.resolveSourceInput(value)
+		if err != nil {
+			m.sourceInputErr = err
+			return m, nil
+		}
+		m.sourceInputErr = nil
+		input.Blur()
+		m.baseBranch = ""
+		m.branch = resolved
+		m.enterModelStep()
+		return m, nil
+	}
+	var cmd tea.Cmd
+	*input, cmd = input.Update(msg)
+	return m, cmd
+}
+
+func (m Model) renderSourceInput() string {
+	title := "Patch file to review"
+	hint := "A unified diff such as git diff or diff -u output. Enter to continue, Esc to go back."
+	if m.diffSource == sourceRange {
+		title = "Commit range to review"
+		hint = "A..B, A...B (since the merge-base) or a single commit SHA. Enter to continue, Esc to go back."
+	}
+	header := lipgloss.NewStyle().Bold(true).Render(title)
+	body := m.sourceInput().View()
+	if m.sourceInputErr != nil {
+		hint = fmt.Sprintf("Error: %s\n%s", m.sourceInputErr, hint)
+	}
+	return lipgloss.JoinVertical(lipgloss.Top, header, body, "", hint)
+}
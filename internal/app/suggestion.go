@@ -0,0 +1,126 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/git"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/review"
+)
+
+// suggestionPatchName is the file, in the repository root, a suggestion's patch is written to.
+const suggestionPatchName = "review-suggestion.patch"
+
+type suggestionPatchMsg struct {
+	patch string
+	file  string
+	err   error
+}
+
+type suggestionSavedMsg struct {
+	// applied is set when the patch went into the working tree rather than to suggestionPatchName.
+	applied bool
+	path    string
+	err     error
+}
+
+// suggestionPatchCmd builds the patch for comment's suggestion against the file as reviewed.
+func suggestionPatchCmd(comment review.Comment, file git.DiffFile, content review.FileContentReader) tea.Cmd {
+	return func() tea.Msg {
+		text, err := content(comment.FilePath)
+		if err != nil {
+			return suggestionPatchMsg{err: fmt.Errorf("read %s: %w", comment.FilePath, err)}
+		}
+		patch, err := review.SuggestionPatch(comment, file, text)
+		return suggestionPatchMsg{patch: patch, file: comment.FilePath, err: err}
+	}
+}
+
+// prepareSuggestionPatch starts building a patch for the selected comment's suggestion; the patch
+// is shown for confirmation (see updateSuggestionConfirm) before anything is written.
+func (m *Model) prepareSuggestionPatch() tea.Cmd {
+	index, ok := m.selectedCommentIndex()
+	if !ok {
+		return nil
+	}
+	comment := m.reviewResult.Comments[index]
+	if _, ok := review.SuggestionCode(comment); !ok {
+		m.statusErr = errors.New("the selected comment has no suggested code")
+		return nil
+	}
+	content := sourceFileReader(m.diffSource, m.repoRoot, m.branch)
+	if content == nil {
+		m.statusErr = errors.New("suggestions can't be applied when reviewing a patch file")
+		return nil
+	}
+	for _, file := range m.diffFiles {
+		if file.Path == comment.FilePath {
+			return suggestionPatchCmd(comment, file, content)
+		}
+	}
+	m.statusErr = fmt.Errorf("%s is not in the current diff", comment.FilePath)
+	return nil
+}
+
+func (m *Model) updateSuggestionConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	patch := m.suggestionPatch
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "w":
+		m.suggestionPatch = ""
+		return m, writeSuggestionPatchCmd(filepath.Join(m.repoRoot, suggestionPatchName), patch)
+	case "a":
+		m.suggestionPatch = ""
+		return m, applySuggestionPatchCmd(m.repoRoot, m.suggestionFile, patch)
+	case "esc", "n":
+		m.suggestionPatch = ""
+		m.statusMessage = "suggestion not applied"
+	}
+	return m, nil
+}
+
+func writeSuggestionPatchCmd(path, patch string) tea.Cmd {
+	return func() tea.Msg {
+		return suggestionSavedMsg{path: path, err: os.WriteFile(path, []byte(patch), 0o644)}
+	}
+}
+
+func applySuggestionPatchCmd(repoRoot, file, patch string) tea.Cmd {
+	return func() tea.Msg {
+		return suggestionSavedMsg{applied: true, path: file, err: git.ApplyPatch(repoRoot, patch)}
+	}
+}
+
+func (m Model) renderSuggestionConfirm() string {
+	title := lipgloss.NewStyle().Bold(true).Foreground(theme.Warning).Render("Apply suggestion to " + m.suggestionFile)
+	lines := strings.Split(strings.TrimSuffix(m.suggestionPatch, "\n"), "\n")
+	if limit := max(m.height-12, 5); len(lines) > limit {
+		lines = append(lines[:limit], fmt.Sprintf("... %d more line(s)", len(lines)-limit))
+	}
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		case strings.HasPrefix(line, "@@"):
+			lines[i] = diffHunkStyle.Render(line)
+		case strings.HasPrefix(line, "+"):
+			lines[i] = diffAddStyle.Render(line)
+		case strings.HasPrefix(line, "-"):
+			lines[i] = diffDelStyle.Render(line)
+		}
+	}
+	return lipgloss.JoinVertical(lipgloss.Left,
+		"",
+		title,
+		"",
+		strings.Join(lines, "\n"),
+		"",
+		fmt.Sprintf("a apply to the working tree (git apply) • w write %s • esc cancel", suggestionPatchName),
+	)
+}
@@ -0,0 +1,155 @@
+package app
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/config"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/review"
+)
+
+// colorTheme is the set of colors the TUI draws with; see darkTheme and lightTheme.
+type colorTheme struct {
+	// Accent marks focus: the focused pane border and the help overlay border.
+	Accent  lipgloss.Color
+	Error   lipgloss.Color
+	Success lipgloss.Color
+	Warning lipgloss.Color
+	// Muted is for hints, ignored files and other secondary text.
+	Muted lipgloss.Color
+
+	Hunk    lipgloss.Color
+	AddTint lipgloss.Color
+	DelTint lipgloss.Color
+
+	BarText     lipgloss.Color
+	BarSurface  lipgloss.Color
+	Badge       lipgloss.Color
+	BadgeText   lipgloss.Color
+	HelpSurface lipgloss.Color
+
+	// Blocker, Issue, Suggestion and Nit color the severity of each comment.
+	Blocker    lipgloss.Color
+	Issue      lipgloss.Color
+	Suggestion lipgloss.Color
+	Nit        lipgloss.Color
+}
+
+var darkTheme = colorTheme{
+	Accent:      "62",
+	Error:       "9",
+	Success:     "10",
+	Warning:     "214",
+	Muted:       "241",
+	Hunk:        "6",
+	AddTint:     "22",
+	DelTint:     "52",
+	BarText:     "#C1C1C1",
+	BarSurface:  "#353535",
+	Badge:       "#6124DF",
+	BadgeText:   "#FFFFFF",
+	HelpSurface: "#1A1A1A",
+	Blocker:     "9",
+	Issue:       "208",
+	Suggestion:  "11",
+	Nit:         "245",
+}
+
+var lightTheme = colorTheme{
+	Accent:      "57",
+	Error:       "160",
+	Success:     "28",
+	Warning:     "166",
+	Muted:       "244",
+	Hunk:        "25",
+	AddTint:     "194",
+	DelTint:     "224",
+	BarText:     "#353535",
+	BarSurface:  "#DADADA",
+	Badge:       "#6124DF",
+	BadgeText:   "#FFFFFF",
+	HelpSurface: "#F5F5F5",
+	Blocker:     "160",
+	Issue:       "166",
+	Suggestion:  "136",
+	Nit:         "243",
+}
+
+// themeNames are the presets the theme config key accepts.
+var themeNames = []string{"dark", "light"}
+
+// theme is the active color theme; applyTheme replaces it once the config is loaded.
+var theme = darkTheme
+
+// themeColorPattern matches #RRGGBB or an ANSI 256-color number (0-255).
+var themeColorPattern = regexp.MustCompile(`^(#[0-9a-fA-F]{6}|25[0-5]|2[0-4][0-9]|1[0-9]{2}|[1-9]?[0-9])$`)
+
+// loadTheme returns the preset named by cfg.Theme with cfg.ThemeColors applied over it.
+func loadTheme(cfg config.Config) (colorTheme, error) {
+	selected := darkTheme
+	switch name := strings.ToLower(strings.TrimSpace(cfg.Theme)); name {
+	case "", "dark":
+	case "light":
+		selected = lightTheme
+	default:
+		return darkTheme, fmt.Errorf("config theme %q: want one of %s", cfg.Theme, strings.Join(themeNames, ", "))
+	}
+	roles := map[string]*lipgloss.Color{
+		"accent":     &selected.Accent,
+		"error":      &selected.Error,
+		"success":    &selected.Success,
+		"warning":    &selected.Warning,
+		"muted":      &selected.Muted,
+		"blocker":    &selected.Blocker,
+		"issue":      &selected.Issue,
+		"suggestion": &selected.Suggestion,
+		"nit":        &selected.Nit,
+	}
+	for role, value := range cfg.ThemeColors {
+		slot, ok := roles[strings.ToLower(role)]
+		if !ok {
+			known := make([]string, 0, len(roles))
+			for name := range roles {
+				known = append(known, name)
+			}
+			slices.Sort(known)
+			return darkTheme, fmt.Errorf("config themeColors: unknown role %q (want %s)", role, strings.Join(known, ", "))
+		}
+		value = strings.TrimSpace(value)
+		if !themeColorPattern.MatchString(value) {
+			return darkTheme, fmt.Errorf("config themeColors.%s: %q is not an ANSI number (0-255) or #RRGGBB", role, value)
+		}
+		*slot = lipgloss.Color(value)
+	}
+	return selected, nil
+}
+
+// severity is the color for sev; ok is false for an unknown severity.
+func (t colorTheme) severity(sev review.Severity) (lipgloss.Color, bool) {
+	switch sev {
+	case review.SeverityBlocker:
+		return t.Blocker, true
+	case review.SeverityIssue:
+		return t.Issue, true
+	case review.SeveritySuggestion:
+		return t.Suggestion, true
+	case review.SeverityNit:
+		return t.Nit, true
+	}
+	return "", false
+}
+
+// applyTheme makes t the active theme, including the package-level diff styles.
+func applyTheme(t colorTheme) {
+	theme = t
+	diffAddStyle = lipgloss.NewStyle().Foreground(t.Success)
+	diffDelStyle = lipgloss.NewStyle().Foreground(t.Error)
+	diffHunkStyle = lipgloss.NewStyle().Foreground(t.Hunk)
+	gutterStyle = lipgloss.NewStyle().Foreground(t.Muted)
+	diffAddTint = t.AddTint
+	diffDelTint = t.DelTint
+}
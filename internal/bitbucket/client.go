@@ -4,67 +4,244 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/git"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/publish"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/review"
+)
+
+// defaultBaseURL is the Bitbucket Cloud REST API root.
+const defaultBaseURL = "https://api.bitbucket.org/2.0"
+
+var (
+	_ publish.InlinePublisher    = (*Client)(nil)
+	_ publish.SummaryUpdater     = (*Client)(nil)
+	_ publish.CredentialVerifier = (*Client)(nil)
 )
 
 type Client struct {
-	config Config
-	http   *http.Client
+	config  Config
+	baseURL string
+	http    *http.Client
 }
 
+// NewClient talks to Bitbucket Cloud unless cfg selects FlavorServer, whose REST API lives under
+// cfg.BaseURL (e.g. https://bitbucket.example.com).
 func NewClient(cfg Config) *Client {
+	baseURL := strings.TrimRight(cfg.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
 	return &Client{
-		config: cfg,
+		config:  cfg,
+		baseURL: baseURL,
 		http: &http.Client{
 			Timeout: 30 * time.Second,
 		},
 	}
 }
 
+// PublishSummary implements publish.Publisher.
+func (c *Client) PublishSummary(ctx context.Context, markdown string) (string, error) {
+	return c.PublishComment(ctx, markdown)
+}
+
+// PublishComment posts markdown as a general pull request comment.
 func (c *Client) PublishComment(ctx context.Context, markdown string) (string, error) {
-	url := fmt.Sprintf("https://api.bitbucket.org/2.0/repositories/%s/%s/pullrequests/%d/comments",
-		c.config.Workspace, c.config.RepoSlug, c.config.PullRequest)
+	if c.server() {
+		return c.sendComment(ctx, "POST", c.commentsURL(), ServerCommentPayload{Text: markdown})
+	}
+	return c.sendComment(ctx, "POST", c.commentsURL(), CommentPayload{
+		Content: Content{
+			Raw: markdown,
+		},
+	})
+}
 
-	payload := CommentPayload{
+// UpdateSummary replaces the body of an existing pull request comment. Bitbucket Server needs the
+// comment's current version, so it is fetched first.
+func (c *Client) UpdateSummary(ctx context.Context, id, markdown string) (string, error) {
+	url := c.commentsURL() + "/" + id
+	var payload any = CommentPayload{
 		Content: Content{
 			Raw: markdown,
 		},
 	}
+	if c.server() {
+		var current struct {
+			Version int `json:"version"`
+		}
+		if err := c.do(ctx, "GET", url, nil, &current); err != nil {
+			return "", publish.AsNotFound(err)
+		}
+		payload = ServerCommentPayload{Text: markdown, Version: &current.Version}
+	}
+	if _, err := c.sendComment(ctx, "PUT", url, payload); err != nil {
+		return "", publish.AsNotFound(err)
+	}
+	return id, nil
+}
+
+// PublishInlineComments posts each comment selected for publishing on its file and start line, one
+// request per comment. Results are in the order of the published comments; the returned error
+// joins every failure. It stops early when ctx is cancelled.
+func (c *Client) PublishInlineComments(ctx context.Context, comments []review.Comment) ([]publish.Result, error) {
+	var results []publish.Result
+	var errs []error
+	for _, comment := range comments {
+		if !comment.Publish {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return results, errors.Join(append(errs, err)...)
+		}
+		// Comments are anchored on their start line only, so a suggestion block (which replaces the
+		// anchored line) is only used for single-line comments.
+		body := review.RenderInlineSuggestionComment(comment, comment.StartLine, comment.StartLine)
+		var payload any = CommentPayload{
+			Content: Content{Raw: body},
+			Inline:  &Inline{Path: comment.FilePath, To: comment.StartLine},
+		}
+		if c.server() {
+			payload = ServerCommentPayload{
+				Text: body,
+				Anchor: &ServerAnchor{
+					Path:     comment.FilePath,
+					Line:     comment.StartLine,
+					LineType: c.serverLineType(comment.FilePath, comment.StartLine),
+					FileType: "TO",
+				},
+			}
+		}
+		id, err := c.sendComment(ctx, "POST", c.commentsURL(), payload)
+		if err != nil {
+			err = fmt.Errorf("%s:%d: %w", comment.FilePath, comment.StartLine, err)
+			errs = append(errs, err)
+		}
+		results = append(results, publish.Result{CommentID: id, Error: err})
+	}
+	return results, errors.Join(errs...)
+}
+
+// VerifyCredentials fetches the pull request to check the token, workspace, repository and pull
+// request ID before anything is posted.
+func (c *Client) VerifyCredentials(ctx context.Context) error {
+	var pull struct {
+		ID int `json:"id"`
+	}
+	err := c.do(ctx, "GET", c.pullRequestURL(), nil, &pull)
+	var statusErr *publish.StatusError
+	if errors.As(err, &statusErr) {
+		switch statusErr.Code {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return fmt.Errorf("token rejected (%s); check the token and its repository permissions", statusErr.Status)
+		case http.StatusNotFound:
+			return fmt.Errorf("pull request #%d not found in %s/%s", c.config.PullRequest, c.config.Workspace, c.config.RepoSlug)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if pull.ID != c.config.PullRequest {
+		return fmt.Errorf("expected pull request #%d, got #%d", c.config.PullRequest, pull.ID)
+	}
+	return nil
+}
+
+// serverLineType is the Server anchor type of line on the new side of path: CONTEXT for an
+// unchanged line of the reviewed diff, otherwise ADDED.
+func (c *Client) serverLineType(path string, line int) string {
+	for _, file := range c.config.Diff {
+		if file.Path != path {
+			continue
+		}
+		for _, hunk := range file.Hunks {
+			for _, diffLine := range hunk.Lines {
+				if diffLine.NewLine == line && diffLine.Kind == git.DiffLineContext {
+					return serverLineContext
+				}
+			}
+		}
+	}
+	return serverLineAdded
+}
 
-	data, err := json.Marshal(payload)
+func (c *Client) server() bool {
+	return c.config.Flavor == FlavorServer
+}
+
+// pullRequestURL is the pull request resource; Workspace holds the project key on Server.
+func (c *Client) pullRequestURL() string {
+	if c.server() {
+		return fmt.Sprintf("%s/rest/api/1.0/projects/%s/repos/%s/pull-requests/%d",
+			c.baseURL, c.config.Workspace, c.config.RepoSlug, c.config.PullRequest)
+	}
+	return fmt.Sprintf("%s/repositories/%s/%s/pullrequests/%d",
+		c.baseURL, c.config.Workspace, c.config.RepoSlug, c.config.PullRequest)
+}
+
+// commentsURL is the pull request comments collection.
+func (c *Client) commentsURL() string {
+	return c.pullRequestURL() + "/comments"
+}
+
+func (c *Client) sendComment(ctx context.Context, method, url string, payload any) (string, error) {
+	var result struct {
+		ID int `json:"id"`
+	}
+	err := c.do(ctx, method, url, payload, &result)
+	if errors.Is(err, errUndecodable) || (err == nil && result.ID == 0) {
+		// Posted, but the ID is unknown; an empty ID is never stored for a later update.
+		return "", nil
+	}
 	if err != nil {
-		return "", fmt.Errorf("marshal payload: %w", err)
+		return "", err
 	}
+	return fmt.Sprintf("%d", result.ID), nil
+}
+
+// errUndecodable is returned by do when the request succeeded but the response body could not be
+// decoded into out.
+var errUndecodable = errors.New("undecodable response")
 
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(data))
+// do sends payload (nil for none) as JSON and decodes a successful response into out.
+func (c *Client) do(ctx context.Context, method, url string, payload, out any) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("marshal payload: %w", err)
+		}
+		body = bytes.NewBuffer(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
-		return "", fmt.Errorf("create request: %w", err)
+		return fmt.Errorf("create request: %w", err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.config.Token))
 
 	resp, err := c.http.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("do request: %w", err)
+		return fmt.Errorf("do request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("unexpected status %s: %s", resp.Status, string(body))
+		return publish.NewStatusError(resp)
 	}
-
-	var result struct {
-		ID int `json:"id"`
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%w: %v", errUndecodable, err)
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		// Bitbucket might return a string ID or something else, but we just want to know it succeeded
-		return "success", nil
-	}
-
-	return fmt.Sprintf("%d", result.ID), nil
+	return nil
 }
@@ -0,0 +1,198 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/git"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/publish"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/review"
+)
+
+func TestPublishInlineComments_whenSomeDeselected_shouldPostOnlyPublishedWithInlineAnchor(t *testing.T) {
+	// arrange
+	var got []CommentPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload CommentPayload
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		got = append(got, payload)
+		fmt.Fprintf(w, `{"id":%d}`, len(got))
+	}))
+	defer server.Close()
+	client := NewClient(Config{Workspace: "acme", RepoSlug: "repo", PullRequest: 7, Token: "t"})
+	client.baseURL = server.URL
+
+	// act
+	results, err := client.PublishInlineComments(context.Background(), []review.Comment{
+		{FilePath: "a.go", StartLine: 12, EndLine: 14, Severity: review.SeverityIssue, Title: "Leak", Body: "close it", Publish: true},
+		{FilePath: "b.go", StartLine: 3, EndLine: 3, Severity: review.SeverityNit, Title: "Typo", Body: "spelling", Publish: false},
+	})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(results) != 1 || results[0].CommentID != "1" {
+		t.Fatalf("expected one published result, got %+v", results)
+	}
+	if len(got) != 1 || got[0].Inline == nil || *got[0].Inline != (Inline{Path: "a.go", To: 12}) {
+		t.Fatalf("expected one inline payload anchored at a.go:12, got %+v", got)
+	}
+}
+
+func TestPublishComment_whenContextCancelled_shouldReturnContextError(t *testing.T) {
+	// arrange
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"id":1}`)
+	}))
+	defer server.Close()
+	client := NewClient(Config{Workspace: "acme", RepoSlug: "repo", PullRequest: 7, Token: "t"})
+	client.baseURL = server.URL
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// act
+	_, err := client.PublishComment(ctx, "summary")
+
+	// assert
+	if !errors.Is(err, context.Canceled) || requests != 0 {
+		t.Fatalf("expected context.Canceled without a request, got err=%v requests=%d", err, requests)
+	}
+}
+
+func TestUpdateSummary_whenCommentDeleted_shouldReturnErrCommentNotFound(t *testing.T) {
+	// arrange
+	var gotMethod, gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath = r.Method, r.URL.Path
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+	client := NewClient(Config{Workspace: "acme", RepoSlug: "repo", PullRequest: 7, Token: "t"})
+	client.baseURL = server.URL
+
+	// act
+	_, err := client.UpdateSummary(context.Background(), "99", "summary")
+
+	// assert
+	if !errors.Is(err, publish.ErrCommentNotFound) {
+		t.Fatalf("expected ErrCommentNotFound, got %v", err)
+	}
+	if gotMethod != http.MethodPut || gotPath != "/repositories/acme/repo/pullrequests/7/comments/99" {
+		t.Fatalf("unexpected request: %s %s", gotMethod, gotPath)
+	}
+}
+
+func TestPublishComment_whenServerFlavor_shouldUseRestAPIPathAndTextPayload(t *testing.T) {
+	// arrange
+	var gotPath string
+	var got ServerCommentPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		fmt.Fprint(w, `{"id":5,"version":0}`)
+	}))
+	defer server.Close()
+	client := NewClient(Config{Workspace: "PROJ", RepoSlug: "repo", PullRequest: 3, Token: "t", BaseURL: server.URL + "/", Flavor: FlavorServer})
+
+	// act
+	id, err := client.PublishComment(context.Background(), "summary")
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if id != "5" || gotPath != "/rest/api/1.0/projects/PROJ/repos/repo/pull-requests/3/comments" || got.Text != "summary" {
+		t.Fatalf("unexpected request: id=%q path=%q payload=%+v", id, gotPath, got)
+	}
+}
+
+func TestPublishInlineComments_whenServerCommentStartsOnContextLine_shouldAnchorAsContext(t *testing.T) {
+	// arrange
+	var got []ServerCommentPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload ServerCommentPayload
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		got = append(got, payload)
+		fmt.Fprintf(w, `{"id":%d}`, len(got))
+	}))
+	defer server.Close()
+	diff := []git.DiffFile{{Path: "a.go", Hunks: []git.DiffHunk{{NewStart: 10, NewLines: 2, Lines: []git.DiffLine{
+		{Kind: git.DiffLineContext, OldLine: 10, NewLine: 10, Text: "func f() {"},
+		{Kind: git.DiffLineAdd, NewLine: 11, Text: "\treturn"},
+	}}}}}
+	client := NewClient(Config{Workspace: "PROJ", RepoSlug: "repo", PullRequest: 3, Token: "t", BaseURL: server.URL, Flavor: FlavorServer, Diff: diff})
+
+	// act
+	_, err := client.PublishInlineComments(context.Background(), []review.Comment{
+		{FilePath: "a.go", StartLine: 10, EndLine: 11, Severity: review.SeverityIssue, Title: "Context", Body: "b", Publish: true},
+		{FilePath: "a.go", StartLine: 11, EndLine: 11, Severity: review.SeverityIssue, Title: "Added", Body: "b", Publish: true},
+	})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(got) != 2 || got[0].Anchor.LineType != "CONTEXT" || got[1].Anchor.LineType != "ADDED" {
+		t.Fatalf("expected CONTEXT then ADDED anchors, got %+v", got)
+	}
+}
+
+func TestVerifyCredentials_whenPullRequestMissing_shouldReportNotFound(t *testing.T) {
+	// arrange
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		if r.URL.Path == "/repositories/acme/repo/pullrequests/7" {
+			fmt.Fprint(w, `{"id":7}`)
+			return
+		}
+		http.Error(w, `{"type":"error"}`, http.StatusNotFound)
+	}))
+	defer server.Close()
+	found := NewClient(Config{Workspace: "acme", RepoSlug: "repo", PullRequest: 7, Token: "t"})
+	found.baseURL = server.URL
+	missing := NewClient(Config{Workspace: "acme", RepoSlug: "repo", PullRequest: 8, Token: "t"})
+	missing.baseURL = server.URL
+
+	// act
+	foundErr := found.VerifyCredentials(context.Background())
+	missingErr := missing.VerifyCredentials(context.Background())
+
+	// assert
+	if foundErr != nil {
+		t.Fatalf("expected the existing pull request to verify, got %v", foundErr)
+	}
+	if missingErr == nil || missingErr.Error() != "pull request #8 not found in acme/repo" {
+		t.Fatalf("expected a not found error, got %v", missingErr)
+	}
+	if len(paths) != 2 || paths[0] != "GET /repositories/acme/repo/pullrequests/7" {
+		t.Fatalf("expected one GET per check, got %v", paths)
+	}
+}
+
+func TestPublishComment_whenResponseHasNoID_shouldReturnEmptyID(t *testing.T) {
+	// arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, "created")
+	}))
+	defer server.Close()
+	client := NewClient(Config{Workspace: "acme", RepoSlug: "repo", PullRequest: 7, Token: "t"})
+	client.baseURL = server.URL
+
+	// act
+	id, err := client.PublishComment(context.Background(), "summary")
+
+	// assert
+	if err != nil || id != "" {
+		t.Fatalf("expected an empty ID and no error, got %q, %v", id, err)
+	}
+}
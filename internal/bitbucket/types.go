@@ -1,27 +1,71 @@
 package bitbucket
 
-import "github.com/techitung-arunyawee/code-reviewer-2/internal/review"
+import (
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/git"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/review"
+)
+
+// APIFlavor selects between Bitbucket Cloud and self-hosted Bitbucket Server / Data Center.
+type APIFlavor string
+
+const (
+	FlavorCloud  APIFlavor = "cloud"
+	FlavorServer APIFlavor = "server"
+)
 
 type Config struct {
+	// Workspace is the Cloud workspace, or the project key on Server.
 	Workspace   string
 	RepoSlug    string
 	PullRequest int
 	Token       string
-}
-
-type PublishResult struct {
-	CommentID string
-	Error     error
+	// BaseURL overrides the API root; empty means Bitbucket Cloud. For Server it is the instance
+	// URL without /rest (e.g. https://bitbucket.example.com).
+	BaseURL string
+	// Flavor defaults to FlavorCloud.
+	Flavor APIFlavor
+	// Diff is the reviewed diff. Server anchors an inline comment as ADDED or CONTEXT depending on
+	// its start line; lines not found in Diff are sent as ADDED.
+	Diff []git.DiffFile
 }
 
 type CommentPayload struct {
 	Content Content `json:"content"`
+	Inline  *Inline `json:"inline,omitempty"`
+}
+
+// Inline anchors a comment to a line on the new side of the pull request diff.
+type Inline struct {
+	Path string `json:"path"`
+	To   int    `json:"to"`
 }
 
 type Content struct {
 	Raw string `json:"raw"`
 }
 
+// ServerCommentPayload is the Bitbucket Server / Data Center comment shape.
+type ServerCommentPayload struct {
+	Text   string        `json:"text"`
+	Anchor *ServerAnchor `json:"anchor,omitempty"`
+	// Version is required when editing a comment.
+	Version *int `json:"version,omitempty"`
+}
+
+// Server anchor line types for lines on the new side of the diff.
+const (
+	serverLineAdded   = "ADDED"
+	serverLineContext = "CONTEXT"
+)
+
+// ServerAnchor places a Server comment on a line of the pull request diff.
+type ServerAnchor struct {
+	Path     string `json:"path"`
+	Line     int    `json:"line"`
+	LineType string `json:"lineType"`
+	FileType string `json:"fileType"`
+}
+
 // Result is used to pass data to composer
 type Result struct {
 	Review review.Result
@@ -8,15 +8,99 @@ import (
 )
 
 type Config struct {
-	LastBranch    string   `json:"lastBranch,omitempty"`
-	LastBase      string   `json:"lastBase,omitempty"`
-	LastModel     string   `json:"lastModel,omitempty"`
+	LastSource string `json:"lastSource,omitempty"`
+	LastBranch string `json:"lastBranch,omitempty"`
+	LastBase   string `json:"lastBase,omitempty"`
+	// BranchSort orders the wizard's branch picker: "recent" (latest commit first, default) or "name".
+	BranchSort string `json:"branchSort,omitempty"`
+	// Models replaces the wizard's model list; "Custom..." is always offered after it.
+	Models    []string `json:"models,omitempty"`
+	LastModel string   `json:"lastModel,omitempty"`
+	// Provider selects the LLM backend: "openrouter" (default), "openai" or "anthropic". When it is
+	// empty, "claude-*" models select anthropic.
+	Provider      string   `json:"provider,omitempty"`
 	Guidelines    []string `json:"guidelines,omitempty"`
 	FreeGuideline string   `json:"freeGuideline,omitempty"`
 	// Publish settings
 	PublishWorkspace string `json:"publishWorkspace,omitempty"`
 	PublishRepoSlug  string `json:"publishRepoSlug,omitempty"`
 	PublishPRID      int    `json:"publishPRID,omitempty"`
+	// PublishProvider is the code host to publish to: bitbucket (default), github or gitlab. The
+	// repo slug and PR ID fields hold the repo (GitHub) or project path (GitLab) and PR/MR number.
+	PublishProvider string `json:"publishProvider,omitempty"`
+	// PublishBitbucketURL points Bitbucket publishing at a self-hosted Bitbucket Server / Data
+	// Center instance (e.g. https://bitbucket.example.com); empty means Bitbucket Cloud. The
+	// workspace field then holds the project key.
+	PublishBitbucketURL string `json:"publishBitbucketURL,omitempty"`
+	// PublishGitLabHost is the GitLab instance to publish to; empty means gitlab.com. It takes the
+	// workspace field's place while GitLab is selected.
+	PublishGitLabHost string `json:"publishGitLabHost,omitempty"`
+	// PublishedComments maps "provider:owner/repo#pr" to the summary comment posted there, so
+	// re-publishing updates it instead of adding another.
+	PublishedComments map[string]string `json:"publishedComments,omitempty"`
+	// PublishTemplate is a text/template file (relative to the repo root) that lays out the
+	// published summary comment instead of the built-in format; it is executed with the review result.
+	PublishTemplate string `json:"publishTemplate,omitempty"`
+	// PublishInline posts each comment on its diff line, with a summary comment for the verdict and
+	// anything that could not be placed inline.
+	PublishInline bool `json:"publishInline,omitempty"`
+	// AllowBlockerOverride lets a manual verdict override flip NO_GO to GO even when blockers exist.
+	AllowBlockerOverride bool `json:"allowBlockerOverride,omitempty"`
+	// FileHints injects `<path>.review.md` sidecars as extra per-file prompt guidance.
+	FileHints bool `json:"fileHints,omitempty"`
+	// FileContext adds a window of the full file around each hunk to review prompts.
+	FileContext bool `json:"fileContext,omitempty"`
+	// ContextLines overrides the diff context (--unified=N); nil means git's default of 3.
+	ContextLines *int `json:"contextLines,omitempty"`
+	// DiffMode is "merge-base" (base...branch, default) or "direct" (base..branch).
+	DiffMode string `json:"diffMode,omitempty"`
+	// FetchRemote runs git fetch before a branch diff whose base or branch is a remote ref (origin/main).
+	FetchRemote bool `json:"fetchRemote,omitempty"`
+	// Include and Exclude are path globs applied to the parsed diff; exclusion wins.
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+	// DisableStreaming makes the TUI wait for whole LLM responses instead of streaming them.
+	DisableStreaming bool `json:"disableStreaming,omitempty"`
+	// MaxConcurrency is how many files are reviewed in parallel; zero or negative keeps the default.
+	MaxConcurrency int `json:"maxConcurrency,omitempty"`
+	// Temperature overrides the review sampling temperature (0-2); nil means the default of 0.2.
+	Temperature *float64 `json:"temperature,omitempty"`
+	// MergeOverlapping folds comments on overlapping lines of the same file into the most severe one.
+	MergeOverlapping bool `json:"mergeOverlapping,omitempty"`
+	// LanguageFocus adds or replaces per-extension review emphasis, e.g. {".go": "..."}; an empty
+	// value turns the built-in focus for that extension off.
+	LanguageFocus map[string]string `json:"languageFocus,omitempty"`
+	// PromptTemplate is a text/template file (relative to the repo root) whose "file-system",
+	// "file-user", "verdict-system" and "verdict-user" definitions replace the built-in prompts.
+	PromptTemplate string `json:"promptTemplate,omitempty"`
+	// NoGoPolicy sets when the rule-based verdict is NO_GO, e.g. "blockers>=1,issues>=5" or "never";
+	// empty means any blocker. The LLM can still return NO_GO on its own, which always wins.
+	NoGoPolicy string `json:"noGoPolicy,omitempty"`
+	// MinSeverity is the lowest severity reviews report (NIT, SUGGESTION, ISSUE or BLOCKER); empty
+	// means NIT, i.e. everything.
+	MinSeverity string `json:"minSeverity,omitempty"`
+	// TokenWarningThreshold is the estimated prompt size (in tokens) above which the TUI asks before
+	// starting a review; zero uses the default and a negative value never asks.
+	TokenWarningThreshold int `json:"tokenWarningThreshold,omitempty"`
+	// MaxDiffLinesPerRequest splits larger file diffs into several review requests; zero uses the default.
+	MaxDiffLinesPerRequest int `json:"maxDiffLinesPerRequest,omitempty"`
+	// Retry tunes LLM request retries; zero values keep the client defaults.
+	Retry RetrySettings `json:"retry,omitempty"`
+	// Theme picks the TUI colors: "dark" (default) or "light".
+	Theme string `json:"theme,omitempty"`
+	// ThemeColors overrides single theme colors by role (accent, error, success, warning, muted,
+	// and the severities blocker, issue, suggestion, nit) with an ANSI number from 0 to 255 such as
+	// "62" or a hex value such as "#6124DF".
+	ThemeColors map[string]string `json:"themeColors,omitempty"`
+}
+
+type RetrySettings struct {
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+	BaseDelayMs int `json:"baseDelayMs,omitempty"`
+	MaxDelayMs  int `json:"maxDelayMs,omitempty"`
+	// Backoff is "linear" (default) or "exponential".
+	Backoff string `json:"backoff,omitempty"`
+	Jitter  bool   `json:"jitter,omitempty"`
 }
 
 func ConfigDir() (string, error) {
@@ -10,6 +10,55 @@ func OpenRouterBaseURL() string {
 	return os.Getenv("OPENROUTER_BASE_URL")
 }
 
+// OpenRouterReferer overrides the HTTP-Referer app attribution sent to OpenRouter.
+func OpenRouterReferer() string {
+	return os.Getenv("OPENROUTER_HTTP_REFERER")
+}
+
+// OpenRouterTitle overrides the X-Title app attribution sent to OpenRouter.
+func OpenRouterTitle() string {
+	return os.Getenv("OPENROUTER_X_TITLE")
+}
+
+// ProviderKeyEnv names the environment variable holding the API key for an LLM provider.
+func ProviderKeyEnv(provider string) string {
+	switch provider {
+	case "openai":
+		return "OPENAI_API_KEY"
+	case "anthropic":
+		return "ANTHROPIC_API_KEY"
+	default:
+		return "OPENROUTER_API_KEY"
+	}
+}
+
+// ProviderAPIKey reads the API key for an LLM provider; empty means OpenRouter.
+func ProviderAPIKey(provider string) string {
+	return os.Getenv(ProviderKeyEnv(provider))
+}
+
+// ProviderBaseURL reads the base URL override for an LLM provider; empty means the provider default.
+func ProviderBaseURL(provider string) string {
+	switch provider {
+	case "openai":
+		return os.Getenv("OPENAI_BASE_URL")
+	case "anthropic":
+		return os.Getenv("ANTHROPIC_BASE_URL")
+	default:
+		return OpenRouterBaseURL()
+	}
+}
+
+// GitHubToken reads the token used to publish to GitHub pull requests.
+func GitHubToken() string {
+	return os.Getenv("GITHUB_TOKEN")
+}
+
+// GitLabToken reads the token used to publish to GitLab merge requests.
+func GitLabToken() string {
+	return os.Getenv("GITLAB_TOKEN")
+}
+
 func BitbucketToken() string {
 	if token := os.Getenv("BITBUCKET_TOKEN"); token != "" {
 		return token
@@ -16,8 +16,34 @@ const (
 )
 
 type DiffFile struct {
-	Path  string
-	Hunks []DiffHunk
+	Path    string
+	OldPath string
+	Renamed bool
+	Binary  bool
+	Deleted bool
+	OldMode string
+	NewMode string
+	Hunks   []DiffHunk
+}
+
+// ModeChanged reports whether the diff changes the file's permission bits.
+func (f DiffFile) ModeChanged() bool {
+	return f.OldMode != "" && f.NewMode != "" && f.OldMode != f.NewMode
+}
+
+// LineCounts returns how many lines the file's hunks add and delete.
+func (f DiffFile) LineCounts() (added, deleted int) {
+	for _, hunk := range f.Hunks {
+		for _, line := range hunk.Lines {
+			switch line.Kind {
+			case DiffLineAdd:
+				added++
+			case DiffLineDel:
+				deleted++
+			}
+		}
+	}
+	return added, deleted
 }
 
 type DiffHunk struct {
@@ -48,6 +74,7 @@ func ParseUnifiedDiff(diff string) ([]DiffFile, error) {
 	var currentHunk *DiffHunk
 	var oldLine int
 	var newLine int
+	var minusPath string
 
 	flushFile := func() {
 		if currentFile != nil {
@@ -61,7 +88,8 @@ func ParseUnifiedDiff(diff string) ([]DiffFile, error) {
 		line := scanner.Text()
 		if strings.HasPrefix(line, "diff --git ") {
 			flushFile()
-			currentFile = &DiffFile{}
+			currentFile = &DiffFile{Path: parseDiffGitPath(line)}
+			minusPath = ""
 			continue
 		}
 
@@ -69,11 +97,54 @@ func ParseUnifiedDiff(diff string) ([]DiffFile, error) {
 			continue
 		}
 
-		if strings.HasPrefix(line, "+++ ") {
+		if currentHunk == nil && strings.HasPrefix(line, "rename from ") {
+			currentFile.OldPath = strings.TrimPrefix(line, "rename from ")
+			currentFile.Renamed = true
+			continue
+		}
+
+		if currentHunk == nil && strings.HasPrefix(line, "rename to ") {
+			currentFile.Path = strings.TrimPrefix(line, "rename to ")
+			currentFile.Renamed = true
+			continue
+		}
+
+		if currentHunk == nil && strings.HasPrefix(line, "old mode ") {
+			currentFile.OldMode = strings.TrimSpace(strings.TrimPrefix(line, "old mode "))
+			continue
+		}
+
+		if currentHunk == nil && strings.HasPrefix(line, "new mode ") {
+			currentFile.NewMode = strings.TrimSpace(strings.TrimPrefix(line, "new mode "))
+			continue
+		}
+
+		if currentHunk == nil && (strings.HasPrefix(line, "Binary files ") || line == "GIT binary patch") {
+			currentFile.Binary = true
+			continue
+		}
+
+		if currentHunk == nil && strings.HasPrefix(line, "deleted file mode ") {
+			currentFile.Deleted = true
+			continue
+		}
+
+		if currentHunk == nil && strings.HasPrefix(line, "--- ") {
+			path := strings.TrimSpace(strings.TrimPrefix(line, "--- "))
+			minusPath = strings.TrimPrefix(path, "a/")
+			continue
+		}
+
+		if currentHunk == nil && strings.HasPrefix(line, "+++ ") {
 			path := strings.TrimSpace(strings.TrimPrefix(line, "+++ "))
 			path = strings.TrimPrefix(path, "b/")
 			if path != "/dev/null" {
 				currentFile.Path = path
+			} else {
+				currentFile.Deleted = true
+				if minusPath != "" && minusPath != "/dev/null" {
+					currentFile.Path = minusPath
+				}
 			}
 			continue
 		}
@@ -143,6 +214,16 @@ func ParseUnifiedDiff(diff string) ([]DiffFile, error) {
 	return files, nil
 }
 
+// parseDiffGitPath extracts the new path from a "diff --git a/<old> b/<new>" header. It is a
+// fallback for entries without ---/+++ lines, such as binary files and pure renames.
+func parseDiffGitPath(line string) string {
+	rest := strings.TrimPrefix(line, "diff --git ")
+	if idx := strings.LastIndex(rest, " b/"); idx != -1 {
+		return rest[idx+len(" b/"):]
+	}
+	return ""
+}
+
 func parseHunkHeader(line string) (string, int, int, int, int, error) {
 	header := line
 	trimmed := strings.TrimPrefix(line, "@@")
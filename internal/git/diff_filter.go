@@ -0,0 +1,97 @@
+package git
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// FilterDiffFiles keeps files whose Path matches at least one include glob (or all files when
+// include is empty) and drops files matching any exclude glob. Exclusion wins over inclusion.
+//
+// Globs use path.Match semantics per path segment. A pattern without a slash matches any single
+// segment (so "vendor" or "*.lock" match at any depth), "**" matches zero or more segments, and a
+// pattern that matches a leading directory also matches everything below it.
+func FilterDiffFiles(files []DiffFile, include, exclude []string) ([]DiffFile, error) {
+	for _, pattern := range append(append([]string(nil), include...), exclude...) {
+		if err := ValidateGlob(pattern); err != nil {
+			return nil, err
+		}
+	}
+
+	kept := make([]DiffFile, 0, len(files))
+	for _, file := range files {
+		if len(include) > 0 && !MatchAnyGlob(include, file.Path) {
+			continue
+		}
+		if MatchAnyGlob(exclude, file.Path) {
+			continue
+		}
+		kept = append(kept, file)
+	}
+	return kept, nil
+}
+
+// ValidateGlob reports malformed patterns up front so they are not silently treated as non-matching.
+func ValidateGlob(pattern string) error {
+	if strings.TrimSpace(pattern) == "" {
+		return fmt.Errorf("empty glob pattern")
+	}
+	for _, segment := range strings.Split(strings.Trim(pattern, "/"), "/") {
+		if _, err := path.Match(segment, ""); err != nil {
+			return fmt.Errorf("invalid glob %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+func MatchAnyGlob(patterns []string, filePath string) bool {
+	for _, pattern := range patterns {
+		if MatchGlob(pattern, filePath) {
+			return true
+		}
+	}
+	return false
+}
+
+func MatchGlob(pattern, filePath string) bool {
+	pattern = strings.Trim(strings.TrimSpace(pattern), "/")
+	filePath = strings.Trim(filePath, "/")
+	if pattern == "" || filePath == "" {
+		return false
+	}
+
+	pathSegments := strings.Split(filePath, "/")
+	if !strings.Contains(pattern, "/") {
+		for _, segment := range pathSegments {
+			if ok, _ := path.Match(pattern, segment); ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	return matchSegments(strings.Split(pattern, "/"), pathSegments)
+}
+
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		// The pattern matched a leading directory of the path.
+		return true
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], segments[1:])
+}
@@ -0,0 +1,64 @@
+package git
+
+import "testing"
+
+func TestFilterDiffFiles_whenIncludeAndExcludeOverlap_shouldLetExcludeWin(t *testing.T) {
+	// arrange
+	files := []DiffFile{
+		{Path: "internal/app/model.go"},
+		{Path: "internal/app/model_gen.go"},
+		{Path: "vendor/lib/x.go"},
+		{Path: "go.sum"},
+	}
+
+	// act
+	kept, err := FilterDiffFiles(files, []string{"*.go"}, []string{"*_gen.go", "vendor"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(kept) != 1 || kept[0].Path != "internal/app/model.go" {
+		t.Fatalf("expected only internal/app/model.go, got %+v", kept)
+	}
+}
+
+func TestMatchGlob_whenPatternHasSegments_shouldMatchPerSegment(t *testing.T) {
+	// arrange
+	cases := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"internal/*/model.go", "internal/app/model.go", true},
+		{"internal/*.go", "internal/app/model.go", false},
+		{"internal/app", "internal/app/model.go", true},
+		{"**/testdata/**", "internal/git/testdata/a.diff", true},
+		{"docs/**/*.md", "docs/a/b/c.md", true},
+		{"docs/**/*.md", "src/c.md", false},
+		{"*.lock", "web/yarn.lock", true},
+	}
+
+	for _, tc := range cases {
+		// act
+		got := MatchGlob(tc.pattern, tc.path)
+
+		// assert
+		if got != tc.want {
+			t.Fatalf("MatchGlob(%q, %q) = %v, want %v", tc.pattern, tc.path, got, tc.want)
+		}
+	}
+}
+
+func TestFilterDiffFiles_whenPatternInvalid_shouldReturnError(t *testing.T) {
+	// arrange
+	files := []DiffFile{{Path: "a.go"}}
+
+	// act
+	_, err := FilterDiffFiles(files, nil, []string{"[a-"})
+
+	// assert
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+}
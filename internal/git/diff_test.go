@@ -46,3 +46,196 @@ func TestParseUnifiedDiff_whenEmptyDiff_shouldReturnError(t *testing.T) {
 		t.Fatalf("expected error, got nil")
 	}
 }
+
+func TestParseUnifiedDiff_whenPureRename_shouldKeepFileWithOldPath(t *testing.T) {
+	// arrange
+	diff := `diff --git a/old/name.go b/new/name.go
+similarity index 100%
+rename from old/name.go
+rename to new/name.go
+`
+
+	// act
+	files, err := ParseUnifiedDiff(diff)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("expected 1 file, got %d", len(files))
+	}
+	if !files[0].Renamed {
+		t.Fatalf("expected file to be marked renamed")
+	}
+	if files[0].OldPath != "old/name.go" || files[0].Path != "new/name.go" {
+		t.Fatalf("expected old/name.go -> new/name.go, got %q -> %q", files[0].OldPath, files[0].Path)
+	}
+	if len(files[0].Hunks) != 0 {
+		t.Fatalf("expected 0 hunks, got %d", len(files[0].Hunks))
+	}
+}
+
+func TestParseUnifiedDiff_whenRenameWithChanges_shouldParseHunks(t *testing.T) {
+	// arrange
+	diff := `diff --git a/a.txt b/b.txt
+similarity index 80%
+rename from a.txt
+rename to b.txt
+index 1111111..2222222 100644
+--- a/a.txt
++++ b/b.txt
+@@ -1,2 +1,2 @@
+ keep
+-old
++new
+`
+
+	// act
+	files, err := ParseUnifiedDiff(diff)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if files[0].OldPath != "a.txt" || files[0].Path != "b.txt" || !files[0].Renamed {
+		t.Fatalf("expected rename a.txt -> b.txt, got %+v", files[0])
+	}
+	if len(files[0].Hunks) != 1 || len(files[0].Hunks[0].Lines) != 3 {
+		t.Fatalf("expected 1 hunk with 3 lines, got %+v", files[0].Hunks)
+	}
+}
+
+func TestParseUnifiedDiff_whenBinaryFile_shouldMarkBinaryWithPath(t *testing.T) {
+	// arrange
+	diff := `diff --git a/assets/logo.png b/assets/logo.png
+index 1111111..2222222 100644
+Binary files a/assets/logo.png and b/assets/logo.png differ
+diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -1 +1 @@
+-old
++new
+`
+
+	// act
+	files, err := ParseUnifiedDiff(diff)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(files))
+	}
+	if !files[0].Binary || files[0].Path != "assets/logo.png" {
+		t.Fatalf("expected binary assets/logo.png, got %+v", files[0])
+	}
+	if files[1].Binary {
+		t.Fatalf("expected main.go not to be binary")
+	}
+}
+
+func TestParseUnifiedDiff_whenModeOnlyChange_shouldKeepFileWithModes(t *testing.T) {
+	// arrange
+	diff := `diff --git a/run.sh b/run.sh
+old mode 100644
+new mode 100755
+`
+
+	// act
+	files, err := ParseUnifiedDiff(diff)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(files) != 1 || files[0].Path != "run.sh" {
+		t.Fatalf("expected run.sh entry, got %+v", files)
+	}
+	if files[0].OldMode != "100644" || files[0].NewMode != "100755" || !files[0].ModeChanged() {
+		t.Fatalf("expected mode change 100644 -> 100755, got %q -> %q", files[0].OldMode, files[0].NewMode)
+	}
+}
+
+func TestParseUnifiedDiff_whenModeAndContentChange_shouldKeepBoth(t *testing.T) {
+	// arrange
+	diff := `diff --git a/run.sh b/run.sh
+old mode 100644
+new mode 100755
+index 1111111..2222222
+--- a/run.sh
++++ b/run.sh
+@@ -1 +1,2 @@
+ #!/bin/sh
++echo hi
+`
+
+	// act
+	files, err := ParseUnifiedDiff(diff)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !files[0].ModeChanged() {
+		t.Fatalf("expected mode change, got %+v", files[0])
+	}
+	if len(files[0].Hunks) != 1 || len(files[0].Hunks[0].Lines) != 2 {
+		t.Fatalf("expected 1 hunk with 2 lines, got %+v", files[0].Hunks)
+	}
+}
+
+func TestParseUnifiedDiff_whenFileDeleted_shouldUseOldPath(t *testing.T) {
+	// arrange
+	diff := `diff --git a/gone.go b/gone.go
+deleted file mode 100644
+index 1111111..0000000
+--- a/gone.go
++++ /dev/null
+@@ -1,2 +0,0 @@
+-package gone
+-func X() {}
+`
+
+	// act
+	files, err := ParseUnifiedDiff(diff)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if files[0].Path != "gone.go" || !files[0].Deleted {
+		t.Fatalf("expected deleted gone.go, got %+v", files[0])
+	}
+	if len(files[0].Hunks[0].Lines) != 2 || files[0].Hunks[0].Lines[0].Kind != DiffLineDel {
+		t.Fatalf("expected 2 deleted lines, got %+v", files[0].Hunks[0].Lines)
+	}
+}
+
+func TestLineCounts_whenHunksAddAndDelete_shouldCountEachKind(t *testing.T) {
+	// arrange
+	diff := `diff --git a/a.go b/a.go
+--- a/a.go
++++ b/a.go
+@@ -1,3 +1,4 @@
+ package a
+-var x = 1
++var x = 2
++var y = 3
+ // end
+`
+	files, err := ParseUnifiedDiff(diff)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// act
+	added, deleted := files[0].LineCounts()
+
+	// assert
+	if added != 2 || deleted != 1 {
+		t.Fatalf("expected +2/-1, got +%d/-%d", added, deleted)
+	}
+}
@@ -5,13 +5,53 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/fs"
 	"os/exec"
+	"strconv"
 	"strings"
 	"time"
 )
 
 const defaultTimeout = 5 * time.Second
 
+// DefaultContextLines matches git's own default for --unified.
+const DefaultContextLines = 3
+
+// DiffMode selects how a branch comparison range is built.
+type DiffMode string
+
+const (
+	// DiffModeMergeBase diffs branch against its merge-base with base (base...branch), i.e. only
+	// the changes introduced on branch. This is the default.
+	DiffModeMergeBase DiffMode = "merge-base"
+	// DiffModeDirect diffs the two tips directly (base..branch), so changes made on base since the
+	// branch forked show up as reversed changes.
+	DiffModeDirect DiffMode = "direct"
+)
+
+// ParseDiffMode validates a user-supplied diff mode; an empty value means DiffModeMergeBase.
+func ParseDiffMode(value string) (DiffMode, error) {
+	switch DiffMode(strings.ToLower(strings.TrimSpace(value))) {
+	case "", DiffModeMergeBase:
+		return DiffModeMergeBase, nil
+	case DiffModeDirect:
+		return DiffModeDirect, nil
+	default:
+		return "", fmt.Errorf("unknown diff mode %q (want %s or %s)", value, DiffModeMergeBase, DiffModeDirect)
+	}
+}
+
+type DiffOptions struct {
+	// ContextLines is passed to git as --unified=N and must be non-negative.
+	ContextLines int
+	// Mode only applies to branch comparisons; the zero value behaves as DiffModeMergeBase.
+	Mode DiffMode
+}
+
+func DefaultDiffOptions() DiffOptions {
+	return DiffOptions{ContextLines: DefaultContextLines}
+}
+
 type RepoInfo struct {
 	RootPath string
 }
@@ -29,37 +69,78 @@ func DetectRepoRoot(path string) (RepoInfo, error) {
 	return RepoInfo{RootPath: strings.TrimSpace(output)}, nil
 }
 
-func ListBranches(repoRoot string) ([]string, error) {
+// Branch is a local or remote-tracking branch; Name is its short form (main, origin/main).
+type Branch struct {
+	Name   string
+	Remote bool
+	// CommittedAt is the committer date of the branch tip.
+	CommittedAt time.Time
+}
+
+// ListBranches returns the local branches (refs/heads) and remote-tracking branches (refs/remotes),
+// leaving out remotes' symbolic HEAD refs.
+func ListBranches(repoRoot string) ([]Branch, error) {
+	return listBranches(repoRoot, "refname")
+}
+
+// ListBranchesByRecency is ListBranches ordered by the tip's commit date, most recent first.
+func ListBranchesByRecency(repoRoot string) ([]Branch, error) {
+	return listBranches(repoRoot, "-committerdate")
+}
+
+func listBranches(repoRoot, sortKey string) ([]Branch, error) {
 	if strings.TrimSpace(repoRoot) == "" {
 		return nil, errors.New("repo root is required")
 	}
 
-	output, err := runGit(repoRoot, defaultTimeout, "for-each-ref", "--format=%(refname:short)", "refs/heads", "refs/remotes")
+	output, err := runGit(repoRoot, defaultTimeout, "for-each-ref", "--sort="+sortKey, "--format=%(refname) %(committerdate:unix)", "refs/heads", "refs/remotes")
 	if err != nil {
 		return nil, err
 	}
 
-	branches := make([]string, 0)
+	branches := make([]Branch, 0)
 	seen := make(map[string]struct{})
 	for _, line := range strings.Split(output, "\n") {
-		branch := strings.TrimSpace(line)
-		if branch == "" || strings.HasSuffix(branch, "/HEAD") {
+		ref, date, _ := strings.Cut(strings.TrimSpace(line), " ")
+		var branch Branch
+		if name, ok := strings.CutPrefix(ref, "refs/heads/"); ok {
+			branch = Branch{Name: name}
+		} else if name, ok := strings.CutPrefix(ref, "refs/remotes/"); ok {
+			branch = Branch{Name: name, Remote: true}
+		}
+		if branch.Name == "" || strings.HasSuffix(branch.Name, "/HEAD") {
 			continue
 		}
-		if _, exists := seen[branch]; exists {
+		if _, exists := seen[ref]; exists {
 			continue
 		}
-		seen[branch] = struct{}{}
+		seen[ref] = struct{}{}
+		if seconds, err := strconv.ParseInt(date, 10, 64); err == nil {
+			branch.CommittedAt = time.Unix(seconds, 0)
+		}
 		branches = append(branches, branch)
 	}
 
 	return branches, nil
 }
 
-func GenerateDiff(repoRoot, baseBranch, branch string) (string, error) {
+// VerifyRef reports an error when ref does not resolve to a commit in the repository.
+func VerifyRef(repoRoot, ref string) error {
 	if strings.TrimSpace(repoRoot) == "" {
-		return "", errors.New("repo root is required")
+		return errors.New("repo root is required")
+	}
+	if strings.TrimSpace(ref) == "" {
+		return errors.New("ref is required")
+	}
+
+	_, err := runGit(repoRoot, defaultTimeout, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return fmt.Errorf("ref %q not found", ref)
 	}
+	return nil
+}
+
+func GenerateDiff(repoRoot, baseBranch, branch string, opts DiffOptions) (string, error) {
 	if strings.TrimSpace(baseBranch) == "" {
 		return "", errors.New("base branch is required")
 	}
@@ -67,7 +148,122 @@ func GenerateDiff(repoRoot, baseBranch, branch string) (string, error) {
 		return "", errors.New("branch is required")
 	}
 
-	return runGit(repoRoot, defaultTimeout, "diff", "--no-color", "--unified=3", baseBranch+"..."+branch)
+	separator := "..."
+	if opts.Mode == DiffModeDirect {
+		separator = ".."
+	}
+
+	return GenerateRangeDiff(repoRoot, baseBranch+separator+branch, opts)
+}
+
+// RevisionRange is a parsed commit range, as reviewed by GenerateRangeDiff.
+type RevisionRange struct {
+	// Base and Head are the range's ends; Head is the revision whose files the changes produce.
+	Base string
+	Head string
+	// Separator is ".." (direct) or "..." (since the merge-base).
+	Separator string
+}
+
+func (r RevisionRange) String() string {
+	return r.Base + r.Separator + r.Head
+}
+
+// ParseRevisionRange reads "A..B" or "A...B" (an omitted end means HEAD, as in git), or a single
+// commit C, which stands for C^..C: the changes that commit introduced.
+func ParseRevisionRange(spec string) (RevisionRange, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return RevisionRange{}, errors.New("range is required")
+	}
+	if strings.HasPrefix(spec, "-") || strings.ContainsAny(spec, " \t") {
+		return RevisionRange{}, fmt.Errorf("invalid range %q", spec)
+	}
+
+	separator := "..."
+	index := strings.Index(spec, separator)
+	if index < 0 {
+		separator = ".."
+		index = strings.Index(spec, separator)
+	}
+	if index < 0 {
+		return RevisionRange{Base: spec + "^", Head: spec, Separator: ".."}, nil
+	}
+	r := RevisionRange{Base: spec[:index], Head: spec[index+len(separator):], Separator: separator}
+	if r.Base == "" {
+		r.Base = "HEAD"
+	}
+	if r.Head == "" {
+		r.Head = "HEAD"
+	}
+	if strings.Contains(r.Head, "..") {
+		return RevisionRange{}, fmt.Errorf("invalid range %q", spec)
+	}
+	return r, nil
+}
+
+// GenerateRangeDiff diffs a raw revision range such as "v1.2.0..v1.3.0", "main...feature" or
+// "abc123^..abc123".
+func GenerateRangeDiff(repoRoot, revisionRange string, opts DiffOptions) (string, error) {
+	if strings.TrimSpace(repoRoot) == "" {
+		return "", errors.New("repo root is required")
+	}
+	if strings.TrimSpace(revisionRange) == "" {
+		return "", errors.New("range is required")
+	}
+	if strings.HasPrefix(revisionRange, "-") {
+		return "", fmt.Errorf("invalid range %q", revisionRange)
+	}
+	if opts.ContextLines < 0 {
+		return "", errors.New("context lines must be non-negative")
+	}
+
+	return runGit(repoRoot, defaultTimeout, "diff", "--no-color", fmt.Sprintf("--unified=%d", opts.ContextLines), revisionRange)
+}
+
+// GenerateWorkingTreeDiff returns staged and unstaged changes to tracked files relative to HEAD.
+func GenerateWorkingTreeDiff(repoRoot string, opts DiffOptions) (string, error) {
+	if strings.TrimSpace(repoRoot) == "" {
+		return "", errors.New("repo root is required")
+	}
+	if opts.ContextLines < 0 {
+		return "", errors.New("context lines must be non-negative")
+	}
+
+	return runGit(repoRoot, defaultTimeout, "diff", "--no-color", fmt.Sprintf("--unified=%d", opts.ContextLines), "HEAD")
+}
+
+// GenerateStagedDiff returns only the changes staged in the index, i.e. what the next commit would contain.
+func GenerateStagedDiff(repoRoot string, opts DiffOptions) (string, error) {
+	if strings.TrimSpace(repoRoot) == "" {
+		return "", errors.New("repo root is required")
+	}
+	if opts.ContextLines < 0 {
+		return "", errors.New("context lines must be non-negative")
+	}
+
+	return runGit(repoRoot, defaultTimeout, "diff", "--cached", "--no-color", fmt.Sprintf("--unified=%d", opts.ContextLines))
+}
+
+// ShowFile returns the content of path at ref (git show ref:path). An empty ref reads the version
+// in the index, which is what a staged review sees. A path missing at ref yields an error matching
+// fs.ErrNotExist.
+func ShowFile(repoRoot, ref, path string) (string, error) {
+	if strings.TrimSpace(repoRoot) == "" {
+		return "", errors.New("repo root is required")
+	}
+	if strings.TrimSpace(path) == "" {
+		return "", errors.New("path is required")
+	}
+	if strings.HasPrefix(ref, "-") {
+		return "", fmt.Errorf("invalid ref %q", ref)
+	}
+
+	content, err := runGit(repoRoot, defaultTimeout, "show", ref+":"+path)
+	if err != nil && (strings.Contains(err.Error(), "does not exist") || strings.Contains(err.Error(), "exists on disk, but not in")) {
+		return "", fmt.Errorf("%w: %w", err, fs.ErrNotExist)
+	}
+	return content, err
 }
 
 func runGit(repoRoot string, timeout time.Duration, args ...string) (string, error) {
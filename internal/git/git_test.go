@@ -1,6 +1,8 @@
 package git
 
 import (
+	"errors"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -36,11 +38,60 @@ func TestListBranches_whenBranchesExist_shouldReturnBranches(t *testing.T) {
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
-	if !contains(branches, "feature/test-branch") {
+	if !contains(branches, Branch{Name: "feature/test-branch"}) {
 		t.Fatalf("expected branch list to include feature/test-branch, got %v", branches)
 	}
 }
 
+func TestListBranches_whenRemoteRefsExist_shouldTagThemAndSkipHEAD(t *testing.T) {
+	// arrange
+	repoRoot := initTestRepo(t)
+	runGitCommand(t, repoRoot, "update-ref", "refs/remotes/origin/master", "HEAD")
+	runGitCommand(t, repoRoot, "symbolic-ref", "refs/remotes/origin/HEAD", "refs/remotes/origin/master")
+
+	// act
+	branches, err := ListBranches(repoRoot)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(branches) != 2 || branches[0].Name != "master" || branches[0].Remote || branches[1].Name != "origin/master" || !branches[1].Remote {
+		t.Fatalf("expected local master and remote origin/master, got %+v", branches)
+	}
+}
+
+func TestListBranchesByRecency_whenTipsDiffer_shouldPutNewestFirst(t *testing.T) {
+	// arrange
+	repoRoot := initTestRepo(t)
+	for _, tip := range []struct{ branch, date string }{
+		{"a-old", "2001-01-01T00:00:00Z"},
+		{"z-new", "2030-01-01T00:00:00Z"},
+	} {
+		t.Setenv("GIT_COMMITTER_DATE", tip.date)
+		runGitCommand(t, repoRoot, "checkout", "--quiet", "-b", tip.branch, "master")
+		runGitCommand(t, repoRoot, "-c", "user.email=test@example.com", "-c", "user.name=Test", "commit", "--allow-empty", "-m", tip.branch)
+	}
+
+	// act
+	branches, err := ListBranchesByRecency(repoRoot)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	names := make([]string, 0, len(branches))
+	for _, branch := range branches {
+		names = append(names, branch.Name)
+	}
+	if strings.Join(names, ",") != "z-new,master,a-old" {
+		t.Fatalf("expected z-new,master,a-old, got %v", names)
+	}
+	if branches[0].CommittedAt.Year() != 2030 {
+		t.Fatalf("expected z-new's commit date, got %v", branches[0].CommittedAt)
+	}
+}
+
 func TestGenerateDiff_whenBranchHasChanges_shouldReturnDiff(t *testing.T) {
 	// arrange
 	repoRoot := initTestRepo(t)
@@ -50,7 +101,7 @@ func TestGenerateDiff_whenBranchHasChanges_shouldReturnDiff(t *testing.T) {
 	runGitCommand(t, repoRoot, "-c", "user.email=test@example.com", "-c", "user.name=Test", "commit", "-m", "add example")
 
 	// act
-	diff, err := GenerateDiff(repoRoot, "master", "feature/change")
+	diff, err := GenerateDiff(repoRoot, "master", "feature/change", DefaultDiffOptions())
 
 	// assert
 	if err != nil {
@@ -61,6 +112,254 @@ func TestGenerateDiff_whenBranchHasChanges_shouldReturnDiff(t *testing.T) {
 	}
 }
 
+func TestGenerateDiff_whenContextLinesNegative_shouldReturnError(t *testing.T) {
+	// arrange
+	repoRoot := initTestRepo(t)
+
+	// act
+	_, err := GenerateDiff(repoRoot, "master", "master", DiffOptions{ContextLines: -1})
+
+	// assert
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+}
+
+func TestGenerateDiff_whenDirectModeAndBaseMovedOn_shouldIncludeBaseChanges(t *testing.T) {
+	// arrange
+	repoRoot := initTestRepo(t)
+	runGitCommand(t, repoRoot, "branch", "feature/change")
+	writeFile(t, filepath.Join(repoRoot, "base-only.txt"), "base\n")
+	runGitCommand(t, repoRoot, "add", "base-only.txt")
+	runGitCommand(t, repoRoot, "-c", "user.email=test@example.com", "-c", "user.name=Test", "commit", "-m", "base change")
+
+	// act
+	mergeBaseDiff, mergeBaseErr := GenerateDiff(repoRoot, "master", "feature/change", DiffOptions{ContextLines: 3, Mode: DiffModeMergeBase})
+	directDiff, directErr := GenerateDiff(repoRoot, "master", "feature/change", DiffOptions{ContextLines: 3, Mode: DiffModeDirect})
+
+	// assert
+	if mergeBaseErr != nil || directErr != nil {
+		t.Fatalf("expected no errors, got %v / %v", mergeBaseErr, directErr)
+	}
+	if strings.TrimSpace(mergeBaseDiff) != "" {
+		t.Fatalf("expected empty merge-base diff, got %q", mergeBaseDiff)
+	}
+	if !strings.Contains(directDiff, "base-only.txt") {
+		t.Fatalf("expected direct diff to include base-only.txt, got %q", directDiff)
+	}
+}
+
+func TestParseRevisionRange_whenSpecGiven_shouldResolveEnds(t *testing.T) {
+	// arrange
+	cases := []struct {
+		spec string
+		want RevisionRange
+	}{
+		{"v1.2.0..v1.3.0", RevisionRange{Base: "v1.2.0", Head: "v1.3.0", Separator: ".."}},
+		{"main...feature", RevisionRange{Base: "main", Head: "feature", Separator: "..."}},
+		{"main..", RevisionRange{Base: "main", Head: "HEAD", Separator: ".."}},
+		{"abc123", RevisionRange{Base: "abc123^", Head: "abc123", Separator: ".."}},
+	}
+
+	for _, tc := range cases {
+		// act
+		got, err := ParseRevisionRange(tc.spec)
+
+		// assert
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", tc.spec, err)
+		}
+		if got != tc.want {
+			t.Fatalf("%s: expected %+v, got %+v", tc.spec, tc.want, got)
+		}
+	}
+}
+
+func TestGenerateRangeDiff_whenSingleCommit_shouldReturnOnlyThatCommit(t *testing.T) {
+	// arrange
+	repoRoot := initTestRepo(t)
+	for _, name := range []string{"first.txt", "second.txt"} {
+		writeFile(t, filepath.Join(repoRoot, name), name+"\n")
+		runGitCommand(t, repoRoot, "add", name)
+		runGitCommand(t, repoRoot, "-c", "user.email=test@example.com", "-c", "user.name=Test", "commit", "-m", "add "+name)
+	}
+	commit, err := ParseRevisionRange("HEAD~1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// act
+	diff, err := GenerateRangeDiff(repoRoot, commit.String(), DefaultDiffOptions())
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !strings.Contains(diff, "first.txt") || strings.Contains(diff, "second.txt") {
+		t.Fatalf("expected only first.txt in the diff, got %q", diff)
+	}
+}
+
+func TestParseDiffMode_whenUnknown_shouldReturnError(t *testing.T) {
+	// arrange
+	value := "sideways"
+
+	// act
+	_, err := ParseDiffMode(value)
+
+	// assert
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+}
+
+func TestGenerateWorkingTreeDiff_whenTrackedFileModified_shouldReturnDiff(t *testing.T) {
+	// arrange
+	repoRoot := initTestRepo(t)
+	writeFile(t, filepath.Join(repoRoot, "example.txt"), "hello\n")
+	runGitCommand(t, repoRoot, "add", "example.txt")
+	runGitCommand(t, repoRoot, "-c", "user.email=test@example.com", "-c", "user.name=Test", "commit", "-m", "add example")
+	writeFile(t, filepath.Join(repoRoot, "example.txt"), "hello\nworld\n")
+
+	// act
+	diff, err := GenerateWorkingTreeDiff(repoRoot, DefaultDiffOptions())
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !strings.Contains(diff, "+world") {
+		t.Fatalf("expected diff to contain +world, got %q", diff)
+	}
+}
+
+func TestGenerateStagedDiff_whenOnlySomeChangesStaged_shouldReturnStagedOnly(t *testing.T) {
+	// arrange
+	repoRoot := initTestRepo(t)
+	writeFile(t, filepath.Join(repoRoot, "staged.txt"), "staged\n")
+	writeFile(t, filepath.Join(repoRoot, "unstaged.txt"), "unstaged\n")
+	runGitCommand(t, repoRoot, "add", "staged.txt")
+
+	// act
+	diff, err := GenerateStagedDiff(repoRoot, DefaultDiffOptions())
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !strings.Contains(diff, "+staged") {
+		t.Fatalf("expected diff to contain +staged, got %q", diff)
+	}
+	if strings.Contains(diff, "unstaged") {
+		t.Fatalf("expected diff to exclude unstaged.txt, got %q", diff)
+	}
+}
+
+func TestVerifyRef_whenBranchExists_shouldReturnNil(t *testing.T) {
+	// arrange
+	repoRoot := initTestRepo(t)
+
+	// act
+	err := VerifyRef(repoRoot, "master")
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestVerifyRef_whenBranchMissing_shouldReturnError(t *testing.T) {
+	// arrange
+	repoRoot := initTestRepo(t)
+
+	// act
+	err := VerifyRef(repoRoot, "does-not-exist")
+
+	// assert
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+}
+
+func TestShowFile_whenFileChangedOnBranch_shouldReturnBranchVersion(t *testing.T) {
+	// arrange
+	repoRoot := initTestRepo(t)
+	runGitCommand(t, repoRoot, "checkout", "-b", "feature/change")
+	writeFile(t, filepath.Join(repoRoot, "example.txt"), "on branch\n")
+	runGitCommand(t, repoRoot, "add", "example.txt")
+	runGitCommand(t, repoRoot, "-c", "user.email=test@example.com", "-c", "user.name=Test", "commit", "-m", "add example")
+	runGitCommand(t, repoRoot, "checkout", "master")
+
+	// act
+	content, err := ShowFile(repoRoot, "feature/change", "example.txt")
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if content != "on branch\n" {
+		t.Fatalf("expected branch content, got %q", content)
+	}
+}
+
+func TestShowFile_whenPathMissingAtRef_shouldReturnNotExist(t *testing.T) {
+	// arrange
+	repoRoot := initTestRepo(t)
+
+	// act
+	_, err := ShowFile(repoRoot, "master", "missing.txt")
+
+	// assert
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected a not-exist error, got %v", err)
+	}
+}
+
+func TestReadPatch_whenFileGiven_shouldReturnParseableDiff(t *testing.T) {
+	// arrange
+	path := filepath.Join(t.TempDir(), "change.patch")
+	patch := "diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n@@ -1 +1 @@\n-package old\n+package main\n"
+	if err := os.WriteFile(path, []byte(patch), 0o644); err != nil {
+		t.Fatalf("write patch: %v", err)
+	}
+
+	// act
+	diff, err := ReadPatch(path)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	files, err := ParseUnifiedDiff(diff)
+	if err != nil {
+		t.Fatalf("expected patch to parse, got %v", err)
+	}
+	if len(files) != 1 || files[0].Path != "main.go" {
+		t.Fatalf("expected main.go, got %+v", files)
+	}
+}
+
+func TestFetchRemoteRefs_whenRemoteMovedOn_shouldUpdateRemoteRef(t *testing.T) {
+	// arrange
+	upstream := initTestRepo(t)
+	clone := filepath.Join(t.TempDir(), "clone")
+	runGitCommand(t, upstream, "clone", "--quiet", upstream, clone)
+	writeFile(t, filepath.Join(upstream, "new.txt"), "new\n")
+	runGitCommand(t, upstream, "add", "new.txt")
+	runGitCommand(t, upstream, "-c", "user.email=test@example.com", "-c", "user.name=Test", "commit", "-m", "add new")
+
+	// act
+	err := FetchRemoteRefs(clone, "master", "origin/master")
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := ShowFile(clone, "origin/master", "new.txt"); err != nil {
+		t.Fatalf("expected origin/master to include new.txt after fetching, got %v", err)
+	}
+}
+
 func initTestRepo(t *testing.T) string {
 	t.Helper()
 
@@ -89,11 +388,30 @@ func writeFile(t *testing.T, path, contents string) {
 	}
 }
 
-func contains(values []string, value string) bool {
+func contains(values []Branch, value Branch) bool {
 	for _, item := range values {
-		if item == value {
+		if item.Name == value.Name && item.Remote == value.Remote {
 			return true
 		}
 	}
 	return false
 }
+
+func TestParseRemoteURL_whenSSHOrHTTPS_shouldExtractHostOwnerAndRepo(t *testing.T) {
+	cases := map[string]Remote{
+		"git@bitbucket.org:acme/my-repo.git":              {Host: "bitbucket.org", Owner: "acme", Repo: "my-repo"},
+		"https://github.com/acme/my-repo.git":             {Host: "github.com", Owner: "acme", Repo: "my-repo"},
+		"https://user@bitbucket.org/acme/my-repo":         {Host: "bitbucket.org", Owner: "acme", Repo: "my-repo"},
+		"ssh://git@gitlab.com:2222/group/sub/my-repo.git": {Host: "gitlab.com", Owner: "group/sub", Repo: "my-repo"},
+	}
+
+	for raw, want := range cases {
+		// act
+		got, err := ParseRemoteURL(raw)
+
+		// assert
+		if err != nil || got != want {
+			t.Fatalf("ParseRemoteURL(%q) = %+v, %v; want %+v", raw, got, err, want)
+		}
+	}
+}
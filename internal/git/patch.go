@@ -0,0 +1,53 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// StdinPatch is the patch path that reads the diff from standard input.
+const StdinPatch = "-"
+
+// ReadPatch reads a unified diff (git diff or diff -u output) from path, or from stdin when path is
+// StdinPatch, so a review can be run on a patch without a repository to diff.
+func ReadPatch(path string) (string, error) {
+	if strings.TrimSpace(path) == "" {
+		return "", errors.New("patch path is required")
+	}
+	var data []byte
+	var err error
+	if path == StdinPatch {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("read patch: %w", err)
+	}
+	return string(data), nil
+}
+
+// ApplyPatch applies a unified diff to the working tree with git apply, which changes nothing
+// when any hunk does not apply.
+func ApplyPatch(repoRoot, patch string) error {
+	if strings.TrimSpace(repoRoot) == "" {
+		return errors.New("repo root is required")
+	}
+	file, err := os.CreateTemp("", "reviewer-*.patch")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.WriteString(patch); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	_, err = runGit(repoRoot, defaultTimeout, "apply", file.Name())
+	return err
+}
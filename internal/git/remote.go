@@ -0,0 +1,101 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Remote is a code host location parsed from a git remote URL.
+type Remote struct {
+	Host string
+	// Owner is the workspace, user or organisation; for GitLab it may span nested groups (a/b).
+	Owner string
+	Repo  string
+}
+
+// RemoteURL returns the fetch URL configured for remote (e.g. "origin").
+func RemoteURL(repoRoot, remote string) (string, error) {
+	if strings.TrimSpace(repoRoot) == "" {
+		return "", errors.New("repo root is required")
+	}
+	if remote == "" || strings.HasPrefix(remote, "-") {
+		return "", fmt.Errorf("invalid remote %q", remote)
+	}
+	output, err := runGit(repoRoot, defaultTimeout, "remote", "get-url", remote)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// fetchTimeout is much longer than defaultTimeout since a fetch goes over the network.
+const fetchTimeout = 2 * time.Minute
+
+// RemoteForRef returns the remote a ref such as "origin/main" tracks, or "" when ref does not start
+// with the name of a configured remote.
+func RemoteForRef(repoRoot, ref string) (string, error) {
+	if strings.TrimSpace(repoRoot) == "" {
+		return "", errors.New("repo root is required")
+	}
+	output, err := runGit(repoRoot, defaultTimeout, "remote")
+	if err != nil {
+		return "", err
+	}
+	for _, remote := range strings.Fields(output) {
+		if strings.HasPrefix(ref, remote+"/") {
+			return remote, nil
+		}
+	}
+	return "", nil
+}
+
+// FetchRemoteRefs runs git fetch for each distinct remote among refs, so remote-tracking refs such
+// as origin/main are current before they are diffed. Refs that are not remote refs are ignored.
+func FetchRemoteRefs(repoRoot string, refs ...string) error {
+	fetched := map[string]bool{}
+	var errs []error
+	for _, ref := range refs {
+		remote, err := RemoteForRef(repoRoot, ref)
+		if err != nil {
+			return err
+		}
+		if remote == "" || fetched[remote] {
+			continue
+		}
+		fetched[remote] = true
+		if _, err := runGit(repoRoot, fetchTimeout, "fetch", "--quiet", remote); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ParseRemoteURL extracts host, owner and repo from SCP-style SSH (git@host:owner/repo.git),
+// ssh:// and http(s):// remote URLs.
+func ParseRemoteURL(raw string) (Remote, error) {
+	raw = strings.TrimSpace(raw)
+	var host, path string
+	if strings.Contains(raw, "://") {
+		parsed, err := url.Parse(raw)
+		if err != nil {
+			return Remote{}, fmt.Errorf("parse remote URL: %w", err)
+		}
+		host, path = parsed.Hostname(), parsed.Path
+	} else if at := strings.Index(raw, "@"); at >= 0 && strings.Contains(raw[at:], ":") {
+		hostAndPath := raw[at+1:]
+		colon := strings.Index(hostAndPath, ":")
+		host, path = hostAndPath[:colon], hostAndPath[colon+1:]
+	} else {
+		return Remote{}, fmt.Errorf("unsupported remote URL %q", raw)
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	slash := strings.LastIndex(path, "/")
+	if host == "" || slash <= 0 || slash == len(path)-1 {
+		return Remote{}, fmt.Errorf("remote URL %q has no owner/repo path", raw)
+	}
+	return Remote{Host: strings.ToLower(host), Owner: path[:slash], Repo: path[slash+1:]}, nil
+}
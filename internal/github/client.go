@@ -0,0 +1,180 @@
+// Package github publishes review results to GitHub pull requests.
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/publish"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/review"
+)
+
+// defaultBaseURL is the GitHub REST API root.
+const defaultBaseURL = "https://api.github.com"
+
+type Config struct {
+	Owner       string
+	Repo        string
+	PullRequest int
+	Token       string
+}
+
+var (
+	_ publish.InlinePublisher    = (*Client)(nil)
+	_ publish.SummaryUpdater     = (*Client)(nil)
+	_ publish.CredentialVerifier = (*Client)(nil)
+)
+
+type Client struct {
+	config  Config
+	baseURL string
+	http    *http.Client
+}
+
+func NewClient(cfg Config) *Client {
+	return &Client{
+		config:  cfg,
+		baseURL: defaultBaseURL,
+		http: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// PublishSummary posts markdown as a pull request conversation comment.
+func (c *Client) PublishSummary(ctx context.Context, markdown string) (string, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d/comments", c.baseURL, c.config.Owner, c.config.Repo, c.config.PullRequest)
+	return c.sendComment(ctx, "POST", url, map[string]any{"body": markdown})
+}
+
+// UpdateSummary edits an existing pull request conversation comment.
+func (c *Client) UpdateSummary(ctx context.Context, id, markdown string) (string, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/issues/comments/%s", c.baseURL, c.config.Owner, c.config.Repo, id)
+	if _, err := c.sendComment(ctx, "PATCH", url, map[string]any{"body": markdown}); err != nil {
+		return "", publish.AsNotFound(err)
+	}
+	return id, nil
+}
+
+// PublishInlineComments posts each comment selected for publishing as a review comment on its line
+// range of the pull request head, one request per comment. Results are in the order of the published
+// comments; the returned error joins every failure.
+func (c *Client) PublishInlineComments(ctx context.Context, comments []review.Comment) ([]publish.Result, error) {
+	headSHA, err := c.headSHA(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("resolve pull request head: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/%s/pulls/%d/comments", c.baseURL, c.config.Owner, c.config.Repo, c.config.PullRequest)
+	var results []publish.Result
+	var errs []error
+	for _, comment := range comments {
+		if !comment.Publish {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return results, errors.Join(append(errs, err)...)
+		}
+		line := max(comment.EndLine, comment.StartLine)
+		payload := map[string]any{
+			"body":      review.RenderInlineSuggestionComment(comment, comment.StartLine, line),
+			"commit_id": headSHA,
+			"path":      comment.FilePath,
+			"line":      line,
+			"side":      "RIGHT",
+		}
+		if comment.EndLine > comment.StartLine {
+			payload["start_line"] = comment.StartLine
+			payload["start_side"] = "RIGHT"
+		}
+		id, err := c.sendComment(ctx, "POST", url, payload)
+		if err != nil {
+			err = fmt.Errorf("%s:%d: %w", comment.FilePath, comment.StartLine, err)
+			errs = append(errs, err)
+		}
+		results = append(results, publish.Result{CommentID: id, Error: err})
+	}
+	return results, errors.Join(errs...)
+}
+
+// VerifyCredentials fetches the pull request to check the token, owner, repository and pull request
+// number before anything is posted.
+func (c *Client) VerifyCredentials(ctx context.Context) error {
+	_, err := c.headSHA(ctx)
+	return err
+}
+
+func (c *Client) headSHA(ctx context.Context) (string, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/pulls/%d", c.baseURL, c.config.Owner, c.config.Repo, c.config.PullRequest)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", fmt.Errorf("create request: %w", err)
+	}
+	c.setHeaders(req)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", publish.NewStatusError(resp)
+	}
+
+	var pull struct {
+		Head struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&pull); err != nil {
+		return "", fmt.Errorf("decode pull request: %w", err)
+	}
+	if pull.Head.SHA == "" {
+		return "", errors.New("pull request has no head commit")
+	}
+	return pull.Head.SHA, nil
+}
+
+func (c *Client) sendComment(ctx context.Context, method, url string, payload map[string]any) (string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(data))
+	if err != nil {
+		return "", fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	c.setHeaders(req)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", publish.NewStatusError(resp)
+	}
+
+	var result struct {
+		ID int64 `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil || result.ID == 0 {
+		// Posted, but the ID is unknown; an empty ID is never stored for a later update.
+		return "", nil
+	}
+	return fmt.Sprintf("%d", result.ID), nil
+}
+
+func (c *Client) setHeaders(req *http.Request) {
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.config.Token))
+}
@@ -0,0 +1,39 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPublishSummary_whenPosted_shouldUseIssueCommentsEndpointWithToken(t *testing.T) {
+	// arrange
+	var gotPath, gotAuth, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotAuth = r.URL.Path, r.Header.Get("Authorization")
+		var payload struct {
+			Body string `json:"body"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		gotBody = payload.Body
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id":9001}`)
+	}))
+	defer server.Close()
+	client := NewClient(Config{Owner: "acme", Repo: "app", PullRequest: 12, Token: "ghp_test"})
+	client.baseURL = server.URL
+
+	// act
+	id, err := client.PublishSummary(context.Background(), "# Verdict")
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if id != "9001" || gotPath != "/repos/acme/app/issues/12/comments" || gotAuth != "Bearer ghp_test" || gotBody != "# Verdict" {
+		t.Fatalf("unexpected request: id=%q path=%q auth=%q body=%q", id, gotPath, gotAuth, gotBody)
+	}
+}
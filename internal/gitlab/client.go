@@ -0,0 +1,135 @@
+// Package gitlab publishes review results to GitLab merge requests.
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/publish"
+)
+
+// DefaultHost is used when no self-managed instance is configured.
+const DefaultHost = "gitlab.com"
+
+type Config struct {
+	// Host is the GitLab instance, with or without a scheme (e.g. gitlab.example.com).
+	Host string
+	// Project is the numeric project ID or its full path (e.g. group/sub/project).
+	Project      string
+	MergeRequest int
+	Token        string
+}
+
+var (
+	_ publish.SummaryUpdater     = (*Client)(nil)
+	_ publish.CredentialVerifier = (*Client)(nil)
+)
+
+type Client struct {
+	config  Config
+	baseURL string
+	http    *http.Client
+}
+
+func NewClient(cfg Config) *Client {
+	return &Client{
+		config:  cfg,
+		baseURL: APIBaseURL(cfg.Host),
+		http: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// APIBaseURL turns a host into its REST API root, defaulting to https and DefaultHost.
+func APIBaseURL(host string) string {
+	host = strings.TrimRight(strings.TrimSpace(host), "/")
+	if host == "" {
+		host = DefaultHost
+	}
+	if !strings.HasPrefix(host, "http://") && !strings.HasPrefix(host, "https://") {
+		host = "https://" + host
+	}
+	return host + "/api/v4"
+}
+
+// PublishSummary posts markdown as a merge request note.
+func (c *Client) PublishSummary(ctx context.Context, markdown string) (string, error) {
+	return c.sendNote(ctx, "POST", c.notesURL(), markdown)
+}
+
+// UpdateSummary replaces the body of an existing merge request note.
+func (c *Client) UpdateSummary(ctx context.Context, id, markdown string) (string, error) {
+	if _, err := c.sendNote(ctx, "PUT", c.notesURL()+"/"+url.PathEscape(id), markdown); err != nil {
+		return "", publish.AsNotFound(err)
+	}
+	return id, nil
+}
+
+// VerifyCredentials fetches the merge request to check the token, host, project and merge request
+// IID before anything is posted.
+func (c *Client) VerifyCredentials(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.mergeRequestURL(), nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("PRIVATE-TOKEN", c.config.Token)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return publish.NewStatusError(resp)
+	}
+	return nil
+}
+
+func (c *Client) mergeRequestURL() string {
+	return fmt.Sprintf("%s/projects/%s/merge_requests/%d",
+		c.baseURL, url.PathEscape(c.config.Project), c.config.MergeRequest)
+}
+
+func (c *Client) notesURL() string {
+	return c.mergeRequestURL() + "/notes"
+}
+
+func (c *Client) sendNote(ctx context.Context, method, endpoint, markdown string) (string, error) {
+	data, err := json.Marshal(map[string]string{"body": markdown})
+	if err != nil {
+		return "", fmt.Errorf("marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewBuffer(data))
+	if err != nil {
+		return "", fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("PRIVATE-TOKEN", c.config.Token)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", publish.NewStatusError(resp)
+	}
+
+	var result struct {
+		ID int64 `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil || result.ID == 0 {
+		// Posted, but the ID is unknown; an empty ID is never stored for a later update.
+		return "", nil
+	}
+	return fmt.Sprintf("%d", result.ID), nil
+}
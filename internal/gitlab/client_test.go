@@ -0,0 +1,32 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPublishSummary_whenProjectIsPath_shouldEscapeItAndSendPrivateToken(t *testing.T) {
+	// arrange
+	var gotPath, gotToken string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotToken = r.URL.EscapedPath(), r.Header.Get("PRIVATE-TOKEN")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id":77}`)
+	}))
+	defer server.Close()
+	client := NewClient(Config{Host: server.URL, Project: "group/app", MergeRequest: 5, Token: "glpat-test"})
+
+	// act
+	id, err := client.PublishSummary(context.Background(), "# Verdict")
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if id != "77" || gotPath != "/api/v4/projects/group%2Fapp/merge_requests/5/notes" || gotToken != "glpat-test" {
+		t.Fatalf("unexpected request: id=%q path=%q token=%q", id, gotPath, gotToken)
+	}
+}
@@ -0,0 +1,138 @@
+package llm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	anthropicVersion = "2023-06-01"
+	// anthropicDefaultMaxTokens is used when the request sets none; the messages API requires it.
+	anthropicDefaultMaxTokens = 4096
+)
+
+// anthropicProvider speaks the Anthropic messages API.
+type anthropicProvider struct{}
+
+func (anthropicProvider) Name() string {
+	return ProviderAnthropic
+}
+
+func (anthropicProvider) DefaultBaseURL() string {
+	return "https://api.anthropic.com/v1"
+}
+
+func (anthropicProvider) Endpoint(baseURL string) string {
+	return baseURL + "/messages"
+}
+
+func (anthropicProvider) KeyCheckEndpoint(baseURL string) string {
+	return baseURL + "/models"
+}
+
+// EncodeRequest maps system messages to the top-level system field, since the messages API only
+// accepts user and assistant roles.
+func (anthropicProvider) EncodeRequest(req ChatRequest) ([]byte, error) {
+	type anthropicMessage struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}
+	payload := struct {
+		Model       string             `json:"model"`
+		System      string             `json:"system,omitempty"`
+		Messages    []anthropicMessage `json:"messages"`
+		MaxTokens   int                `json:"max_tokens"`
+		Temperature float64            `json:"temperature"`
+		Stream      bool               `json:"stream,omitempty"`
+	}{
+		Model:       req.Model,
+		MaxTokens:   req.MaxTokens,
+		Temperature: req.Temperature,
+		Stream:      req.Stream,
+	}
+	if payload.MaxTokens <= 0 {
+		payload.MaxTokens = anthropicDefaultMaxTokens
+	}
+
+	var system []string
+	for _, message := range req.Messages {
+		if message.Role == "system" {
+			system = append(system, message.Content)
+			continue
+		}
+		payload.Messages = append(payload.Messages, anthropicMessage{Role: message.Role, Content: message.Content})
+	}
+	if len(payload.Messages) == 0 {
+		return nil, errors.New("anthropic requests need at least one non-system message")
+	}
+	payload.System = strings.Join(system, "\n\n")
+	return json.Marshal(payload)
+}
+
+// SupportsJSONMode is false: the messages API has no response_format, so the prompt alone asks
+// for JSON.
+func (anthropicProvider) SupportsJSONMode(string) bool {
+	return false
+}
+
+func (anthropicProvider) SetHeaders(header http.Header, apiKey string) {
+	header.Set("x-api-key", apiKey)
+	header.Set("anthropic-version", anthropicVersion)
+}
+
+// DecodeResponse concatenates the text content blocks.
+func (anthropicProvider) DecodeResponse(body []byte) (string, error) {
+	var decoded struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+		StopReason string `json:"stop_reason"`
+	}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return "", err
+	}
+	if len(decoded.Content) == 0 {
+		return "", fmt.Errorf("anthropic response missing content (stop_reason %q)", decoded.StopReason)
+	}
+	var builder strings.Builder
+	for _, block := range decoded.Content {
+		if block.Type == "text" {
+			builder.WriteString(block.Text)
+		}
+	}
+	return builder.String(), nil
+}
+
+func (anthropicProvider) DecodeStreamEvent(data []byte) (string, bool, bool, error) {
+	var event struct {
+		Type  string `json:"type"`
+		Delta struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"delta"`
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(data, &event); err != nil {
+		return "", false, false, nil
+	}
+	switch event.Type {
+	case "error":
+		if event.Error != nil {
+			return "", false, false, errors.New(event.Error.Message)
+		}
+		return "", false, false, errors.New("unknown stream error")
+	case "message_stop":
+		return "", true, false, nil
+	case "content_block_delta":
+		if event.Delta.Type == "text_delta" && event.Delta.Text != "" {
+			return event.Delta.Text, false, true, nil
+		}
+	}
+	return "", false, false, nil
+}
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -14,6 +13,19 @@ import (
 
 const defaultBaseURL = "https://openrouter.ai/api/v1"
 
+// Streamed responses have no overall timeout, so these bound the two ways a stream can hang: a
+// server that never sends headers, and one that stops sending events mid-stream.
+const (
+	streamHeaderTimeout      = 90 * time.Second
+	defaultStreamIdleTimeout = 60 * time.Second
+)
+
+// Default OpenRouter app attribution, sent as HTTP-Referer and X-Title.
+const (
+	DefaultAppReferer = "https://github.com/techitung-arunyawee/code-reviewer-2"
+	DefaultAppTitle   = "code-reviewer-2"
+)
+
 type Message struct {
 	Role    string `json:"role"`
 	Content string `json:"content"`
@@ -22,116 +34,229 @@ type Message struct {
 type ChatRequest struct {
 	Model       string    `json:"model"`
 	Messages    []Message `json:"messages"`
-	Temperature float64   `json:"temperature,omitempty"`
+	Temperature float64   `json:"temperature"`
+	MaxTokens   int       `json:"max_tokens,omitempty"`
+	Stream      bool      `json:"stream,omitempty"`
+	// ResponseFormat asks for structured output. Client drops it for models whose provider does not
+	// support it (see Provider.SupportsJSONMode), so callers can always set it.
+	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
 }
 
+// ResponseFormat is the OpenAI-style response_format request field.
+type ResponseFormat struct {
+	Type string `json:"type"`
+}
+
+// JSONObjectFormat asks the model for a single JSON object and no surrounding prose or fences.
+var JSONObjectFormat = &ResponseFormat{Type: "json_object"}
+
 type Client struct {
+	provider   Provider
 	apiKey     string
 	baseURL    string
 	httpClient *http.Client
+	// streamClient has no overall timeout; it only waits streamHeaderTimeout for response headers.
+	streamClient *http.Client
+	// streamIdleTimeout aborts a stream that sends nothing for this long.
+	streamIdleTimeout time.Duration
+	retry             RetryConfig
+	// headers are extra per-request headers, e.g. OpenRouter app attribution.
+	headers http.Header
 }
 
+// NewClient returns an OpenRouter client.
 func NewClient(apiKey, baseURL string) *Client {
+	provider, _ := ParseProvider(ProviderOpenRouter)
+	return NewProviderClient(provider, apiKey, baseURL)
+}
+
+// NewProviderClient returns a client for the given backend; an empty baseURL uses the provider default.
+func NewProviderClient(provider Provider, apiKey, baseURL string) *Client {
 	if strings.TrimSpace(baseURL) == "" {
-		baseURL = defaultBaseURL
+		baseURL = provider.DefaultBaseURL()
 	}
 	return &Client{
-		apiKey:  apiKey,
-		baseURL: strings.TrimRight(baseURL, "/"),
+		provider: provider,
+		apiKey:   apiKey,
+		baseURL:  strings.TrimRight(baseURL, "/"),
 		httpClient: &http.Client{
 			Timeout: 90 * time.Second,
 		},
+		streamClient:      newStreamHTTPClient(),
+		streamIdleTimeout: defaultStreamIdleTimeout,
+		retry:             DefaultRetryConfig(),
+		headers:           defaultHeaders(provider),
+	}
+}
+
+func newStreamHTTPClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = streamHeaderTimeout
+	return &http.Client{Transport: transport}
+}
+
+func defaultHeaders(provider Provider) http.Header {
+	headers := http.Header{}
+	if provider.Name() == ProviderOpenRouter {
+		headers.Set("HTTP-Referer", DefaultAppReferer)
+		headers.Set("X-Title", DefaultAppTitle)
+	}
+	return headers
+}
+
+// SetAttribution overrides the OpenRouter HTTP-Referer and X-Title headers. Empty values keep the
+// current ones; other providers ignore attribution.
+func (c *Client) SetAttribution(referer, title string) {
+	if c.provider.Name() != ProviderOpenRouter {
+		return
+	}
+	if referer = strings.TrimSpace(referer); referer != "" {
+		c.headers.Set("HTTP-Referer", referer)
+	}
+	if title = strings.TrimSpace(title); title != "" {
+		c.headers.Set("X-Title", title)
+	}
+}
+
+// encodeRequest drops a response format the provider cannot honor before encoding req.
+func (c *Client) encodeRequest(req ChatRequest) ([]byte, error) {
+	if req.ResponseFormat != nil && !c.provider.SupportsJSONMode(req.Model) {
+		req.ResponseFormat = nil
+	}
+	return c.provider.EncodeRequest(req)
+}
+
+func (c *Client) setHeaders(header http.Header) {
+	c.provider.SetHeaders(header, c.apiKey)
+	for key, values := range c.headers {
+		header[key] = append([]string(nil), values...)
 	}
 }
 
 func (c *Client) ChatCompletion(ctx context.Context, req ChatRequest) (string, error) {
+	if err := c.validateRequest(req); err != nil {
+		return "", err
+	}
+
+	body, err := c.encodeRequest(req)
+	if err != nil {
+		return "", err
+	}
+
+	endpoint := c.provider.Endpoint(c.baseURL)
+	id := logRequest(endpoint, body, c.apiKey)
+	return c.withRetry(ctx, func() (string, bool, error) {
+		return c.doRequest(ctx, id, endpoint, body)
+	})
+}
+
+// Endpoint is the chat completion URL requests are posted to.
+func (c *Client) Endpoint() string {
+	return c.provider.Endpoint(c.baseURL)
+}
+
+// Replay posts a recorded request payload (see ReadRequestLog) as is, except that streaming is
+// turned off so the whole response comes back at once. Replays are not added to the request log;
+// their responses are logged under a fresh ID.
+func (c *Client) Replay(ctx context.Context, payload json.RawMessage) (string, error) {
+	if strings.TrimSpace(c.apiKey) == "" {
+		return "", fmt.Errorf("%s api key is missing", c.provider.Name())
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return "", fmt.Errorf("recorded payload is not a JSON object: %w", err)
+	}
+	delete(fields, "stream")
+	body, err := json.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+	endpoint := c.Endpoint()
+	id := newRequestID()
+	return c.withRetry(ctx, func() (string, bool, error) {
+		return c.doRequest(ctx, id, endpoint, body)
+	})
+}
+
+func (c *Client) validateRequest(req ChatRequest) error {
 	if strings.TrimSpace(c.apiKey) == "" {
-		return "", errors.New("openrouter api key is missing")
+		return fmt.Errorf("%s api key is missing", c.provider.Name())
 	}
 	if strings.TrimSpace(req.Model) == "" {
-		return "", errors.New("openrouter model is required")
+		return fmt.Errorf("%s model is required", c.provider.Name())
 	}
 	if len(req.Messages) == 0 {
-		return "", errors.New("openrouter messages are required")
+		return fmt.Errorf("%s messages are required", c.provider.Name())
+	}
+	return nil
+}
+
+// ValidateKey checks the API key against the provider's key endpoint without spending tokens.
+func (c *Client) ValidateKey(ctx context.Context) error {
+	if strings.TrimSpace(c.apiKey) == "" {
+		return fmt.Errorf("%s api key is missing", c.provider.Name())
 	}
 
-	body, err := json.Marshal(req)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.provider.KeyCheckEndpoint(c.baseURL), nil)
 	if err != nil {
-		return "", err
+		return err
 	}
+	c.setHeaders(req.Header)
+	req.Header.Set("Accept", "application/json")
 
-	endpoint := c.baseURL + "/chat/completions"
-	logRequest(endpoint, body)
-	var lastErr error
-	for attempt := 0; attempt < 3; attempt++ {
-		content, retry, err := c.doRequest(ctx, endpoint, body)
-		if err == nil {
-			return content, nil
-		}
-		lastErr = err
-		if !retry {
-			break
-		}
-		backoff := time.Duration(500*(attempt+1)) * time.Millisecond
-		timer := time.NewTimer(backoff)
-		select {
-		case <-ctx.Done():
-			timer.Stop()
-			return "", ctx.Err()
-		case <-timer.C:
-		}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
 	}
+	defer resp.Body.Close()
 
-	return "", lastErr
+	if resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(resp.Body)
+		message := strings.TrimSpace(string(data))
+		if message == "" {
+			message = resp.Status
+		}
+		return fmt.Errorf("%s key check failed: %s", c.provider.Name(), message)
+	}
+	return nil
 }
 
-func (c *Client) doRequest(ctx context.Context, endpoint string, payload []byte) (string, bool, error) {
+func (c *Client) doRequest(ctx context.Context, id, endpoint string, payload []byte) (string, bool, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
 	if err != nil {
 		return "", false, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	c.setHeaders(req.Header)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		logResponse(id, 0, nil, err, c.apiKey)
 		return "", true, err
 	}
 	defer resp.Body.Close()
 
 	data, err := io.ReadAll(resp.Body)
+	logResponse(id, resp.StatusCode, data, err, c.apiKey)
 	if err != nil {
 		return "", false, err
 	}
 
 	if resp.StatusCode >= 300 {
-		message := strings.TrimSpace(string(data))
-		if message == "" {
-			message = resp.Status
-		}
-		err := fmt.Errorf("openrouter request failed: %s", message)
-		return "", resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, err
+		statusErr := newStatusError(c.provider.Name(), resp, data)
+		return "", statusErr.retryable(), statusErr
 	}
 
-	var decoded struct {
-		Choices []struct {
-			Message struct {
-				Content string `json:"content"`
-			} `json:"message"`
-		} `json:"choices"`
-	}
-	if err := json.Unmarshal(data, &decoded); err != nil {
+	content, err := c.provider.DecodeResponse(data)
+	if err != nil {
 		return "", false, err
 	}
-	if len(decoded.Choices) == 0 {
-		return "", false, errors.New("openrouter response missing choices")
-	}
 
-	content := strings.TrimSpace(decoded.Choices[0].Message.Content)
+	content = strings.TrimSpace(content)
 	if content == "" {
-		return "", false, errors.New("openrouter response content is empty")
+		return "", false, fmt.Errorf("%s response content is empty", c.provider.Name())
 	}
 
 	return content, false, nil
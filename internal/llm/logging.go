@@ -1,21 +1,128 @@
 package llm
 
 import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/techitung-arunyawee/code-reviewer-2/internal/config"
 )
 
-type requestLogEntry struct {
+const (
+	// requestLogName is the file under the cache dir every request payload is appended to.
+	requestLogName = "llm-requests.log"
+	// responseLogName holds the matching responses while response logging is enabled.
+	responseLogName = "llm-responses.log"
+	// responseLogLimit caps the response body kept per entry.
+	responseLogLimit = 4096
+)
+
+// responseLogging is off by default because responses quote the reviewed code back.
+var responseLogging atomic.Bool
+
+// EnableResponseLog turns response logging on or off for every client (main ties it to --debug).
+func EnableResponseLog(enabled bool) {
+	responseLogging.Store(enabled)
+}
+
+// RequestLogEntry is one line of the request log. ID correlates it with the response log.
+type RequestLogEntry struct {
 	Timestamp string          `json:"timestamp"`
+	ID        string          `json:"id,omitempty"`
 	Endpoint  string          `json:"endpoint"`
 	Payload   json.RawMessage `json:"payload"`
 }
 
-func logRequest(endpoint string, payload []byte) {
+// ResponseLogEntry is one line of the response log; ID matches the RequestLogEntry it answers.
+type ResponseLogEntry struct {
+	Timestamp string `json:"timestamp"`
+	ID        string `json:"id"`
+	// Status is the HTTP status, or 0 when no response arrived.
+	Status    int             `json:"status"`
+	Body      string          `json:"body,omitempty"`
+	Truncated bool            `json:"truncated,omitempty"`
+	Usage     json.RawMessage `json:"usage,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// secretPatterns match credentials that can reach the logs through a prompt (a diff adding a .env
+// file) or an echoed error body: bearer tokens, key query parameters and the key formats of the
+// LLM providers and code hosts this tool talks to. The first group, when present, is kept.
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9._~+/=-]{8,}`),
+	regexp.MustCompile(`(?i)([?&](?:key|api_key|access_token|token)=)[^&\s"]+`),
+	regexp.MustCompile(`()\bsk-[A-Za-z0-9_-]{16,}`),
+	regexp.MustCompile(`()\b(?:ghp|gho|ghu|ghs|ghr|github_pat)_[A-Za-z0-9_]{20,}`),
+	regexp.MustCompile(`()\bglpat-[A-Za-z0-9_-]{20,}`),
+	regexp.MustCompile(`()\bAKIA[0-9A-Z]{16}\b`),
+}
+
+// redactSecrets replaces apiKey and anything matching secretPatterns with "***".
+func redactSecrets(data []byte, apiKey string) []byte {
+	if apiKey = strings.TrimSpace(apiKey); apiKey != "" {
+		data = bytes.ReplaceAll(data, []byte(apiKey), []byte("***"))
+	}
+	for _, pattern := range secretPatterns {
+		data = pattern.ReplaceAll(data, []byte("${1}***"))
+	}
+	return data
+}
+
+func newRequestID() string {
+	var raw [8]byte
+	_, _ = rand.Read(raw[:])
+	return hex.EncodeToString(raw[:])
+}
+
+// logRequest records payload, with secrets redacted, and returns the ID its responses are logged
+// under. Replaying an entry therefore sends "***" wherever a secret was.
+func logRequest(endpoint string, payload []byte, apiKey string) string {
+	id := newRequestID()
+	appendLogEntry(requestLogName, RequestLogEntry{
+		Timestamp: time.Now().Format(time.RFC3339),
+		ID:        id,
+		Endpoint:  string(redactSecrets([]byte(endpoint), apiKey)),
+		Payload:   redactSecrets(append([]byte(nil), payload...), apiKey),
+	})
+	return id
+}
+
+// logResponse records one attempt's outcome for request id when response logging is enabled. body
+// is the raw response, or the accumulated content of a stream; usage is taken from its top-level
+// "usage" field when there is one. Secrets are redacted as in logRequest.
+func logResponse(id string, status int, body []byte, err error, apiKey string) {
+	if !responseLogging.Load() {
+		return
+	}
+	entry := ResponseLogEntry{Timestamp: time.Now().Format(time.RFC3339), ID: id, Status: status}
+	var decoded struct {
+		Usage json.RawMessage `json:"usage"`
+	}
+	if json.Unmarshal(body, &decoded) == nil && len(decoded.Usage) > 0 && string(decoded.Usage) != "null" {
+		entry.Usage = decoded.Usage
+	}
+	// Redact before truncating: a cut through a secret could leave a prefix too short to match.
+	body = redactSecrets(body, apiKey)
+	if len(body) > responseLogLimit {
+		body, entry.Truncated = body[:responseLogLimit], true
+	}
+	entry.Body = string(body)
+	if err != nil {
+		entry.Error = string(redactSecrets([]byte(err.Error()), apiKey))
+	}
+	appendLogEntry(responseLogName, entry)
+}
+
+func appendLogEntry(name string, entry any) {
 	dir, err := config.CacheDir()
 	if err != nil {
 		return
@@ -23,20 +130,65 @@ func logRequest(endpoint string, payload []byte) {
 	if err := os.MkdirAll(dir, 0o755); err != nil {
 		return
 	}
-	path := filepath.Join(dir, "llm-requests.log")
-	entry := requestLogEntry{
-		Timestamp: time.Now().Format(time.RFC3339),
-		Endpoint:  endpoint,
-		Payload:   append([]byte(nil), payload...),
-	}
 	data, err := json.Marshal(entry)
 	if err != nil {
 		return
 	}
-	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	file, err := os.OpenFile(filepath.Join(dir, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
 	if err != nil {
 		return
 	}
 	defer file.Close()
 	_, _ = file.Write(append(data, '\n'))
 }
+
+// RequestLogPath is the request log written by every chat completion.
+func RequestLogPath() (string, error) {
+	return logPath(requestLogName)
+}
+
+// ResponseLogPath is the response log written while EnableResponseLog is on.
+func ResponseLogPath() (string, error) {
+	return logPath(responseLogName)
+}
+
+func logPath(name string) (string, error) {
+	dir, err := config.CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// ReadRequestLog parses a request log in recorded order.
+func ReadRequestLog(path string) ([]RequestLogEntry, error) {
+	return readLogEntries[RequestLogEntry](path)
+}
+
+// ReadResponseLog parses a response log in recorded order.
+func ReadResponseLog(path string) ([]ResponseLogEntry, error) {
+	return readLogEntries[ResponseLogEntry](path)
+}
+
+func readLogEntries[T any](path string) ([]T, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	entries := make([]T, 0)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry T
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
@@ -0,0 +1,176 @@
+package llm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/config"
+)
+
+const (
+	ProviderOpenRouter = "openrouter"
+	ProviderOpenAI     = "openai"
+	ProviderAnthropic  = "anthropic"
+)
+
+// ProviderNames lists the accepted values for the provider config field and --provider flag.
+var ProviderNames = []string{ProviderOpenRouter, ProviderOpenAI, ProviderAnthropic}
+
+// Provider hides the wire format of a chat completion API so Client can keep one retry,
+// logging and streaming path for every backend.
+type Provider interface {
+	Name() string
+	DefaultBaseURL() string
+	// Endpoint is the chat completion URL under baseURL.
+	Endpoint(baseURL string) string
+	// KeyCheckEndpoint is a cheap authenticated GET used by ValidateKey.
+	KeyCheckEndpoint(baseURL string) string
+	EncodeRequest(req ChatRequest) ([]byte, error)
+	// SupportsJSONMode reports whether model accepts response_format {"type": "json_object"}.
+	SupportsJSONMode(model string) bool
+	SetHeaders(header http.Header, apiKey string)
+	// DecodeResponse extracts the assistant text from a non-streamed response body.
+	DecodeResponse(body []byte) (string, error)
+	// DecodeStreamEvent extracts the content delta from one server-sent event payload.
+	// done reports the end of the stream; ok is false for events that carry no content.
+	DecodeStreamEvent(data []byte) (delta string, done bool, ok bool, err error)
+}
+
+// ParseProvider resolves a provider name; empty means OpenRouter.
+func ParseProvider(name string) (Provider, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", ProviderOpenRouter:
+		return openAICompatible{name: ProviderOpenRouter, baseURL: defaultBaseURL, keyPath: "/key"}, nil
+	case ProviderOpenAI:
+		return openAICompatible{name: ProviderOpenAI, baseURL: "https://api.openai.com/v1", keyPath: "/models"}, nil
+	case ProviderAnthropic:
+		return anthropicProvider{}, nil
+	default:
+		return nil, fmt.Errorf("unknown provider %q (want one of %s)", name, strings.Join(ProviderNames, ", "))
+	}
+}
+
+// openAICompatible speaks the OpenAI chat completions format, which OpenRouter also implements.
+type openAICompatible struct {
+	name    string
+	baseURL string
+	keyPath string
+}
+
+func (p openAICompatible) Name() string {
+	return p.name
+}
+
+func (p openAICompatible) DefaultBaseURL() string {
+	return p.baseURL
+}
+
+func (p openAICompatible) Endpoint(baseURL string) string {
+	return baseURL + "/chat/completions"
+}
+
+func (p openAICompatible) KeyCheckEndpoint(baseURL string) string {
+	return baseURL + p.keyPath
+}
+
+func (p openAICompatible) EncodeRequest(req ChatRequest) ([]byte, error) {
+	return json.Marshal(req)
+}
+
+// openAIWithoutJSONMode are OpenAI model prefixes that predate or reject response_format.
+var openAIWithoutJSONMode = []string{"gpt-4-0314", "gpt-4-0613", "gpt-3.5-turbo-0613", "o1-preview", "o1-mini"}
+
+// openRouterJSONModeVendors are the OpenRouter model namespaces whose upstream APIs implement
+// response_format; OpenRouter passes the field through to them.
+var openRouterJSONModeVendors = []string{"openai/", "google/", "mistralai/", "deepseek/"}
+
+func (p openAICompatible) SupportsJSONMode(model string) bool {
+	model = strings.ToLower(strings.TrimSpace(model))
+	if p.name == ProviderOpenRouter {
+		return slices.ContainsFunc(openRouterJSONModeVendors, func(vendor string) bool {
+			return strings.HasPrefix(model, vendor)
+		})
+	}
+	if model == "gpt-4" {
+		return false
+	}
+	return !slices.ContainsFunc(openAIWithoutJSONMode, func(prefix string) bool {
+		return strings.HasPrefix(model, prefix)
+	})
+}
+
+func (p openAICompatible) SetHeaders(header http.Header, apiKey string) {
+	header.Set("Authorization", "Bearer "+apiKey)
+}
+
+func (p openAICompatible) DecodeResponse(body []byte) (string, error) {
+	var decoded struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return "", err
+	}
+	if len(decoded.Choices) == 0 {
+		return "", fmt.Errorf("%s response missing choices", p.name)
+	}
+	return decoded.Choices[0].Message.Content, nil
+}
+
+func (p openAICompatible) DecodeStreamEvent(data []byte) (string, bool, bool, error) {
+	if strings.TrimSpace(string(data)) == "[DONE]" {
+		return "", true, false, nil
+	}
+	var chunk struct {
+		Choices []struct {
+			Delta struct {
+				Content string `json:"content"`
+			} `json:"delta"`
+		} `json:"choices"`
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(data, &chunk); err != nil {
+		return "", false, false, nil
+	}
+	if chunk.Error != nil {
+		return "", false, false, errors.New(chunk.Error.Message)
+	}
+	if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+		return "", false, false, nil
+	}
+	return chunk.Choices[0].Delta.Content, false, true, nil
+}
+
+// ProviderForModel picks the provider for a model when none is configured explicitly: bare
+// "claude-*" model names go to Anthropic, everything else to OpenRouter.
+func ProviderForModel(explicit, model string) string {
+	if strings.TrimSpace(explicit) != "" {
+		return strings.ToLower(strings.TrimSpace(explicit))
+	}
+	if strings.HasPrefix(strings.ToLower(strings.TrimSpace(model)), "claude-") {
+		return ProviderAnthropic
+	}
+	return ProviderOpenRouter
+}
+
+// NewClientFromConfig builds a client for cfg.Provider (or the provider implied by cfg.LastModel)
+// with the provider's base URL override and the configured retry policy.
+func NewClientFromConfig(cfg config.Config, apiKey string) (*Client, error) {
+	provider, err := ParseProvider(ProviderForModel(cfg.Provider, cfg.LastModel))
+	if err != nil {
+		return nil, err
+	}
+	client := NewProviderClient(provider, apiKey, config.ProviderBaseURL(provider.Name()))
+	client.SetRetryConfig(RetryConfigFrom(cfg.Retry))
+	client.SetAttribution(config.OpenRouterReferer(), config.OpenRouterTitle())
+	return client, nil
+}
@@ -0,0 +1,122 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestChatCompletion_whenOpenAIProvider_shouldPostToChatCompletionsWithBearerKey(t *testing.T) {
+	// arrange
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	var gotPath, gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotAuth = r.URL.Path, r.Header.Get("Authorization")
+		fmt.Fprint(w, `{"choices":[{"message":{"content":"ok"}}]}`)
+	}))
+	defer server.Close()
+	provider, err := ParseProvider("openai")
+	if err != nil {
+		t.Fatalf("expected openai provider, got %v", err)
+	}
+	client := NewProviderClient(provider, "sk-test", server.URL+"/v1")
+
+	// act
+	content, err := client.ChatCompletion(context.Background(), ChatRequest{
+		Model:    "gpt-4o-mini",
+		Messages: []Message{{Role: "user", Content: "hi"}},
+	})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if content != "ok" || gotPath != "/v1/chat/completions" || gotAuth != "Bearer sk-test" {
+		t.Fatalf("unexpected request: content=%q path=%q auth=%q", content, gotPath, gotAuth)
+	}
+}
+
+func TestParseProvider_whenUnknown_shouldReturnError(t *testing.T) {
+	// act
+	_, err := ParseProvider("bedrock")
+
+	// assert
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+}
+
+func TestChatCompletion_whenAnthropicProvider_shouldSendSystemTopLevelAndJoinTextBlocks(t *testing.T) {
+	// arrange
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	var gotPath, gotKey, gotVersion string
+	var gotBody struct {
+		System    string    `json:"system"`
+		Messages  []Message `json:"messages"`
+		MaxTokens int       `json:"max_tokens"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotKey, gotVersion = r.URL.Path, r.Header.Get("x-api-key"), r.Header.Get("anthropic-version")
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		fmt.Fprint(w, `{"content":[{"type":"text","text":"{\"comments\""},{"type":"text","text":": []}"}],"stop_reason":"end_turn"}`)
+	}))
+	defer server.Close()
+	provider, _ := ParseProvider(ProviderForModel("", "claude-sonnet-4-5"))
+	client := NewProviderClient(provider, "ak-test", server.URL)
+
+	// act
+	content, err := client.ChatCompletion(context.Background(), ChatRequest{
+		Model: "claude-sonnet-4-5",
+		Messages: []Message{
+			{Role: "system", Content: "be strict"},
+			{Role: "user", Content: "review this"},
+		},
+	})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if content != `{"comments": []}` {
+		t.Fatalf("expected joined text blocks, got %q", content)
+	}
+	if gotPath != "/messages" || gotKey != "ak-test" || gotVersion == "" {
+		t.Fatalf("unexpected request: path=%q key=%q version=%q", gotPath, gotKey, gotVersion)
+	}
+	if gotBody.System != "be strict" || len(gotBody.Messages) != 1 || gotBody.Messages[0].Role != "user" || gotBody.MaxTokens <= 0 {
+		t.Fatalf("expected system at top level and only the user message, got %+v", gotBody)
+	}
+}
+
+func TestChatCompletion_whenJSONModeRequested_shouldSendResponseFormatOnlyToSupportingModels(t *testing.T) {
+	// arrange
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	var formats []any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		formats = append(formats, body["response_format"])
+		fmt.Fprint(w, `{"choices":[{"message":{"content":"{}"}}]}`)
+	}))
+	defer server.Close()
+	client := NewClient("or-test", server.URL)
+
+	// act
+	for _, model := range []string{"openai/gpt-4o-mini", "anthropic/claude-sonnet-4.5"} {
+		if _, err := client.ChatCompletion(context.Background(), ChatRequest{
+			Model:          model,
+			Messages:       []Message{{Role: "user", Content: "Return JSON."}},
+			ResponseFormat: JSONObjectFormat,
+		}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	// assert
+	if len(formats) != 2 || fmt.Sprint(formats[0]) != "map[type:json_object]" || formats[1] != nil {
+		t.Fatalf("expected response_format only for the OpenAI model, got %v", formats)
+	}
+}
@@ -0,0 +1,154 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/config"
+)
+
+// RetryConfig controls how failed requests are retried. Only transport errors, 429s and 5xx
+// responses are retried.
+type RetryConfig struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	// MaxDelay caps a single wait; zero means uncapped.
+	MaxDelay time.Duration
+	// Exponential doubles the delay per attempt instead of growing it linearly.
+	Exponential bool
+	// Jitter randomizes each wait between half and the full delay.
+	Jitter bool
+}
+
+// DefaultRetryConfig is 3 attempts with 500ms, 1s linear backoff.
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+	}
+}
+
+// RetryConfigFrom applies user settings on top of DefaultRetryConfig.
+func RetryConfigFrom(settings config.RetrySettings) RetryConfig {
+	retry := DefaultRetryConfig()
+	if settings.MaxAttempts > 0 {
+		retry.MaxAttempts = settings.MaxAttempts
+	}
+	if settings.BaseDelayMs > 0 {
+		retry.BaseDelay = time.Duration(settings.BaseDelayMs) * time.Millisecond
+	}
+	if settings.MaxDelayMs > 0 {
+		retry.MaxDelay = time.Duration(settings.MaxDelayMs) * time.Millisecond
+	}
+	retry.Exponential = strings.EqualFold(settings.Backoff, "exponential")
+	retry.Jitter = settings.Jitter
+	return retry
+}
+
+// SetRetryConfig replaces the retry policy used by ChatCompletion and ChatCompletionStream.
+func (c *Client) SetRetryConfig(retry RetryConfig) {
+	if retry.MaxAttempts <= 0 {
+		retry.MaxAttempts = 1
+	}
+	c.retry = retry
+}
+
+// backoff returns the wait after the given zero-based failed attempt.
+func (r RetryConfig) backoff(attempt int) time.Duration {
+	delay := r.BaseDelay * time.Duration(attempt+1)
+	if r.Exponential {
+		delay = r.BaseDelay << min(attempt, 30)
+	}
+	if r.MaxDelay > 0 && delay > r.MaxDelay {
+		delay = r.MaxDelay
+	}
+	if r.Jitter && delay > 1 {
+		delay = delay/2 + rand.N(delay/2+1)
+	}
+	return delay
+}
+
+// maxRetryAfter bounds how long a server-suggested Retry-After can stall a review.
+const maxRetryAfter = 60 * time.Second
+
+// statusError is a non-2xx response. RetryAfter is the server's suggested wait, if any.
+type statusError struct {
+	provider   string
+	message    string
+	StatusCode int
+	RetryAfter time.Duration
+}
+
+func (e *statusError) Error() string {
+	return e.provider + " request failed: " + e.message
+}
+
+func newStatusError(provider string, resp *http.Response, body []byte) *statusError {
+	message := strings.TrimSpace(string(body))
+	if message == "" {
+		message = resp.Status
+	}
+	err := &statusError{provider: provider, message: message, StatusCode: resp.StatusCode}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		err.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	}
+	return err
+}
+
+func (e *statusError) retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// parseRetryAfter reads Retry-After in either delay-seconds or HTTP-date form, capped at
+// maxRetryAfter. It returns zero when the header is absent, invalid or already in the past.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	var delay time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		delay = time.Duration(seconds) * time.Second
+	} else if at, err := http.ParseTime(value); err == nil {
+		delay = at.Sub(now)
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return min(delay, maxRetryAfter)
+}
+
+// withRetry runs attempt until it succeeds, reports a non-retryable error, or attempts run out.
+func (c *Client) withRetry(ctx context.Context, attempt func() (string, bool, error)) (string, error) {
+	var lastErr error
+	for i := 0; i < c.retry.MaxAttempts; i++ {
+		content, retry, err := attempt()
+		if err == nil {
+			return content, nil
+		}
+		lastErr = err
+		if !retry || i == c.retry.MaxAttempts-1 {
+			break
+		}
+		wait := c.retry.backoff(i)
+		// The server knows its rate-limit window better than our backoff does.
+		var statusErr *statusError
+		if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 {
+			wait = statusErr.RetryAfter
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return "", ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	return "", lastErr
+}
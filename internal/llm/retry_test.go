@@ -0,0 +1,101 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetryConfigBackoff_whenExponentialWithCap_shouldDoubleUntilMaxDelay(t *testing.T) {
+	// arrange
+	retry := RetryConfig{MaxAttempts: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond, Exponential: true}
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond}
+
+	for attempt, expected := range want {
+		// act
+		got := retry.backoff(attempt)
+
+		// assert
+		if got != expected {
+			t.Fatalf("backoff(%d) = %v, want %v", attempt, got, expected)
+		}
+	}
+}
+
+func TestRetryConfigBackoff_whenDefault_shouldKeepLinearBackoff(t *testing.T) {
+	// arrange
+	retry := DefaultRetryConfig()
+
+	// act
+	first, second := retry.backoff(0), retry.backoff(1)
+
+	// assert
+	if first != 500*time.Millisecond || second != time.Second {
+		t.Fatalf("expected 500ms then 1s, got %v then %v", first, second)
+	}
+}
+
+func TestParseRetryAfter_whenSecondsOrHTTPDate_shouldReturnCappedDelay(t *testing.T) {
+	// arrange
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"7", 7 * time.Second},
+		{now.Add(20 * time.Second).Format(http.TimeFormat), 20 * time.Second},
+		{"3600", maxRetryAfter},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"soon", 0},
+		{"", 0},
+	}
+
+	for _, tc := range cases {
+		// act
+		got := parseRetryAfter(tc.value, now)
+
+		// assert
+		if got != tc.want {
+			t.Fatalf("parseRetryAfter(%q) = %v, want %v", tc.value, got, tc.want)
+		}
+	}
+}
+
+func TestChatCompletion_whenRateLimitedWithRetryAfter_shouldWaitAndRetry(t *testing.T) {
+	// arrange
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"choices":[{"message":{"content":"ok"}}]}`)
+	}))
+	defer server.Close()
+	client := NewClient("key", server.URL)
+	client.SetRetryConfig(RetryConfig{MaxAttempts: 2, BaseDelay: time.Millisecond})
+	started := time.Now()
+
+	// act
+	content, err := client.ChatCompletion(context.Background(), ChatRequest{
+		Model:    "m",
+		Messages: []Message{{Role: "user", Content: "hi"}},
+	})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if content != "ok" || calls != 2 {
+		t.Fatalf("expected ok after 2 calls, got %q after %d", content, calls)
+	}
+	if elapsed := time.Since(started); elapsed < time.Second {
+		t.Fatalf("expected to honor Retry-After of 1s, waited %v", elapsed)
+	}
+}
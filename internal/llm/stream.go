@@ -0,0 +1,136 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var errStreamIdle = errors.New("stream idle")
+
+// ChatCompletionStream sends the request with "stream": true and calls onDelta with each content
+// fragment as it arrives. The full content is accumulated and returned once the stream ends.
+// Retries only happen before any content has been delivered.
+func (c *Client) ChatCompletionStream(ctx context.Context, req ChatRequest, onDelta func(string)) (string, error) {
+	if err := c.validateRequest(req); err != nil {
+		return "", err
+	}
+
+	req.Stream = true
+	body, err := c.encodeRequest(req)
+	if err != nil {
+		return "", err
+	}
+
+	endpoint := c.provider.Endpoint(c.baseURL)
+	id := logRequest(endpoint, body, c.apiKey)
+	return c.withRetry(ctx, func() (string, bool, error) {
+		return c.doStreamRequest(ctx, id, endpoint, body, onDelta)
+	})
+}
+
+func (c *Client) doStreamRequest(ctx context.Context, id, endpoint string, payload []byte, onDelta func(string)) (string, bool, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return "", false, err
+	}
+
+	c.setHeaders(req.Header)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := c.streamClient.Do(req)
+	if err != nil {
+		logResponse(id, 0, nil, err, c.apiKey)
+		return "", true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(resp.Body)
+		logResponse(id, resp.StatusCode, data, nil, c.apiKey)
+		statusErr := newStatusError(c.provider.Name(), resp, data)
+		return "", statusErr.retryable(), statusErr
+	}
+
+	idle := time.AfterFunc(c.streamIdleTimeout, func() { cancel(errStreamIdle) })
+	defer idle.Stop()
+	body := &idleReader{r: resp.Body, timer: idle, timeout: c.streamIdleTimeout}
+
+	content, err := readEventStream(c.provider, body, onDelta)
+	if err != nil && errors.Is(context.Cause(ctx), errStreamIdle) {
+		err = fmt.Errorf("%s stream sent nothing for %s: %w", c.provider.Name(), c.streamIdleTimeout, errStreamIdle)
+	}
+	logResponse(id, resp.StatusCode, []byte(content), err, c.apiKey)
+	if err != nil {
+		return "", false, err
+	}
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return "", false, fmt.Errorf("%s response content is empty", c.provider.Name())
+	}
+	return content, false, nil
+}
+
+// idleReader restarts the idle timer whenever the stream delivers data, so only a stalled stream
+// trips it; keep-alive comments count as activity.
+type idleReader struct {
+	r       io.Reader
+	timer   *time.Timer
+	timeout time.Duration
+}
+
+func (r *idleReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.timer.Reset(r.timeout)
+	}
+	return n, err
+}
+
+// readEventStream accumulates delta content from server-sent events until the provider reports the
+// end of the stream or EOF. Comment lines, non-data fields and events without content are skipped;
+// an error event aborts.
+func readEventStream(provider Provider, r io.Reader, onDelta func(string)) (string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var builder strings.Builder
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+
+		delta, done, ok, err := provider.DecodeStreamEvent([]byte(data))
+		if err != nil {
+			return builder.String(), fmt.Errorf("%s stream error: %w", provider.Name(), err)
+		}
+		if done {
+			break
+		}
+		if !ok {
+			continue
+		}
+
+		builder.WriteString(delta)
+		if onDelta != nil {
+			onDelta(delta)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return builder.String(), err
+	}
+
+	return builder.String(), nil
+}
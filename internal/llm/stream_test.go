@@ -0,0 +1,97 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestChatCompletionStream_whenServerSendsChunks_shouldAccumulateContent(t *testing.T) {
+	// arrange
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, ": OPENROUTER PROCESSING\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"{\\\"comments\\\"\"}}]}\n\n")
+		fmt.Fprint(w, "data: not-json\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\": []}\"}}]}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"ignored\"}}]}\n\n")
+	}))
+	defer server.Close()
+	client := NewClient("key", server.URL)
+	var deltas []string
+
+	// act
+	content, err := client.ChatCompletionStream(context.Background(), ChatRequest{
+		Model:    "m",
+		Messages: []Message{{Role: "user", Content: "hi"}},
+	}, func(delta string) { deltas = append(deltas, delta) })
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if content != `{"comments": []}` {
+		t.Fatalf("expected accumulated JSON, got %q", content)
+	}
+	if len(deltas) != 2 {
+		t.Fatalf("expected 2 deltas, got %v", deltas)
+	}
+}
+
+func TestChatCompletionStream_whenStreamReportsError_shouldReturnError(t *testing.T) {
+	// arrange
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "data: {\"error\":{\"message\":\"upstream timeout\"}}\n\n")
+	}))
+	defer server.Close()
+	client := NewClient("key", server.URL)
+
+	// act
+	_, err := client.ChatCompletionStream(context.Background(), ChatRequest{
+		Model:    "m",
+		Messages: []Message{{Role: "user", Content: "hi"}},
+	}, nil)
+
+	// assert
+	if err == nil || !strings.Contains(err.Error(), "upstream timeout") {
+		t.Fatalf("expected upstream timeout error, got %v", err)
+	}
+}
+
+func TestChatCompletionStream_whenStreamStalls_shouldReturnIdleError(t *testing.T) {
+	// arrange
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"partial\"}}]}\n\n")
+		w.(http.Flusher).Flush()
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+	client := NewClient("key", server.URL)
+	client.streamIdleTimeout = 50 * time.Millisecond
+
+	// act
+	_, err := client.ChatCompletionStream(context.Background(), ChatRequest{
+		Model:    "m",
+		Messages: []Message{{Role: "user", Content: "hi"}},
+	}, nil)
+
+	// assert
+	if !errors.Is(err, errStreamIdle) {
+		t.Fatalf("expected stream idle error, got %v", err)
+	}
+}
@@ -0,0 +1,11 @@
+package publish
+
+import (
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/review"
+)
+
+// ComposeMarkdown renders the summary comment posted to the pull request, with tmpl when set and
+// the built-in layout otherwise.
+func ComposeMarkdown(res review.Result, tmpl *review.SummaryTemplate) (string, error) {
+	return tmpl.Render(res)
+}
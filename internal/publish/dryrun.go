@@ -0,0 +1,89 @@
+package publish
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/review"
+)
+
+// DryRun is an InlinePublisher that records what would be sent instead of calling an API. Running
+// Publish against it previews exactly the payloads a real publish would post.
+type DryRun struct {
+	Summaries []string
+	Inline    []review.Comment
+	// UpdatedID is the existing summary comment a real publish would edit.
+	UpdatedID string
+}
+
+var (
+	_ InlinePublisher = (*DryRun)(nil)
+	_ SummaryUpdater  = (*DryRun)(nil)
+)
+
+func (d *DryRun) PublishSummary(_ context.Context, markdown string) (string, error) {
+	d.Summaries = append(d.Summaries, markdown)
+	return "dry-run", nil
+}
+
+func (d *DryRun) UpdateSummary(_ context.Context, id, markdown string) (string, error) {
+	d.Summaries = append(d.Summaries, markdown)
+	d.UpdatedID = id
+	return id, nil
+}
+
+func (d *DryRun) PublishInlineComments(_ context.Context, comments []review.Comment) ([]Result, error) {
+	var results []Result
+	for _, comment := range comments {
+		if !comment.Publish {
+			continue
+		}
+		d.Inline = append(d.Inline, comment)
+		results = append(results, Result{CommentID: "dry-run"})
+	}
+	return results, nil
+}
+
+// Preview lists the inline targets followed by the summary comment body.
+func (d *DryRun) Preview() string {
+	var sb strings.Builder
+	if len(d.Inline) > 0 {
+		sb.WriteString(fmt.Sprintf("Inline comments (%d):\n", len(d.Inline)))
+		for _, comment := range d.Inline {
+			sb.WriteString(fmt.Sprintf("- %s:%d [%s] %s\n", comment.FilePath, comment.StartLine, comment.Severity, comment.Title))
+		}
+		sb.WriteString("\n")
+	}
+	for _, summary := range d.Summaries {
+		if d.UpdatedID != "" {
+			sb.WriteString(fmt.Sprintf("Summary comment (updates existing comment %s):\n\n", d.UpdatedID))
+		} else {
+			sb.WriteString("Summary comment:\n\n")
+		}
+		sb.WriteString(summary)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// PreviewPublish renders what Publish would send for res and writes it to a temp file, returning
+// the preview and the file path. No API is called.
+func PreviewPublish(ctx context.Context, res review.Result, opts Options) (string, string, error) {
+	dryRun := &DryRun{}
+	if _, err := Publish(ctx, dryRun, res, opts); err != nil {
+		return "", "", err
+	}
+	preview := dryRun.Preview()
+
+	file, err := os.CreateTemp("", "reviewer-publish-*.md")
+	if err != nil {
+		return preview, "", err
+	}
+	defer file.Close()
+	if _, err := file.WriteString(preview); err != nil {
+		return preview, "", err
+	}
+	return preview, file.Name(), nil
+}
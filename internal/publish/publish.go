@@ -0,0 +1,200 @@
+// Package publish posts review results to a code host's pull request.
+package publish
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/review"
+)
+
+const (
+	ProviderBitbucket = "bitbucket"
+	ProviderGitHub    = "github"
+	ProviderGitLab    = "gitlab"
+)
+
+// ProviderNames lists the supported code hosts in the order the Publish tab cycles through them.
+var ProviderNames = []string{ProviderBitbucket, ProviderGitHub, ProviderGitLab}
+
+// ParseProvider normalizes a code host name; empty means Bitbucket.
+func ParseProvider(name string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", ProviderBitbucket:
+		return ProviderBitbucket, nil
+	case ProviderGitHub:
+		return ProviderGitHub, nil
+	case ProviderGitLab:
+		return ProviderGitLab, nil
+	default:
+		return "", fmt.Errorf("unknown publish provider %q (want %s)", name, strings.Join(ProviderNames, ", "))
+	}
+}
+
+// ProviderForHost maps a git remote host to its code host provider. Hosts containing "gitlab" are
+// treated as self-managed GitLab instances.
+func ProviderForHost(host string) (string, bool) {
+	host = strings.ToLower(host)
+	switch {
+	case host == "bitbucket.org":
+		return ProviderBitbucket, true
+	case host == "github.com":
+		return ProviderGitHub, true
+	case host == "gitlab.com" || strings.Contains(host, "gitlab"):
+		return ProviderGitLab, true
+	default:
+		return "", false
+	}
+}
+
+// SupportsInline reports whether the publisher for provider can anchor comments to diff lines.
+func SupportsInline(provider string) bool {
+	return provider == ProviderBitbucket || provider == ProviderGitHub
+}
+
+// Publisher posts a general comment on a pull request and returns its ID.
+type Publisher interface {
+	PublishSummary(ctx context.Context, markdown string) (string, error)
+}
+
+// InlinePublisher can also anchor comments to diff lines.
+type InlinePublisher interface {
+	Publisher
+	// PublishInlineComments posts every comment selected for publishing on its own line. Results
+	// are in the order of the published comments; the error joins every failure.
+	PublishInlineComments(ctx context.Context, comments []review.Comment) ([]Result, error)
+}
+
+// Result is the outcome of posting one inline comment.
+type Result struct {
+	CommentID string
+	Error     error
+}
+
+// SummaryUpdater can edit a previously posted summary comment in place.
+type SummaryUpdater interface {
+	// UpdateSummary replaces the body of comment id. It returns an error wrapping
+	// ErrCommentNotFound when the comment no longer exists.
+	UpdateSummary(ctx context.Context, id, markdown string) (string, error)
+}
+
+// CredentialVerifier can check its token and pull request before anything is posted.
+type CredentialVerifier interface {
+	// VerifyCredentials returns nil when the pull request exists and the token can read it.
+	VerifyCredentials(ctx context.Context) error
+}
+
+// ErrCommentNotFound reports that a stored summary comment was deleted on the code host.
+var ErrCommentNotFound = errors.New("comment not found")
+
+// Options controls how Publish posts a result.
+type Options struct {
+	// Inline places selected comments on their diff lines when the publisher supports it.
+	Inline bool
+	// SummaryID is the summary comment left by a previous publish; when set and the publisher is a
+	// SummaryUpdater, that comment is updated instead of posting a new one.
+	SummaryID string
+	// Template replaces the summary comment's built-in layout when set.
+	Template *review.SummaryTemplate
+}
+
+// Outcome describes what Publish posted.
+type Outcome struct {
+	SummaryID string
+	// Updated is true when an existing summary comment was edited.
+	Updated bool
+	// Inline and InlineTotal count placed and attempted inline comments; both are 0 without inline.
+	Inline      int
+	InlineTotal int
+}
+
+func (o Outcome) String() string {
+	text := o.SummaryID
+	if text == "" {
+		text = "posted (ID not returned)"
+	}
+	if o.Updated {
+		text += " (updated)"
+	}
+	if o.InlineTotal > 0 {
+		text += fmt.Sprintf(" (%d of %d inline)", o.Inline, o.InlineTotal)
+	}
+	return text
+}
+
+// Publish posts res through p. With opts.Inline and an InlinePublisher, selected comments go on
+// their diff lines and the summary carries the verdict plus any comment that could not be placed
+// inline; otherwise everything goes into one summary comment.
+func Publish(ctx context.Context, p Publisher, res review.Result, opts Options) (Outcome, error) {
+	inlinePublisher, ok := p.(InlinePublisher)
+	if !opts.Inline || !ok {
+		markdown, err := ComposeMarkdown(res, opts.Template)
+		if err != nil {
+			return Outcome{}, err
+		}
+		return publishSummary(ctx, p, markdown, opts.SummaryID, Outcome{})
+	}
+
+	// Fail on a broken template before anything is posted.
+	if _, err := ComposeMarkdown(res, opts.Template); err != nil {
+		return Outcome{}, err
+	}
+	published := make([]review.Comment, 0, len(res.Comments))
+	for _, comment := range res.Comments {
+		if comment.Publish {
+			published = append(published, comment)
+		}
+	}
+	results, err := inlinePublisher.PublishInlineComments(ctx, published)
+	if ctx.Err() != nil {
+		return Outcome{}, ctx.Err()
+	}
+	if err != nil {
+		slog.Warn("Some inline comments failed; adding them to the summary", "error", err)
+	}
+	summary := res
+	summary.Comments = nil
+	outcome := Outcome{InlineTotal: len(published)}
+	for i, comment := range published {
+		if i < len(results) && results[i].Error == nil {
+			outcome.Inline++
+			continue
+		}
+		summary.Comments = append(summary.Comments, comment)
+	}
+	markdown, err := ComposeMarkdown(summary, opts.Template)
+	if err != nil {
+		return Outcome{}, err
+	}
+	return publishSummary(ctx, p, markdown, opts.SummaryID, outcome)
+}
+
+// publishSummary updates the comment summaryID when possible, falling back to a new comment when
+// there is none or it was deleted.
+func publishSummary(ctx context.Context, p Publisher, markdown, summaryID string, outcome Outcome) (Outcome, error) {
+	if updater, ok := p.(SummaryUpdater); ok && summaryID != "" {
+		id, err := updater.UpdateSummary(ctx, summaryID, markdown)
+		if err == nil {
+			outcome.SummaryID, outcome.Updated = id, true
+			return outcome, nil
+		}
+		if !errors.Is(err, ErrCommentNotFound) {
+			return Outcome{}, err
+		}
+		slog.Info("Previous summary comment is gone; posting a new one", "id", summaryID)
+	}
+	id, err := p.PublishSummary(ctx, markdown)
+	if err != nil {
+		return Outcome{}, err
+	}
+	outcome.SummaryID = id
+	return outcome, nil
+}
+
+// SummaryKey identifies the pull request a summary comment belongs to, for remembering its ID.
+func SummaryKey(provider, owner, repo string, pullRequest int) string {
+	return fmt.Sprintf("%s:%s/%s#%d", provider, owner, repo, pullRequest)
+}
@@ -0,0 +1,115 @@
+package publish
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/review"
+)
+
+type fakePublisher struct {
+	summaries []string
+	failPath  string
+	deleted   bool
+}
+
+func (f *fakePublisher) UpdateSummary(_ context.Context, id, markdown string) (string, error) {
+	if f.deleted {
+		return "", fmt.Errorf("%w: 404", ErrCommentNotFound)
+	}
+	f.summaries = append(f.summaries, markdown)
+	return id, nil
+}
+
+func (f *fakePublisher) PublishSummary(_ context.Context, markdown string) (string, error) {
+	f.summaries = append(f.summaries, markdown)
+	return "42", nil
+}
+
+func (f *fakePublisher) PublishInlineComments(_ context.Context, comments []review.Comment) ([]Result, error) {
+	var results []Result
+	var errs []error
+	for _, comment := range comments {
+		if comment.FilePath == f.failPath {
+			err := errors.New("line not in diff")
+			errs = append(errs, err)
+			results = append(results, Result{Error: err})
+			continue
+		}
+		results = append(results, Result{CommentID: "1"})
+	}
+	return results, errors.Join(errs...)
+}
+
+func TestPublish_whenInlineCommentFails_shouldFallBackToSummary(t *testing.T) {
+	// arrange
+	publisher := &fakePublisher{failPath: "b.go"}
+	res := review.Result{
+		Verdict: review.Verdict{Decision: review.DecisionGo},
+		Comments: []review.Comment{
+			{FilePath: "a.go", StartLine: 1, EndLine: 1, Title: "Placed inline", Publish: true},
+			{FilePath: "b.go", StartLine: 2, EndLine: 2, Title: "Outside the diff", Publish: true},
+		},
+	}
+
+	// act
+	outcome, err := Publish(context.Background(), publisher, res, Options{Inline: true})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if outcome.String() != "42 (1 of 2 inline)" {
+		t.Fatalf("unexpected outcome %q", outcome)
+	}
+	if len(publisher.summaries) != 1 || !strings.Contains(publisher.summaries[0], "Outside the diff") || strings.Contains(publisher.summaries[0], "Placed inline") {
+		t.Fatalf("expected the summary to carry only the failed comment, got %q", publisher.summaries)
+	}
+}
+
+func TestPublish_whenDryRunInline_shouldRecordTargetsWithoutDetailsInSummary(t *testing.T) {
+	// arrange
+	dryRun := &DryRun{}
+	res := review.Result{
+		Verdict: review.Verdict{Decision: review.DecisionNoGo},
+		Comments: []review.Comment{
+			{FilePath: "a.go", StartLine: 8, EndLine: 9, Severity: review.SeverityBlocker, Title: "Nil deref", Publish: true},
+			{FilePath: "b.go", StartLine: 1, EndLine: 1, Severity: review.SeverityNit, Title: "Typo", Publish: false},
+		},
+	}
+
+	// act
+	_, err := Publish(context.Background(), dryRun, res, Options{Inline: true})
+	preview := dryRun.Preview()
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(dryRun.Inline) != 1 || !strings.Contains(preview, "- a.go:8 [BLOCKER] Nil deref") {
+		t.Fatalf("expected one inline target in the preview, got:\n%s", preview)
+	}
+	if len(dryRun.Summaries) != 1 || strings.Contains(dryRun.Summaries[0], "Detailed Comments") {
+		t.Fatalf("expected a verdict-only summary, got %q", dryRun.Summaries)
+	}
+}
+
+func TestPublish_whenStoredSummaryDeleted_shouldPostNewComment(t *testing.T) {
+	// arrange
+	publisher := &fakePublisher{deleted: true}
+	res := review.Result{Verdict: review.Verdict{Decision: review.DecisionGo}}
+
+	// act
+	outcome, err := Publish(context.Background(), publisher, res, Options{SummaryID: "7"})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if outcome.SummaryID != "42" || outcome.Updated || len(publisher.summaries) != 1 {
+		t.Fatalf("expected a freshly created summary, got %+v", outcome)
+	}
+}
@@ -0,0 +1,34 @@
+package publish
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// StatusError is a non-2xx response from a code host API.
+type StatusError struct {
+	Status string
+	Code   int
+	Body   string
+}
+
+// NewStatusError reads the body of the non-2xx resp into a StatusError.
+func NewStatusError(resp *http.Response) *StatusError {
+	body, _ := io.ReadAll(resp.Body)
+	return &StatusError{Status: resp.Status, Code: resp.StatusCode, Body: string(body)}
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status %s: %s", e.Status, e.Body)
+}
+
+// AsNotFound marks a 404 from updating a comment as ErrCommentNotFound.
+func AsNotFound(err error) error {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.Code == http.StatusNotFound {
+		return fmt.Errorf("%w: %v", ErrCommentNotFound, err)
+	}
+	return err
+}
@@ -0,0 +1,81 @@
+package review
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/config"
+)
+
+// fileCacheEntry is what a successful file review leaves behind on disk.
+type fileCacheEntry struct {
+	Comments []Comment `json:"comments"`
+	Dropped  int       `json:"dropped"`
+}
+
+// fileCacheKey identifies one file review request. Anything that changes the prompt (diff, model,
+// guidelines, per-file hint, surrounding file content, language focus, prompt templates, severity
+// floor) or the sampling (temperature) must be part of the key. Every part is written with its
+// separator, empty or not, so a value cannot shift into the slot of the next one.
+func fileCacheKey(filePath, diff, model, guidelineHash, hint, fileContent, languageFocus, promptHash string, minSeverity Severity, temperature float64) string {
+	hasher := sha256.New()
+	parts := []string{filePath, diff, model, guidelineHash, hint, fileContent, languageFocus, promptHash, string(minSeverity), strconv.FormatFloat(temperature, 'g', -1, 64)}
+	for _, part := range parts {
+		_, _ = hasher.Write([]byte(part))
+		_, _ = hasher.Write([]byte{0})
+	}
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+func fileCachePath(key string) (string, error) {
+	dir, err := config.CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "reviews", key+".json"), nil
+}
+
+// loadCachedFileReview returns the stored review for key; ok is false on a miss or unreadable entry.
+func loadCachedFileReview(key string) (fileCacheEntry, bool) {
+	path, err := fileCachePath(key)
+	if err != nil {
+		return fileCacheEntry{}, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fileCacheEntry{}, false
+	}
+	var entry fileCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return fileCacheEntry{}, false
+	}
+	return entry, true
+}
+
+func storeCachedFileReview(key string, entry fileCacheEntry) error {
+	path, err := fileCachePath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	// Write then rename so concurrent workers never read a half-written entry.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return errors.Join(err, os.Remove(tmp))
+	}
+	return nil
+}
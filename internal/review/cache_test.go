@@ -0,0 +1,55 @@
+package review
+
+import "testing"
+
+func TestFileReviewCache_whenStored_shouldLoadOnlyForSameKey(t *testing.T) {
+	// arrange
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	key := fileCacheKey("a.go", "@@ -1 +1 @@", "model", "hash", "", "", "", "", SeverityNit, 0.2)
+	otherGuidelines := fileCacheKey("a.go", "@@ -1 +1 @@", "model", "other-hash", "", "", "", "", SeverityNit, 0.2)
+	entry := fileCacheEntry{Comments: []Comment{{FilePath: "a.go", StartLine: 1, EndLine: 1, Title: "t", Body: "b"}}, Dropped: 2}
+
+	// act
+	err := storeCachedFileReview(key, entry)
+	loaded, hit := loadCachedFileReview(key)
+	_, otherHit := loadCachedFileReview(otherGuidelines)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !hit || len(loaded.Comments) != 1 || loaded.Dropped != 2 || loaded.Comments[0].Title != "t" {
+		t.Fatalf("expected cached entry, got hit=%v %+v", hit, loaded)
+	}
+	if otherHit {
+		t.Fatalf("expected a miss when the guideline hash changes")
+	}
+}
+
+func TestFileCacheKey_whenTemperatureChanges_shouldChangeKey(t *testing.T) {
+	// arrange
+	diff := "@@ -1 +1 @@"
+
+	// act
+	cool := fileCacheKey("a.go", diff, "model", "hash", "", "", "", "", SeverityNit, 0.2)
+	hot := fileCacheKey("a.go", diff, "model", "hash", "", "", "", "", SeverityNit, 1)
+
+	// assert
+	if cool == hot {
+		t.Fatal("expected a different key for a different temperature")
+	}
+}
+
+func TestFileCacheKey_whenValueMovesToNextEmptySlot_shouldChangeKey(t *testing.T) {
+	// arrange
+	diff := "@@ -1 +1 @@"
+
+	// act
+	focus := fileCacheKey("a.go", diff, "model", "hash", "", "", "go", "", SeverityNit, 0.2)
+	prompts := fileCacheKey("a.go", diff, "model", "hash", "", "", "", "go", SeverityNit, 0.2)
+
+	// assert
+	if focus == prompts {
+		t.Fatal("expected language focus and prompt hash to hash differently")
+	}
+}
@@ -7,15 +7,36 @@ import (
 )
 
 func RenderUnifiedDiffFile(file git.DiffFile) string {
+	oldPath := file.Path
+	if file.OldPath != "" {
+		oldPath = file.OldPath
+	}
+
 	var builder strings.Builder
 	builder.WriteString("diff --git a/")
-	builder.WriteString(file.Path)
+	builder.WriteString(oldPath)
 	builder.WriteString(" b/")
 	builder.WriteString(file.Path)
+	if file.Renamed {
+		builder.WriteString("\nrename from ")
+		builder.WriteString(oldPath)
+		builder.WriteString("\nrename to ")
+		builder.WriteString(file.Path)
+	}
+	if file.ModeChanged() {
+		builder.WriteString("\nold mode ")
+		builder.WriteString(file.OldMode)
+		builder.WriteString("\nnew mode ")
+		builder.WriteString(file.NewMode)
+	}
 	builder.WriteString("\n--- a/")
-	builder.WriteString(file.Path)
-	builder.WriteString("\n+++ b/")
-	builder.WriteString(file.Path)
+	builder.WriteString(oldPath)
+	if file.Deleted {
+		builder.WriteString("\n+++ /dev/null")
+	} else {
+		builder.WriteString("\n+++ b/")
+		builder.WriteString(file.Path)
+	}
 	builder.WriteString("\n")
 
 	for _, hunk := range file.Hunks {
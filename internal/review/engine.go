@@ -1,10 +1,15 @@
 package review
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"path"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
@@ -14,6 +19,20 @@ import (
 
 const DefaultModel = "openai/gpt-4o-mini"
 
+// DefaultMaxConcurrency is how many files are reviewed in parallel unless RunOptions overrides it.
+const DefaultMaxConcurrency = 3
+
+// DefaultTemperature is used for file reviews and the verdict unless RunOptions overrides it.
+const DefaultTemperature = 0.2
+
+// DefaultMaxDiffLinesPerRequest is the diff line budget for one file review request. Larger files
+// are split into groups of whole hunks and reviewed in several requests.
+const DefaultMaxDiffLinesPerRequest = 1000
+
+// DefaultMaxTokens caps each completion. Some providers default far lower, which cuts off long
+// comment arrays mid-JSON.
+const DefaultMaxTokens = 4096
+
 type Progress struct {
 	Completed   int
 	Total       int
@@ -28,6 +47,61 @@ type RunOptions struct {
 	FreeText       string
 	GuidelineHash  string
 	MaxConcurrency int
+	// Temperature overrides DefaultTemperature; nil keeps the default so 0 stays expressible.
+	Temperature *float64
+	// MaxTokens is sent as max_tokens on every request; zero uses DefaultMaxTokens.
+	MaxTokens int
+	// FileHints enables per-file sidecar guidance, read through FileContent (see LoadFileHint).
+	FileHints bool
+	// OnStream, when set, switches file reviews to streamed responses and reports how many
+	// characters have arrived for a file. It is called from worker goroutines.
+	OnStream func(filePath string, received int)
+	// MaxDiffLinesPerRequest bounds the diff lines sent per request; zero uses
+	// DefaultMaxDiffLinesPerRequest.
+	MaxDiffLinesPerRequest int
+	// FileContent reads files as of the reviewed revision, for sidecar hints and surrounding code.
+	FileContent FileContentReader
+	// FileContext adds a window of surrounding code from FileContent to each request (see
+	// buildFileContext).
+	FileContext bool
+	// NoCache skips the on-disk file review cache for both lookups and writes.
+	NoCache bool
+	// MergeOverlapping folds comments on overlapping lines of the same file into the most severe
+	// one (see mergeOverlapping).
+	MergeOverlapping bool
+	// LanguageFocus maps file extensions (".go") to review emphasis for that language, overriding
+	// DefaultLanguageFocus; an empty value turns an extension's focus off.
+	LanguageFocus map[string]string
+	// Prompts, when set, replaces some or all of the built-in prompts (see LoadPromptTemplates).
+	Prompts *PromptTemplates
+	// NoGoPolicy decides the rule-based verdict from the comment counts; nil uses
+	// DefaultNoGoPolicy. The final verdict is NO_GO when either this rule or the LLM says so.
+	NoGoPolicy *NoGoPolicy
+	// MinSeverity is the severity floor: the prompt asks the model to leave out anything less
+	// severe and such comments are dropped before dedupe. Empty means NIT, i.e. no floor.
+	MinSeverity Severity
+}
+
+func (o RunOptions) minSeverity() Severity {
+	if o.MinSeverity == "" {
+		return SeverityNit
+	}
+	return o.MinSeverity
+}
+
+func (o RunOptions) temperature() float64 {
+	if o.Temperature == nil {
+		return DefaultTemperature
+	}
+	return *o.Temperature
+}
+
+// ValidateTemperature rejects sampling temperatures outside the [0, 2] range providers accept.
+func ValidateTemperature(temperature float64) error {
+	if temperature < 0 || temperature > 2 {
+		return fmt.Errorf("temperature must be between 0 and 2, got %g", temperature)
+	}
+	return nil
 }
 
 type fileReviewResult struct {
@@ -37,15 +111,56 @@ type fileReviewResult struct {
 	dropped  int
 }
 
-func Run(ctx context.Context, client *llm.Client, files []git.DiffFile, opts RunOptions, progress func(Progress)) (Result, error) {
+// ChatClient is the part of *llm.Client the engine needs, so tests can substitute canned replies.
+type ChatClient interface {
+	ChatCompletion(ctx context.Context, req llm.ChatRequest) (string, error)
+	ChatCompletionStream(ctx context.Context, req llm.ChatRequest, onDelta func(string)) (string, error)
+}
+
+func Run(ctx context.Context, client ChatClient, files []git.DiffFile, opts RunOptions, progress func(Progress)) (Result, error) {
 	if len(files) == 0 {
 		return Result{}, errors.New("no diff files to review")
 	}
+	return run(ctx, client, files, len(files), Result{}, opts, progress)
+}
+
+// RetryFailed re-reviews the files listed in prior.FileErrors and merges the outcome into prior:
+// new comments join the earlier ones (which win on duplicates, keeping any edits), files that now
+// succeed leave FileErrors, and the verdict is regenerated over every comment. files is the full
+// set the prior review covered.
+func RetryFailed(ctx context.Context, client ChatClient, files []git.DiffFile, prior Result, opts RunOptions, progress func(Progress)) (Result, error) {
+	failed := make([]git.DiffFile, 0, len(prior.FileErrors))
+	for _, file := range files {
+		if _, ok := prior.FileErrors[file.Path]; ok {
+			failed = append(failed, file)
+		}
+	}
+	if len(failed) == 0 {
+		return Result{}, errors.New("no failed files to retry")
+	}
+	return run(ctx, client, failed, len(files), prior, opts, progress)
+}
+
+// run reviews files and builds the result on top of prior, whose comments, drop and dismiss
+// counts and errors for files not in files carry over. total is the number of files the whole
+// review covers, for the partial-review note.
+func run(ctx context.Context, client ChatClient, files []git.DiffFile, total int, prior Result, opts RunOptions, progress func(Progress)) (Result, error) {
 	if opts.Model == "" {
 		opts.Model = DefaultModel
 	}
 	if opts.MaxConcurrency <= 0 {
-		opts.MaxConcurrency = 3
+		opts.MaxConcurrency = DefaultMaxConcurrency
+	}
+	if opts.MaxTokens <= 0 {
+		opts.MaxTokens = DefaultMaxTokens
+	}
+	if opts.Temperature != nil {
+		if err := ValidateTemperature(*opts.Temperature); err != nil {
+			return Result{}, err
+		}
+	}
+	if opts.MaxDiffLinesPerRequest <= 0 {
+		opts.MaxDiffLinesPerRequest = DefaultMaxDiffLinesPerRequest
 	}
 	if opts.GuidelineHash == "" {
 		hash, err := HashGuidelines(opts.GuidelinePaths, opts.FreeText)
@@ -65,24 +180,52 @@ func Run(ctx context.Context, client *llm.Client, files []git.DiffFile, opts Run
 
 	worker := func() {
 		for file := range jobs {
-			if len(file.Hunks) == 0 {
+			if file.Binary || (len(file.Hunks) == 0 && !file.ModeChanged()) {
 				results <- fileReviewResult{comments: nil, filePath: file.Path}
 				continue
 			}
 			diff := RenderUnifiedDiffFile(file)
-			messages := BuildFileReviewMessages(guidelines, diff)
-			content, err := client.ChatCompletion(ctx, llm.ChatRequest{
-				Model:       opts.Model,
-				Messages:    messages,
-				Temperature: 0.2,
-			})
-			if err != nil {
-				results <- fileReviewResult{err: err, filePath: file.Path}
-				continue
+			fileGuidelines := guidelines
+			hint := ""
+			if opts.FileHints {
+				loaded, err := LoadFileHint(opts.FileContent, file.Path)
+				if err != nil {
+					results <- fileReviewResult{err: err, filePath: file.Path}
+					continue
+				}
+				hint = loaded
+				fileGuidelines = appendFileHint(guidelines, file.Path, hint)
 			}
-
-			comments, dropped, err := parseFileComments(content)
-			results <- fileReviewResult{comments: comments, err: err, filePath: file.Path, dropped: dropped}
+			content := ""
+			if opts.FileContext && opts.FileContent != nil && !file.Deleted {
+				loaded, err := opts.FileContent(file.Path)
+				if err != nil {
+					slog.Warn("Reviewing without surrounding context", "file", file.Path, "error", err)
+				}
+				content = loaded
+			}
+			cacheKey := fileCacheKey(file.Path, diff, opts.Model, opts.GuidelineHash, hint, content, opts.languageFocus(file.Path), opts.Prompts.Hash(), opts.minSeverity(), opts.temperature())
+			if !opts.NoCache {
+				if entry, ok := loadCachedFileReview(cacheKey); ok {
+					comments, outside := commentsWithinDiff(file, entry.Comments)
+					results <- fileReviewResult{comments: comments, filePath: file.Path, dropped: entry.Dropped + outside}
+					continue
+				}
+			}
+			comments, dropped, err := reviewFileChunks(ctx, client, opts, file, fileGuidelines, content)
+			if dropped > 0 {
+				slog.Warn("Dropped malformed comments", "file", file.Path, "dropped", dropped)
+			}
+			if err == nil && !opts.NoCache {
+				if cacheErr := storeCachedFileReview(cacheKey, fileCacheEntry{Comments: comments, Dropped: dropped}); cacheErr != nil {
+					slog.Warn("Failed to cache file review", "file", file.Path, "error", cacheErr)
+				}
+			}
+			comments, outside := commentsWithinDiff(file, comments)
+			if outside > 0 {
+				slog.Warn("Dropped comments outside the diff", "file", file.Path, "dropped", outside)
+			}
+			results <- fileReviewResult{comments: comments, err: err, filePath: file.Path, dropped: dropped + outside}
 		}
 	}
 
@@ -97,14 +240,19 @@ func Run(ctx context.Context, client *llm.Client, files []git.DiffFile, opts Run
 		close(jobs)
 	}()
 
-	collected := make([]Comment, 0)
+	collected := slices.Clone(prior.Comments)
 	fileErrors := make(map[string]string)
-	droppedTotal := 0
+	for path, message := range prior.FileErrors {
+		fileErrors[path] = message
+	}
+	for _, file := range files {
+		delete(fileErrors, file.Path)
+	}
+	droppedTotal := prior.Dropped
 
-	total := len(files)
 	completed := 0
 	failed := 0
-	for completed < total {
+	for completed < len(files) {
 		result := <-results
 		completed++
 		if result.err != nil {
@@ -118,28 +266,32 @@ func Run(ctx context.Context, client *llm.Client, files []git.DiffFile, opts Run
 			}
 			progress(Progress{
 				Completed:   completed,
-				Total:       total,
+				Total:       len(files),
 				Failed:      failed,
 				CurrentFile: result.filePath,
 				LastError:   lastError,
 			})
 		}
 		droppedTotal += result.dropped
-		collected = append(collected, result.comments...)
+		collected = append(collected, commentsAtLeast(result.comments, opts.minSeverity())...)
 	}
 
-	if failed == total {
-		return Result{}, fmt.Errorf("review failed for all files; last error: %s", progressLastError(fileErrors))
+	if failed == len(files) {
+		return Result{FileErrors: fileErrors}, allFilesFailedError(fileErrors)
 	}
 
 	deduped := dedupeComments(collected)
+	if opts.MergeOverlapping {
+		deduped = mergeOverlapping(deduped)
+	}
 	stats := ComputeStats(deduped)
-	ruleDecision := DecisionGo
-	if stats.Blocker > 0 {
-		ruleDecision = DecisionNoGo
+	policy := DefaultNoGoPolicy
+	if opts.NoGoPolicy != nil {
+		policy = *opts.NoGoPolicy
 	}
+	ruleDecision := policy.Decide(stats)
 
-	verdict, err := generateVerdict(ctx, client, opts.Model, guidelines, deduped, stats, ruleDecision)
+	verdict, err := generateVerdict(ctx, client, opts, guidelines, deduped, stats, ruleDecision)
 	if err != nil {
 		verdict = Verdict{
 			Decision:  ruleDecision,
@@ -159,43 +311,281 @@ func Run(ctx context.Context, client *llm.Client, files []git.DiffFile, opts Run
 		}
 	}
 
+	noteUnreviewedFiles(&verdict, fileErrors, total)
+
 	return Result{
 		Comments:      deduped,
 		Verdict:       verdict,
 		Model:         opts.Model,
 		GuidelineHash: opts.GuidelineHash,
 		Dropped:       droppedTotal,
+		Dismissed:     prior.Dismissed,
 		FileErrors:    fileErrors,
 		GeneratedAt:   time.Now(),
 	}, nil
 }
 
-func progressLastError(errs map[string]string) string {
-	for _, v := range errs {
-		return v
+// streamReportEvery throttles OnStream so the UI is not woken for every token.
+const streamReportEvery = 256
+
+func completeFileReview(ctx context.Context, client ChatClient, req llm.ChatRequest, filePath string, onStream func(string, int)) (string, error) {
+	if onStream == nil {
+		return client.ChatCompletion(ctx, req)
+	}
+	received := 0
+	reported := 0
+	onStream(filePath, 0)
+	return client.ChatCompletionStream(ctx, req, func(delta string) {
+		received += len(delta)
+		if received-reported >= streamReportEvery {
+			reported = received
+			onStream(filePath, received)
+		}
+	})
+}
+
+// chunkDiffFile splits a file's hunks into groups of at most maxLines diff lines. A single hunk over
+// the budget becomes its own chunk rather than being cut mid-hunk. Line numbers stay absolute, so
+// comments from each chunk need no adjustment.
+func chunkDiffFile(file git.DiffFile, maxLines int) []git.DiffFile {
+	if maxLines <= 0 || len(file.Hunks) <= 1 {
+		return []git.DiffFile{file}
+	}
+	var chunks []git.DiffFile
+	var current []git.DiffHunk
+	currentLines := 0
+	flush := func() {
+		if len(current) == 0 {
+			return
+		}
+		chunk := file
+		chunk.Hunks = current
+		chunks = append(chunks, chunk)
+		current = nil
+		currentLines = 0
+	}
+	for _, hunk := range file.Hunks {
+		if currentLines > 0 && currentLines+len(hunk.Lines) > maxLines {
+			flush()
+		}
+		current = append(current, hunk)
+		currentLines += len(hunk.Lines)
+	}
+	flush()
+	return chunks
+}
+
+// reviewFileChunks reviews a file in one request, or in several when its diff exceeds the line
+// budget. Comments from chunks that succeeded are kept even if another chunk fails. fileContent is
+// the full file (or empty); each request gets the context window for its own hunks.
+func reviewFileChunks(ctx context.Context, client ChatClient, opts RunOptions, file git.DiffFile, guidelines, fileContent string) ([]Comment, int, error) {
+	chunks := chunkDiffFile(file, opts.MaxDiffLinesPerRequest)
+	if len(chunks) == 1 {
+		return reviewFileDiff(ctx, client, opts, file.Path, guidelines, RenderUnifiedDiffFile(file), buildFileContext(fileContent, file.Hunks))
 	}
-	return "unknown error"
+
+	slog.Info("Reviewing file in chunks", "file", file.Path, "chunks", len(chunks))
+	var comments []Comment
+	dropped := 0
+	var errs []error
+	for i, chunk := range chunks {
+		chunkComments, chunkDropped, err := reviewFileDiff(ctx, client, opts, file.Path, guidelines, RenderUnifiedDiffFile(chunk), buildFileContext(fileContent, chunk.Hunks))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("chunk %d/%d: %w", i+1, len(chunks), err))
+			continue
+		}
+		comments = append(comments, chunkComments...)
+		dropped += chunkDropped
+	}
+	return comments, dropped, errors.Join(errs...)
 }
 
+// invalidJSONReminder is appended when a file review has to be re-sent because the model's answer
+// could not be decoded.
+const invalidJSONReminder = "Your previous output was not valid JSON. Return ONLY the JSON object."
+
+// reviewFileDiff asks the model to review one rendered diff and decodes its comments. An answer that
+// is not valid JSON is retried once with a reminder before the file is reported as failed.
+func reviewFileDiff(ctx context.Context, client ChatClient, opts RunOptions, filePath, guidelines, diff, fileContext string) ([]Comment, int, error) {
+	messages, err := opts.Prompts.fileReviewMessages(guidelines, opts.languageFocus(filePath), opts.minSeverity(), diff, fileContext)
+	if err != nil {
+		return nil, 0, err
+	}
+	req := llm.ChatRequest{
+		Model:          opts.Model,
+		Messages:       messages,
+		Temperature:    opts.temperature(),
+		MaxTokens:      opts.MaxTokens,
+		ResponseFormat: llm.JSONObjectFormat,
+	}
+	content, err := completeFileReview(ctx, client, req, filePath, opts.OnStream)
+	if err != nil {
+		return nil, 0, err
+	}
+	comments, dropped, err := parseFileComments(content)
+	if err == nil {
+		return comments, dropped, nil
+	}
+	// A cut-off answer would be cut off again under the same max_tokens, so the JSON reminder
+	// retry only runs for answers that are not JSON at all.
+	if looksTruncated(content, err) {
+		return nil, 0, truncatedResponseError(filePath, content, err, opts.MaxTokens)
+	}
+
+	slog.Warn("Invalid JSON from model; retrying file once", "file", filePath, "error", err)
+	req.Messages = append(req.Messages, llm.Message{Role: "system", Content: invalidJSONReminder})
+	retryContent, retryErr := completeFileReview(ctx, client, req, filePath, opts.OnStream)
+	if retryErr != nil {
+		return nil, 0, retryErr
+	}
+	comments, dropped, err = parseFileComments(retryContent)
+	if err != nil && looksTruncated(retryContent, err) {
+		err = truncatedResponseError(filePath, retryContent, err, opts.MaxTokens)
+	}
+	return comments, dropped, err
+}
+
+func truncatedResponseError(filePath, content string, err error, maxTokens int) error {
+	slog.Warn("LLM response looks truncated; max_tokens may be too low", "file", filePath, "maxTokens", maxTokens, "chars", len(content))
+	return fmt.Errorf("%w (response looks truncated; max_tokens=%d may be too low)", err, maxTokens)
+}
+
+func appendFileHint(guidelines, path, hint string) string {
+	if hint == "" {
+		return guidelines
+	}
+	section := fmt.Sprintf("# File-specific guidance for %s\n%s", path, hint)
+	if strings.TrimSpace(guidelines) == "" {
+		return section
+	}
+	return guidelines + "\n\n" + section
+}
+
+// noteUnreviewedFiles records in the verdict that it only covers part of the diff and downgrades it
+// to NO_GO, so files that were never looked at cannot pass as a clean review.
+func noteUnreviewedFiles(verdict *Verdict, fileErrors map[string]string, total int) {
+	if len(fileErrors) == 0 {
+		return
+	}
+	verdict.Decision = DecisionNoGo
+	paths := make([]string, 0, len(fileErrors))
+	for path := range fileErrors {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	verdict.Summary = strings.TrimSpace(fmt.Sprintf("%s (Partial review: %d of %d file(s) could not be reviewed.)", verdict.Summary, len(paths), total))
+	verdict.Rationale = append(verdict.Rationale, "Not reviewed due to errors: "+strings.Join(paths, ", "))
+}
+
+// allFilesFailedError aggregates every file's failure, sorted by path.
+func allFilesFailedError(fileErrors map[string]string) error {
+	paths := make([]string, 0, len(fileErrors))
+	for path := range fileErrors {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	errs := make([]error, 0, len(paths))
+	for _, path := range paths {
+		errs = append(errs, fmt.Errorf("%s: %s", path, fileErrors[path]))
+	}
+	return fmt.Errorf("review failed for all %d file(s): %w", len(paths), errors.Join(errs...))
+}
+
+// commentsAtLeast keeps the comments at floor or above, following the shared severity order. The
+// prompt already asks for this; the filter catches findings the model returns anyway.
+func commentsAtLeast(comments []Comment, floor Severity) []Comment {
+	return slices.DeleteFunc(slices.Clone(comments), func(comment Comment) bool {
+		return !comment.Severity.AtLeast(floor)
+	})
+}
+
+// commentsWithinDiff drops comments placed on another file than the one reviewed, or whose line
+// range does not touch any new-side line shown in file's hunks (added or context), returning the
+// rest and how many were dropped. Deleted files and files without hunks have no new-side lines, so
+// only the path is checked for them.
+func commentsWithinDiff(file git.DiffFile, comments []Comment) ([]Comment, int) {
+	checkLines := !file.Deleted && len(file.Hunks) > 0
+	kept := make([]Comment, 0, len(comments))
+	for _, comment := range comments {
+		if path.Clean(comment.FilePath) != path.Clean(file.Path) {
+			continue
+		}
+		if checkLines && !touchesHunks(file.Hunks, comment.StartLine, comment.EndLine) {
+			continue
+		}
+		comment.FilePath = file.Path
+		kept = append(kept, comment)
+	}
+	return kept, len(comments) - len(kept)
+}
+
+func touchesHunks(hunks []git.DiffHunk, start, end int) bool {
+	for _, hunk := range hunks {
+		for _, line := range hunk.Lines {
+			if line.NewLine > 0 && line.NewLine >= start && line.NewLine <= end {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// dedupeComments drops comments whose ID was already seen and returns the rest in the default
+// display order.
 func dedupeComments(comments []Comment) []Comment {
-	seen := make(map[string]Comment)
+	seen := make(map[string]bool)
+	deduped := make([]Comment, 0, len(comments))
 	for _, comment := range comments {
 		if strings.TrimSpace(comment.ID) == "" {
 			comment.ID = StableCommentID(comment)
 		}
-		if _, ok := seen[comment.ID]; ok {
+		if seen[comment.ID] {
 			continue
 		}
-		seen[comment.ID] = comment
-	}
-
-	deduped := make([]Comment, 0, len(seen))
-	for _, comment := range seen {
+		seen[comment.ID] = true
 		deduped = append(deduped, comment)
 	}
+	SortComments(deduped, OrderBySeverity)
 	return deduped
 }
 
+// mergeOverlapping folds each comment into an earlier one on the same file whose line range
+// overlaps it. comments must be in OrderBySeverity, so the comment kept is the most severe; the
+// others' title, body and suggestion are appended to it as context and their tags merged in.
+func mergeOverlapping(comments []Comment) []Comment {
+	merged := make([]Comment, 0, len(comments))
+	for _, comment := range comments {
+		target := -1
+		for i, kept := range merged {
+			if kept.FilePath == comment.FilePath && kept.StartLine <= comment.EndLine && comment.StartLine <= kept.EndLine {
+				target = i
+				break
+			}
+		}
+		if target < 0 {
+			merged = append(merged, comment)
+			continue
+		}
+		kept := merged[target]
+		kept.Body += fmt.Sprintf("\n\nAlso noted (%s): %s\n%s", comment.Severity, comment.Title, comment.Body)
+		if comment.Suggestion != nil && *comment.Suggestion != "" {
+			suggestion := *comment.Suggestion
+			if kept.Suggestion != nil && *kept.Suggestion != "" {
+				suggestion = *kept.Suggestion + "\n\n" + suggestion
+			}
+			kept.Suggestion = &suggestion
+		}
+		kept.Tags = slices.Clone(kept.Tags)
+		for _, tag := range comment.Tags {
+			if !slices.Contains(kept.Tags, tag) {
+				kept.Tags = append(kept.Tags, tag)
+			}
+		}
+		merged[target] = kept
+	}
+	return merged
+}
+
 func parseFileComments(content string) ([]Comment, int, error) {
 	payload := stripCodeFence(content)
 	var decoded struct {
@@ -209,6 +599,9 @@ func parseFileComments(content string) ([]Comment, int, error) {
 			Suggestion *string  `json:"suggestion"`
 			Evidence   *string  `json:"evidence"`
 			Tags       []string `json:"tags"`
+			// Rule is also read as guidelineRef, which some models answer with instead.
+			Rule         string `json:"rule"`
+			GuidelineRef string `json:"guidelineRef"`
 		} `json:"comments"`
 	}
 
@@ -229,6 +622,7 @@ func parseFileComments(content string) ([]Comment, int, error) {
 			Suggestion: trimOptional(item.Suggestion),
 			Evidence:   trimOptional(item.Evidence),
 			Tags:       item.Tags,
+			Rule:       strings.TrimSpace(cmp.Or(item.Rule, item.GuidelineRef)),
 			Publish:    true,
 		}
 		if comment.StartLine <= 0 || comment.EndLine <= 0 || comment.EndLine < comment.StartLine {
@@ -245,11 +639,31 @@ func parseFileComments(content string) ([]Comment, int, error) {
 	return comments, dropped, nil
 }
 
-func generateVerdict(ctx context.Context, client *llm.Client, model, guidelines string, comments []Comment, stats Stats, ruleDecision Decision) (Verdict, error) {
+// looksTruncated reports whether a JSON decode failure is the response ending early rather than
+// the model answering with something other than JSON.
+func looksTruncated(content string, err error) bool {
+	payload := stripCodeFence(content)
+	if !strings.HasPrefix(payload, "{") {
+		return false
+	}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) && syntaxErr.Offset >= int64(len(payload)) {
+		return true
+	}
+	return strings.Contains(err.Error(), "unexpected end of JSON input")
+}
+
+func generateVerdict(ctx context.Context, client ChatClient, opts RunOptions, guidelines string, comments []Comment, stats Stats, ruleDecision Decision) (Verdict, error) {
+	messages, err := opts.Prompts.verdictMessages(guidelines, comments, stats, ruleDecision)
+	if err != nil {
+		return Verdict{}, err
+	}
 	content, err := client.ChatCompletion(ctx, llm.ChatRequest{
-		Model:       model,
-		Messages:    BuildVerdictMessages(guidelines, comments, stats, ruleDecision),
-		Temperature: 0.2,
+		Model:          opts.Model,
+		Messages:       messages,
+		Temperature:    opts.temperature(),
+		MaxTokens:      opts.MaxTokens,
+		ResponseFormat: llm.JSONObjectFormat,
 	})
 	if err != nil {
 		return Verdict{}, err
@@ -275,19 +689,73 @@ func generateVerdict(ctx context.Context, client *llm.Client, model, guidelines
 	}, nil
 }
 
+// stripCodeFence extracts the JSON payload from a model answer: the first balanced object that is
+// valid JSON, wherever it sits. Every answer the engine asks for is an object, so brackets in prose
+// before it ("see [docs] {...}") are skipped. That copes with ```json fences, several fences and prose
+// before or after the JSON. When no object is valid, the first balanced one (or else the trimmed
+// content) is returned so json.Unmarshal reports the error.
 func stripCodeFence(content string) string {
 	trimmed := strings.TrimSpace(content)
-	if strings.HasPrefix(trimmed, "```") {
-		trimmed = strings.TrimPrefix(trimmed, "```")
-		trimmed = strings.TrimSpace(trimmed)
-		if idx := strings.Index(trimmed, "\n"); idx != -1 {
-			trimmed = trimmed[idx+1:]
+	fallback := ""
+	for offset := 0; ; {
+		i := strings.IndexByte(trimmed[offset:], '{')
+		if i == -1 {
+			break
 		}
-		if end := strings.LastIndex(trimmed, "```"); end != -1 {
-			trimmed = trimmed[:end]
+		start := offset + i
+		if candidate, ok := balancedJSON(trimmed[start:]); ok {
+			if json.Valid([]byte(candidate)) {
+				return candidate
+			}
+			if fallback == "" {
+				fallback = candidate
+			}
+		}
+		offset = start + 1
+	}
+	if fallback != "" {
+		return fallback
+	}
+	return trimmed
+}
+
+// balancedJSON returns the prefix of s, which starts with an opening bracket, through the bracket
+// that closes it. Brackets inside JSON strings are skipped; ok is false when a closing bracket does
+// not match the open one or s ends first.
+func balancedJSON(s string) (string, bool) {
+	var closers []byte
+	inString, escaped := false, false
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case ch == '\\':
+				escaped = true
+			case ch == '"':
+				inString = false
+			}
+			continue
+		}
+		switch ch {
+		case '"':
+			inString = true
+		case '{':
+			closers = append(closers, '}')
+		case '[':
+			closers = append(closers, ']')
+		case '}', ']':
+			if len(closers) == 0 || closers[len(closers)-1] != ch {
+				return "", false
+			}
+			closers = closers[:len(closers)-1]
+			if len(closers) == 0 {
+				return s[:i+1], true
+			}
 		}
 	}
-	return strings.TrimSpace(trimmed)
+	return "", false
 }
 
 func trimOptional(value *string) *string {
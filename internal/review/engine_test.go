@@ -0,0 +1,479 @@
+package review
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/git"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/llm"
+)
+
+func TestNoteUnreviewedFiles_whenFilesFailed_shouldMarkVerdictPartialAndNoGo(t *testing.T) {
+	// arrange
+	verdict := Verdict{Decision: DecisionGo, Summary: "Looks good."}
+	fileErrors := map[string]string{"b.go": "timeout", "a.go": "bad json"}
+
+	// act
+	noteUnreviewedFiles(&verdict, fileErrors, 5)
+
+	// assert
+	if verdict.Decision != DecisionNoGo {
+		t.Fatalf("expected a partial review to be NO_GO, got %s", verdict.Decision)
+	}
+	if !strings.Contains(verdict.Summary, "2 of 5 file(s) could not be reviewed") {
+		t.Fatalf("expected partial review note in summary, got %q", verdict.Summary)
+	}
+	if len(verdict.Rationale) != 1 || !strings.HasSuffix(verdict.Rationale[0], "a.go, b.go") {
+		t.Fatalf("expected sorted unreviewed files in rationale, got %v", verdict.Rationale)
+	}
+}
+
+func TestParseFileComments_whenEntriesMalformed_shouldCountDropped(t *testing.T) {
+	// arrange
+	content := "```json\n" + `{"comments": [
+		{"filePath": "a.go", "startLine": 3, "endLine": 4, "severity": "issue", "title": "t", "body": "b"},
+		{"filePath": "a.go", "startLine": 0, "endLine": 4, "severity": "nit", "title": "t", "body": "b"},
+		{"filePath": "a.go", "startLine": 5, "endLine": 4, "severity": "nit", "title": "t", "body": "b"},
+		{"filePath": "", "startLine": 1, "endLine": 1, "severity": "nit", "title": "t", "body": "b"},
+		{"filePath": "a.go", "startLine": 1, "endLine": 1, "severity": "nit", "title": " ", "body": "b"}
+	]}` + "\n```"
+
+	// act
+	comments, dropped, err := parseFileComments(content)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(comments) != 1 || dropped != 4 {
+		t.Fatalf("expected 1 comment and 4 dropped, got %d and %d", len(comments), dropped)
+	}
+}
+
+func TestParseFileComments_whenRuleCitedUnderEitherName_shouldKeepIt(t *testing.T) {
+	// arrange
+	content := `{"comments": [
+		{"filePath": "a.go", "startLine": 1, "endLine": 1, "severity": "issue", "title": "t", "body": "b", "rule": " Wrap errors with context. "},
+		{"filePath": "a.go", "startLine": 2, "endLine": 2, "severity": "nit", "title": "t", "body": "b", "guidelineRef": "No naked returns."},
+		{"filePath": "a.go", "startLine": 3, "endLine": 3, "severity": "nit", "title": "t", "body": "b"}
+	]}`
+
+	// act
+	comments, _, err := parseFileComments(content)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := []string{"Wrap errors with context.", "No naked returns.", ""}
+	for i, comment := range comments {
+		if comment.Rule != want[i] {
+			t.Fatalf("comment %d: expected rule %q, got %q", i, want[i], comment.Rule)
+		}
+	}
+}
+
+func TestRun_whenOneFileFails_shouldKeepOtherFilesAndRecordError(t *testing.T) {
+	// arrange
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	reply := `{"comments":[{"filePath":"a.go","startLine":1,"endLine":1,"severity":"ISSUE","title":"t","body":"b"}],` +
+		`"verdict":{"decision":"GO","summary":"ok","rationale":[]}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "b.go") {
+			http.Error(w, "context length exceeded", http.StatusBadRequest)
+			return
+		}
+		payload, _ := json.Marshal(map[string]any{"choices": []any{map[string]any{"message": map[string]string{"content": reply}}}})
+		_, _ = w.Write(payload)
+	}))
+	defer server.Close()
+	hunk := []git.DiffHunk{{OldStart: 1, OldLines: 1, NewStart: 1, NewLines: 1, Lines: []git.DiffLine{{Kind: git.DiffLineAdd, Text: "x", NewLine: 1}}}}
+	files := []git.DiffFile{{Path: "a.go", Hunks: hunk}, {Path: "b.go", Hunks: hunk}}
+	var progressed []Progress
+
+	// act
+	result, err := Run(context.Background(), llm.NewClient("key", server.URL), files, RunOptions{NoCache: true, MaxConcurrency: 1}, func(p Progress) {
+		progressed = append(progressed, p)
+	})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(result.Comments) != 1 || result.Comments[0].FilePath != "a.go" {
+		t.Fatalf("expected the a.go comment to survive, got %+v", result.Comments)
+	}
+	if !strings.Contains(result.FileErrors["b.go"], "context length exceeded") {
+		t.Fatalf("expected b.go error to be recorded, got %v", result.FileErrors)
+	}
+	if len(progressed) != 2 || progressed[1].Completed != 2 || progressed[1].Failed != 1 {
+		t.Fatalf("expected progress to advance past the failure, got %+v", progressed)
+	}
+}
+
+func TestReviewFileDiff_whenFirstAnswerIsNotJSON_shouldRetryOnceWithReminder(t *testing.T) {
+	// arrange
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	calls := 0
+	sawReminder := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		sawReminder = strings.Contains(string(body), invalidJSONReminder)
+		content := "Sure! Here are my findings."
+		if calls > 1 {
+			content = `{"comments":[{"filePath":"a.go","startLine":1,"endLine":1,"severity":"NIT","title":"t","body":"b"}]}`
+		}
+		payload, _ := json.Marshal(map[string]any{"choices": []any{map[string]any{"message": map[string]string{"content": content}}}})
+		_, _ = w.Write(payload)
+	}))
+	defer server.Close()
+
+	// act
+	comments, _, err := reviewFileDiff(context.Background(), llm.NewClient("key", server.URL), RunOptions{Model: "m"}, "a.go", "", "diff", "")
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if calls != 2 || !sawReminder || len(comments) != 1 {
+		t.Fatalf("expected one retry with the reminder, got calls=%d reminder=%v comments=%d", calls, sawReminder, len(comments))
+	}
+}
+
+func TestReviewFileDiff_whenFirstAnswerIsTruncated_shouldFailWithoutRetry(t *testing.T) {
+	// arrange
+	calls := 0
+	client := fakeChatClient{
+		fileReply: func(llm.ChatRequest) (string, error) {
+			calls++
+			return `{"comments":[{"filePath":"a.go","startLine":1,"endLine":1,"severity":"NIT","title":"t","bo`, nil
+		},
+	}
+
+	// act
+	_, _, err := reviewFileDiff(context.Background(), client, RunOptions{Model: "m", MaxTokens: 512}, "a.go", "", "Diff:\n+x", "")
+
+	// assert
+	if err == nil || !strings.Contains(err.Error(), "max_tokens=512") {
+		t.Fatalf("expected a max_tokens hint, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected no retry for a truncated answer, got %d calls", calls)
+	}
+}
+
+func TestChunkDiffFile_whenOverBudget_shouldGroupWholeHunks(t *testing.T) {
+	// arrange
+	hunk := func(lines int) git.DiffHunk {
+		return git.DiffHunk{Lines: make([]git.DiffLine, lines)}
+	}
+	file := git.DiffFile{Path: "big.go", Hunks: []git.DiffHunk{hunk(40), hunk(50), hunk(200), hunk(10)}}
+
+	// act
+	chunks := chunkDiffFile(file, 100)
+
+	// assert
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 chunks, got %d", len(chunks))
+	}
+	if len(chunks[0].Hunks) != 2 || len(chunks[1].Hunks) != 1 || len(chunks[2].Hunks) != 1 {
+		t.Fatalf("unexpected grouping: %d, %d, %d hunks", len(chunks[0].Hunks), len(chunks[1].Hunks), len(chunks[2].Hunks))
+	}
+	if chunks[2].Path != "big.go" {
+		t.Fatalf("expected chunks to keep the file path, got %q", chunks[2].Path)
+	}
+}
+
+func TestCommentsWithinDiff_whenLinesOutsideHunksOrOtherFile_shouldDropAndCount(t *testing.T) {
+	// arrange
+	file := git.DiffFile{Path: "a.go", Hunks: []git.DiffHunk{{Lines: []git.DiffLine{
+		{Kind: git.DiffLineContext, OldLine: 9, NewLine: 10},
+		{Kind: git.DiffLineAdd, NewLine: 11},
+		{Kind: git.DiffLineDel, OldLine: 10},
+	}}}}
+	comments := []Comment{
+		{FilePath: "a.go", Title: "on added line", StartLine: 11, EndLine: 11},
+		{FilePath: "./a.go", Title: "range overlapping context", StartLine: 5, EndLine: 10},
+		{FilePath: "a.go", Title: "hallucinated", StartLine: 142, EndLine: 142},
+		{FilePath: "b.go", Title: "other file", StartLine: 11, EndLine: 11},
+	}
+
+	// act
+	kept, dropped := commentsWithinDiff(file, comments)
+
+	// assert
+	if dropped != 2 || len(kept) != 2 {
+		t.Fatalf("expected 2 dropped and 2 kept, got %d dropped, kept %+v", dropped, kept)
+	}
+	if kept[0].Title != "on added line" || kept[1].Title != "range overlapping context" || kept[1].FilePath != "a.go" {
+		t.Fatalf("expected in-diff comments kept in order on the reviewed path, got %+v", kept)
+	}
+}
+
+func TestMergeOverlapping_whenSameFileLinesOverlap_shouldKeepMostSevereWithContext(t *testing.T) {
+	// arrange
+	comments := []Comment{
+		{ID: "1", FilePath: "a.go", StartLine: 10, EndLine: 12, Severity: SeverityBlocker, Title: "Nil deref", Body: "p can be nil", Tags: []string{"bug"}},
+		{ID: "2", FilePath: "a.go", StartLine: 12, EndLine: 12, Severity: SeverityNit, Title: "Naming", Body: "rename p", Tags: []string{"style"}},
+		{ID: "3", FilePath: "b.go", StartLine: 12, EndLine: 12, Severity: SeverityNit, Title: "Other file", Body: "unrelated"},
+	}
+
+	// act
+	merged := mergeOverlapping(comments)
+
+	// assert
+	if len(merged) != 2 || merged[0].ID != "1" || merged[1].ID != "3" {
+		t.Fatalf("expected the a.go comments merged into the blocker, got %+v", merged)
+	}
+	if !strings.Contains(merged[0].Body, "Also noted (NIT): Naming") || len(merged[0].Tags) != 2 {
+		t.Fatalf("expected the nit appended as context with its tag, got %+v", merged[0])
+	}
+}
+
+func TestCommentsAtLeast_whenFloorIsSuggestion_shouldDropNits(t *testing.T) {
+	// arrange
+	comments := []Comment{
+		{ID: "nit", Severity: SeverityNit},
+		{ID: "suggestion", Severity: SeveritySuggestion},
+		{ID: "blocker", Severity: SeverityBlocker},
+	}
+
+	// act
+	kept := commentsAtLeast(comments, SeveritySuggestion)
+
+	// assert
+	if len(kept) != 2 || kept[0].ID != "suggestion" || kept[1].ID != "blocker" {
+		t.Fatalf("expected suggestion and blocker, got %+v", kept)
+	}
+	if len(comments) != 3 {
+		t.Fatalf("expected the input to be left alone, got %+v", comments)
+	}
+}
+
+func TestStripCodeFence_whenJSONIsFencedOrWrapped_shouldExtractFirstBalancedValue(t *testing.T) {
+	// arrange
+	payload := `{"comments": [{"title": "Use } carefully", "body": "a \"quoted\" {brace"}]}`
+	cases := []string{
+		payload,
+		"```json\n" + payload + "\n```",
+		"```\n" + payload + "\n```\n\n```\n{\"other\": true}\n```",
+		"Here is the review:\n" + payload + "\nLet me know if you need anything else {or more}.",
+	}
+
+	for _, content := range cases {
+		// act
+		got := stripCodeFence(content)
+
+		// assert
+		if got != payload {
+			t.Fatalf("stripCodeFence(%q) = %q, want %q", content, got, payload)
+		}
+	}
+}
+
+func TestStripCodeFence_whenProseHasBracketsBeforeJSON_shouldSkipThem(t *testing.T) {
+	// arrange
+	payload := `{"verdict": {"decision": "GO", "summary": "ok", "rationale": ["fine"]}}`
+	content := "See [docs] and {the notes] below, then {this}:\n```json\n" + payload + "\n```"
+
+	// act
+	got := stripCodeFence(content)
+
+	// assert
+	if got != payload {
+		t.Fatalf("stripCodeFence(%q) = %q, want %q", content, got, payload)
+	}
+}
+
+// fakeChatClient answers file reviews and the verdict request with canned content.
+type fakeChatClient struct {
+	fileReply    func(req llm.ChatRequest) (string, error)
+	verdictReply string
+}
+
+func (f fakeChatClient) ChatCompletion(_ context.Context, req llm.ChatRequest) (string, error) {
+	if strings.Contains(req.Messages[len(req.Messages)-1].Content, "Diff:") || len(req.Messages) > 2 {
+		return f.fileReply(req)
+	}
+	return f.verdictReply, nil
+}
+
+func (f fakeChatClient) ChatCompletionStream(ctx context.Context, req llm.ChatRequest, onDelta func(string)) (string, error) {
+	content, err := f.ChatCompletion(ctx, req)
+	onDelta(content)
+	return content, err
+}
+
+func fakeDiffFiles(paths ...string) []git.DiffFile {
+	hunk := []git.DiffHunk{{OldStart: 1, OldLines: 1, NewStart: 1, NewLines: 1, Lines: []git.DiffLine{{Kind: git.DiffLineAdd, Text: "x", NewLine: 1}}}}
+	files := make([]git.DiffFile, 0, len(paths))
+	for _, path := range paths {
+		files = append(files, git.DiffFile{Path: path, Hunks: hunk})
+	}
+	return files
+}
+
+func TestRun_whenRepliesRepeatAndContainMalformedEntries_shouldDedupeAndCountDropped(t *testing.T) {
+	// arrange
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	client := fakeChatClient{
+		fileReply: func(llm.ChatRequest) (string, error) {
+			return `{"comments":[
+				{"filePath":"a.go","startLine":1,"endLine":1,"severity":"ISSUE","title":"t","body":"b"},
+				{"filePath":"a.go","startLine":1,"endLine":1,"severity":"ISSUE","title":"t","body":"b"},
+				{"filePath":"a.go","startLine":0,"endLine":1,"severity":"NIT","title":"t","body":"b"}
+			]}`, nil
+		},
+		verdictReply: `{"verdict":{"decision":"GO","summary":"fine","rationale":[]}}`,
+	}
+
+	// act
+	result, err := Run(context.Background(), client, fakeDiffFiles("a.go"), RunOptions{NoCache: true}, nil)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(result.Comments) != 1 || result.Dropped != 1 {
+		t.Fatalf("expected 1 comment and 1 dropped, got %d and %d", len(result.Comments), result.Dropped)
+	}
+	if result.Verdict.Decision != DecisionGo || result.Verdict.Summary != "fine" {
+		t.Fatalf("expected the model verdict, got %+v", result.Verdict)
+	}
+}
+
+func TestRun_whenFileReplyIsEmpty_shouldRecordFileError(t *testing.T) {
+	// arrange
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	client := fakeChatClient{
+		fileReply: func(req llm.ChatRequest) (string, error) {
+			if strings.Contains(req.Messages[1].Content, "b.go") {
+				return "", nil
+			}
+			return `{"comments":[]}`, nil
+		},
+		verdictReply: `{"verdict":{"decision":"GO","summary":"fine","rationale":[]}}`,
+	}
+
+	// act
+	result, err := Run(context.Background(), client, fakeDiffFiles("a.go", "b.go"), RunOptions{NoCache: true}, nil)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, ok := result.FileErrors["b.go"]; !ok || len(result.FileErrors) != 1 {
+		t.Fatalf("expected only b.go to fail, got %v", result.FileErrors)
+	}
+}
+
+func TestRun_whenEveryFileFails_shouldReturnSortedErrorAndFileErrors(t *testing.T) {
+	// arrange
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	client := fakeChatClient{
+		fileReply: func(llm.ChatRequest) (string, error) {
+			return "", errors.New("rate limited")
+		},
+	}
+
+	// act
+	result, err := Run(context.Background(), client, fakeDiffFiles("c.go", "a.go", "b.go"), RunOptions{NoCache: true}, nil)
+
+	// assert
+	if err == nil {
+		t.Fatal("expected an error when every file fails")
+	}
+	message := err.Error()
+	a, b, c := strings.Index(message, "a.go: "), strings.Index(message, "b.go: "), strings.Index(message, "c.go: ")
+	if a < 0 || b < a || c < b {
+		t.Fatalf("expected every failing path in sorted order, got %q", message)
+	}
+	if len(result.FileErrors) != 3 || !strings.Contains(result.FileErrors["b.go"], "rate limited") {
+		t.Fatalf("expected file errors for every path, got %v", result.FileErrors)
+	}
+}
+
+func TestRun_whenVerdictIsMalformed_shouldFallBackToRuleDecision(t *testing.T) {
+	// arrange
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	client := fakeChatClient{
+		fileReply: func(llm.ChatRequest) (string, error) {
+			return `{"comments":[{"filePath":"a.go","startLine":1,"endLine":1,"severity":"BLOCKER","title":"t","body":"b"}]}`, nil
+		},
+		verdictReply: "I think this looks fine.",
+	}
+
+	// act
+	result, err := Run(context.Background(), client, fakeDiffFiles("a.go"), RunOptions{NoCache: true}, nil)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Verdict.Decision != DecisionNoGo || result.Verdict.Summary != "Verdict unavailable due to parsing error." {
+		t.Fatalf("expected the rule-based fallback verdict, got %+v", result.Verdict)
+	}
+}
+
+func TestRetryFailed_whenFailedFileNowSucceeds_shouldMergeCommentsAndClearError(t *testing.T) {
+	// arrange
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	reviewed := []string{}
+	client := fakeChatClient{
+		fileReply: func(req llm.ChatRequest) (string, error) {
+			reviewed = append(reviewed, req.Messages[1].Content)
+			return `{"comments":[{"filePath":"b.go","startLine":1,"endLine":1,"severity":"ISSUE","title":"t","body":"b"}]}`, nil
+		},
+		verdictReply: `{"verdict":{"decision":"GO","summary":"fine","rationale":[]}}`,
+	}
+	kept := Comment{ID: "edited", FilePath: "a.go", StartLine: 1, EndLine: 1, Severity: SeverityNit, Title: "edited", Body: "b"}
+	prior := Result{Comments: []Comment{kept}, FileErrors: map[string]string{"b.go": "timeout"}, Dismissed: 2}
+
+	// act
+	result, err := RetryFailed(context.Background(), client, fakeDiffFiles("a.go", "b.go"), prior, RunOptions{NoCache: true}, nil)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(reviewed) != 1 || !strings.Contains(reviewed[0], "b/b.go") {
+		t.Fatalf("expected only b.go to be re-reviewed, got %d request(s)", len(reviewed))
+	}
+	if len(result.Comments) != 2 || len(result.FileErrors) != 0 || result.Dismissed != 2 {
+		t.Fatalf("expected both comments and no errors, got %+v", result)
+	}
+	if result.Verdict.Stats.Issue != 1 || result.Verdict.Stats.Nit != 1 {
+		t.Fatalf("expected stats over all comments, got %+v", result.Verdict.Stats)
+	}
+}
+
+func TestValidateTemperature_whenAtOrPastBounds_shouldRejectOnlyOutsideRange(t *testing.T) {
+	// arrange
+	cases := []struct {
+		temperature float64
+		valid       bool
+	}{
+		{temperature: -0.1, valid: false},
+		{temperature: 0, valid: true},
+		{temperature: 2, valid: true},
+		{temperature: 2.1, valid: false},
+	}
+
+	for _, tc := range cases {
+		// act
+		err := ValidateTemperature(tc.temperature)
+
+		// assert
+		if (err == nil) != tc.valid {
+			t.Fatalf("temperature %g: expected valid=%v, got error %v", tc.temperature, tc.valid, err)
+		}
+	}
+}
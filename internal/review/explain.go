@@ -0,0 +1,57 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/git"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/llm"
+)
+
+// BuildExplainMessages builds a single follow-up turn about one review comment: the reviewer's
+// question, with the comment and the diff of its file as context.
+func BuildExplainMessages(comment Comment, diff, question string) []llm.Message {
+	system := strings.Join([]string{
+		"You are a expert senior software engineer who reviewed this code.",
+		"A reviewer is asking about one of your review comments.",
+		"Answer briefly in plain text or Markdown. If the comment is wrong, say so.",
+	}, " ")
+
+	lines := []string{
+		fmt.Sprintf("Your comment on %s:%d (%s): %s", comment.FilePath, comment.StartLine, comment.Severity, comment.Title),
+		comment.Body,
+	}
+	if comment.Suggestion != nil && strings.TrimSpace(*comment.Suggestion) != "" {
+		lines = append(lines, "", "Your suggestion:", *comment.Suggestion)
+	}
+	lines = append(lines, "", "Diff:", diff, "", "Question:", question)
+
+	return []llm.Message{
+		{Role: "system", Content: system},
+		{Role: "user", Content: strings.Join(lines, "\n")},
+	}
+}
+
+// Explain sends one follow-up question about comment to the model and returns its reply. file is
+// the comment's parsed diff; temperature nil means DefaultTemperature.
+func Explain(ctx context.Context, client ChatClient, model string, temperature *float64, comment Comment, file git.DiffFile, question string) (string, error) {
+	question = strings.TrimSpace(question)
+	if question == "" {
+		return "", errors.New("question is required")
+	}
+	reply, err := client.ChatCompletion(ctx, llm.ChatRequest{
+		Model:       model,
+		Messages:    BuildExplainMessages(comment, RenderUnifiedDiffFile(file), question),
+		Temperature: RunOptions{Temperature: temperature}.temperature(),
+	})
+	if err != nil {
+		return "", err
+	}
+	reply = strings.TrimSpace(reply)
+	if reply == "" {
+		return "", errors.New("empty reply")
+	}
+	return reply, nil
+}
@@ -0,0 +1,36 @@
+package review
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/llm"
+)
+
+func TestExplain_whenAsked_shouldSendCommentDiffAndQuestionInOneTurn(t *testing.T) {
+	// arrange
+	var sent llm.ChatRequest
+	client := fakeChatClient{fileReply: func(req llm.ChatRequest) (string, error) {
+		sent = req
+		return "  Because the error is dropped.  ", nil
+	}}
+	comment := Comment{FilePath: "a.go", StartLine: 1, Severity: SeverityIssue, Title: "Unchecked error", Body: "err is ignored"}
+
+	// act
+	reply, err := Explain(context.Background(), client, "test-model", nil, comment, fakeDiffFiles("a.go")[0], "Why is this an issue?")
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if reply != "Because the error is dropped." {
+		t.Fatalf("expected the trimmed reply, got %q", reply)
+	}
+	user := sent.Messages[len(sent.Messages)-1].Content
+	for _, want := range []string{"Unchecked error", "diff --git a/a.go b/a.go", "Why is this an issue?"} {
+		if !strings.Contains(user, want) {
+			t.Fatalf("expected prompt to contain %q, got:\n%s", want, user)
+		}
+	}
+}
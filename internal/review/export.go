@@ -0,0 +1,90 @@
+package review
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ReportFormat selects how WriteReport serializes a Result.
+type ReportFormat string
+
+const (
+	ReportMarkdown ReportFormat = "markdown"
+	ReportJSON     ReportFormat = "json"
+	ReportSARIF    ReportFormat = "sarif"
+)
+
+// DefaultReportName is the file name used when a report is exported without an explicit path.
+func DefaultReportName(format ReportFormat) string {
+	switch format {
+	case ReportJSON:
+		return "review-report.json"
+	case ReportSARIF:
+		return "review-report.sarif"
+	default:
+		return "review-report.md"
+	}
+}
+
+// ParseReportFormat resolves a --format value; empty picks the format from the path's extension,
+// falling back to Markdown.
+func ParseReportFormat(value, path string) (ReportFormat, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "":
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".json":
+			return ReportJSON, nil
+		case ".sarif":
+			return ReportSARIF, nil
+		default:
+			return ReportMarkdown, nil
+		}
+	case "markdown", "md":
+		return ReportMarkdown, nil
+	case "json":
+		return ReportJSON, nil
+	case "sarif":
+		return ReportSARIF, nil
+	default:
+		return "", fmt.Errorf("unknown report format %q (want markdown, json or sarif)", value)
+	}
+}
+
+// RenderReport serializes res in the given format.
+func RenderReport(res Result, format ReportFormat) ([]byte, error) {
+	switch format {
+	case ReportMarkdown:
+		return []byte(RenderMarkdown(res) + "\n"), nil
+	case ReportJSON:
+		data, err := MarshalResult(res)
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	case ReportSARIF:
+		data, err := json.MarshalIndent(ToSARIF(res), "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	default:
+		return nil, fmt.Errorf("unknown report format %q", format)
+	}
+}
+
+// WriteReport renders res and writes it to path, creating parent directories as needed.
+func WriteReport(path string, format ReportFormat, res Result) error {
+	data, err := RenderReport(res, format)
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, data, 0o644)
+}
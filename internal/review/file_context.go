@@ -0,0 +1,59 @@
+package review
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/git"
+)
+
+const (
+	// FileContextWindow is how many lines of the full file are shown above and below each hunk.
+	FileContextWindow = 20
+	// MaxFileContextBytes caps the surrounding context added to one review request.
+	MaxFileContextBytes = 8000
+)
+
+// FileContentReader returns the full content of a changed file as of the reviewed revision.
+type FileContentReader func(path string) (string, error)
+
+// buildFileContext cuts a window of FileContextWindow lines around each hunk's new-side range out of
+// the full file, numbered with new-side line numbers. Overlapping windows are merged and the result
+// is capped at MaxFileContextBytes.
+func buildFileContext(content string, hunks []git.DiffHunk) string {
+	if content == "" || len(hunks) == 0 {
+		return ""
+	}
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+
+	type window struct{ start, end int }
+	var windows []window
+	for _, hunk := range hunks {
+		start := max(hunk.NewStart-FileContextWindow, 1)
+		end := min(hunk.NewStart+hunk.NewLines-1+FileContextWindow, len(lines))
+		if start > end {
+			continue
+		}
+		if n := len(windows); n > 0 && start <= windows[n-1].end+1 {
+			windows[n-1].end = max(windows[n-1].end, end)
+			continue
+		}
+		windows = append(windows, window{start, end})
+	}
+
+	var builder strings.Builder
+	for i, w := range windows {
+		if i > 0 {
+			builder.WriteString("...\n")
+		}
+		for line := w.start; line <= w.end; line++ {
+			entry := fmt.Sprintf("%5d | %s\n", line, lines[line-1])
+			if builder.Len()+len(entry) > MaxFileContextBytes {
+				builder.WriteString("... (context truncated)\n")
+				return builder.String()
+			}
+			builder.WriteString(entry)
+		}
+	}
+	return builder.String()
+}
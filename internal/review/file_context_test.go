@@ -0,0 +1,37 @@
+package review
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/git"
+)
+
+func TestBuildFileContext_whenHunksAreClose_shouldMergeWindows(t *testing.T) {
+	// arrange
+	var lines []string
+	for i := 1; i <= 200; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	content := strings.Join(lines, "\n") + "\n"
+	hunks := []git.DiffHunk{
+		{NewStart: 30, NewLines: 2},
+		{NewStart: 60, NewLines: 1},
+		{NewStart: 150, NewLines: 1},
+	}
+
+	// act
+	context := buildFileContext(content, hunks)
+
+	// assert
+	if strings.Count(context, "...\n") != 1 {
+		t.Fatalf("expected the first two windows to merge into one gap marker, got:\n%s", context)
+	}
+	if !strings.HasPrefix(context, "   10 | line 10\n") || !strings.Contains(context, "  170 | line 170\n") {
+		t.Fatalf("expected windows of %d lines around hunks, got:\n%s", FileContextWindow, context)
+	}
+	if strings.Contains(context, "line 9\n") || strings.Contains(context, "line 171\n") {
+		t.Fatalf("expected lines outside the windows to be left out, got:\n%s", context)
+	}
+}
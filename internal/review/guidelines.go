@@ -4,6 +4,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
@@ -40,12 +41,19 @@ func ScanGuidelineFiles(repoRoot string, extraPaths []string) ([]string, error)
 		addPath(filepath.Join(profileDir, entry.Name()))
 	}
 
+	var fetchErrs []error
 	for _, path := range extraPaths {
 		resolved, err := ResolveGuidelinePath(repoRoot, path)
 		if err != nil {
 			continue
 		}
-		if isRegularFile(resolved) {
+		if IsGuidelineURL(resolved) {
+			if _, err := ReadGuideline(resolved); err != nil {
+				fetchErrs = append(fetchErrs, err)
+				continue
+			}
+			addPath(resolved)
+		} else if isRegularFile(resolved) {
 			addPath(resolved)
 		}
 	}
@@ -55,7 +63,8 @@ func ScanGuidelineFiles(repoRoot string, extraPaths []string) ([]string, error)
 		paths = append(paths, path)
 	}
 	sort.Strings(paths)
-	return paths, nil
+	// Unreachable URLs are left out but reported, so the local profiles stay usable.
+	return paths, errors.Join(fetchErrs...)
 }
 
 func ResolveGuidelinePath(repoRoot, input string) (string, error) {
@@ -63,12 +72,42 @@ func ResolveGuidelinePath(repoRoot, input string) (string, error) {
 		return "", errors.New("guideline path is empty")
 	}
 	path := input
+	if trimmed := strings.TrimSpace(input); IsGuidelineURL(trimmed) {
+		return trimmed, nil
+	}
 	if !filepath.IsAbs(path) {
 		path = filepath.Join(repoRoot, path)
 	}
 	return filepath.Clean(path), nil
 }
 
+// ResolveGuidelinePaths is ResolveGuidelinePath for input that may be a glob such as
+// docs/guidelines/*.md, expanded relative to repoRoot. A glob returns every matching .md file,
+// sorted, and is an error when nothing matches; a plain path or URL is returned as is.
+func ResolveGuidelinePaths(repoRoot, input string) ([]string, error) {
+	resolved, err := ResolveGuidelinePath(repoRoot, input)
+	if err != nil {
+		return nil, err
+	}
+	if IsGuidelineURL(resolved) || !strings.ContainsAny(input, "*?[") {
+		return []string{resolved}, nil
+	}
+	matches, err := filepath.Glob(resolved)
+	if err != nil {
+		return nil, fmt.Errorf("guideline glob %q: %w", input, err)
+	}
+	paths := make([]string, 0, len(matches))
+	for _, match := range matches {
+		if strings.ToLower(filepath.Ext(match)) == ".md" && isRegularFile(match) {
+			paths = append(paths, match)
+		}
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("guideline glob %q matches no .md files", input)
+	}
+	return paths, nil
+}
+
 func HashGuidelines(paths []string, freeText string) (string, error) {
 	paths = append([]string(nil), paths...)
 	sort.Strings(paths)
@@ -78,7 +117,7 @@ func HashGuidelines(paths []string, freeText string) (string, error) {
 
 	hasher := sha256.New()
 	for _, path := range paths {
-		data, err := os.ReadFile(path)
+		data, err := ReadGuideline(path)
 		if err != nil {
 			return "", err
 		}
@@ -105,13 +144,42 @@ func isRegularFile(path string) bool {
 	return info.Mode().IsRegular()
 }
 
+// MaxFileHintBytes bounds how much of a per-file sidecar is injected into a prompt.
+const MaxFileHintBytes = 4096
+
+// FileHintPath returns the repo-relative sidecar path carrying review hints for a diff file
+// (e.g. main.go.review.md).
+func FileHintPath(filePath string) string {
+	return filePath + ".review.md"
+}
+
+// LoadFileHint reads the per-file sidecar for filePath through read, so the hint comes from the
+// reviewed revision like the file itself, truncated to MaxFileHintBytes. A missing sidecar, or no
+// reader (a patch has no revision), is not an error and yields an empty hint.
+func LoadFileHint(read FileContentReader, filePath string) (string, error) {
+	if read == nil || strings.TrimSpace(filePath) == "" {
+		return "", nil
+	}
+	data, err := read(FileHintPath(filePath))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", err
+	}
+	if len(data) > MaxFileHintBytes {
+		data = data[:MaxFileHintBytes]
+	}
+	return strings.TrimSpace(data), nil
+}
+
 func LoadGuidelines(paths []string, freeText string) (string, error) {
 	paths = append([]string(nil), paths...)
 	sort.Strings(paths)
 
 	var builder strings.Builder
 	for _, path := range paths {
-		data, err := os.ReadFile(path)
+		data, err := ReadGuideline(path)
 		if err != nil {
 			return "", err
 		}
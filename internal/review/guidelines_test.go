@@ -0,0 +1,122 @@
+package review
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveGuidelinePaths_whenGlob_shouldReturnMatchingMarkdownFiles(t *testing.T) {
+	// arrange
+	root := t.TempDir()
+	dir := filepath.Join(root, "docs", "guidelines")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"b.md", "a.md", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("rule"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// act
+	paths, err := ResolveGuidelinePaths(root, "docs/guidelines/*")
+	_, emptyErr := ResolveGuidelinePaths(root, "docs/missing/*.md")
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(paths) != 2 || filepath.Base(paths[0]) != "a.md" || filepath.Base(paths[1]) != "b.md" {
+		t.Fatalf("expected a.md and b.md, got %v", paths)
+	}
+	if emptyErr == nil || !strings.Contains(emptyErr.Error(), "matches no .md files") {
+		t.Fatalf("expected an error for a glob without matches, got %v", emptyErr)
+	}
+}
+
+func TestReadGuideline_whenURLFetchedOnce_shouldServeLaterReadsFromCache(t *testing.T) {
+	// arrange
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte("# Org rules\nPrefer small functions."))
+	}))
+	defer server.Close()
+	url := server.URL + "/rules.md"
+
+	// act
+	first, firstErr := ReadGuideline(url)
+	second, secondErr := ReadGuideline(url)
+
+	// assert
+	if firstErr != nil || secondErr != nil {
+		t.Fatalf("expected no errors, got %v and %v", firstErr, secondErr)
+	}
+	if string(first) != string(second) || !strings.Contains(string(first), "Prefer small functions.") {
+		t.Fatalf("expected the fetched content twice, got %q and %q", first, second)
+	}
+	if requests != 1 {
+		t.Fatalf("expected one request with the second read cached, got %d", requests)
+	}
+}
+
+func TestReadGuideline_whenURLExceedsSizeLimit_shouldReturnError(t *testing.T) {
+	// arrange
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(strings.Repeat("a", maxRemoteGuidelineBytes+1)))
+	}))
+	defer server.Close()
+
+	// act
+	data, err := ReadGuideline(server.URL + "/huge.md")
+
+	// assert
+	if err == nil || !strings.Contains(err.Error(), "larger than") {
+		t.Fatalf("expected a size limit error, got %d bytes and %v", len(data), err)
+	}
+}
+
+func TestLoadFileHint_whenSidecarIsMissing_shouldReturnEmptyHint(t *testing.T) {
+	// arrange
+	var requested string
+	read := func(path string) (string, error) {
+		requested = path
+		return "", fmt.Errorf("git show: path does not exist: %w", os.ErrNotExist)
+	}
+
+	// act
+	hint, err := LoadFileHint(read, "cmd/main.go")
+
+	// assert
+	if err != nil || hint != "" {
+		t.Fatalf("expected an empty hint and no error, got %q, %v", hint, err)
+	}
+	if requested != "cmd/main.go.review.md" {
+		t.Fatalf("expected the sidecar to be read through the reader, got %q", requested)
+	}
+}
+
+func TestLoadFileHint_whenSidecarExceedsCap_shouldTruncateToMaxBytes(t *testing.T) {
+	// arrange
+	read := func(string) (string, error) {
+		return strings.Repeat("a", MaxFileHintBytes) + "overflow", nil
+	}
+
+	// act
+	hint, err := LoadFileHint(read, "main.go")
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(hint) != MaxFileHintBytes || strings.Contains(hint, "overflow") {
+		t.Fatalf("expected the hint cut at %d bytes, got %d", MaxFileHintBytes, len(hint))
+	}
+}
@@ -0,0 +1,112 @@
+package review
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/config"
+)
+
+// historyPath is where the latest result for a branch of a repository, reviewed with one guideline
+// set, is kept.
+func historyPath(repoRoot, branch, guidelineHash string) (string, error) {
+	dir, err := config.CacheDir()
+	if err != nil {
+		return "", err
+	}
+	hasher := sha256.New()
+	for _, part := range []string{repoRoot, branch, guidelineHash} {
+		_, _ = hasher.Write([]byte(part))
+		_, _ = hasher.Write([]byte{0})
+	}
+	return filepath.Join(dir, "history", hex.EncodeToString(hasher.Sum(nil))+".json"), nil
+}
+
+// LoadPreviousResult returns the result last stored with SaveResult for the same repository,
+// branch and guideline hash; ok is false when there is none.
+func LoadPreviousResult(repoRoot, branch, guidelineHash string) (Result, bool, error) {
+	path, err := historyPath(repoRoot, branch, guidelineHash)
+	if err != nil {
+		return Result{}, false, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Result{}, false, nil
+	}
+	if err != nil {
+		return Result{}, false, err
+	}
+	res, err := UnmarshalResult(data)
+	if err != nil {
+		return Result{}, false, err
+	}
+	return res, true, nil
+}
+
+// SaveResult stores res as the latest result for the repository, branch and guideline hash,
+// replacing the one before it.
+func SaveResult(repoRoot, branch string, res Result) error {
+	path, err := historyPath(repoRoot, branch, res.GuidelineHash)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := MarshalResult(res)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return errors.Join(err, os.Remove(tmp))
+	}
+	return nil
+}
+
+// Comparison splits two runs' comments by StableCommentID: New only appear in the current run,
+// Resolved only in the previous one, and Unchanged in both.
+type Comparison struct {
+	New       []Comment
+	Resolved  []Comment
+	Unchanged []Comment
+}
+
+// CompareResults compares the comments of a previous and the current run of the same review.
+func CompareResults(previous, current Result) Comparison {
+	seen := make(map[string]bool, len(previous.Comments))
+	for _, comment := range previous.Comments {
+		seen[commentKey(comment)] = true
+	}
+	var comparison Comparison
+	kept := make(map[string]bool, len(current.Comments))
+	for _, comment := range current.Comments {
+		key := commentKey(comment)
+		kept[key] = true
+		if seen[key] {
+			comparison.Unchanged = append(comparison.Unchanged, comment)
+		} else {
+			comparison.New = append(comparison.New, comment)
+		}
+	}
+	for _, comment := range previous.Comments {
+		if !kept[commentKey(comment)] {
+			comparison.Resolved = append(comparison.Resolved, comment)
+		}
+	}
+	return comparison
+}
+
+func commentKey(comment Comment) string {
+	if comment.ID != "" {
+		return comment.ID
+	}
+	return StableCommentID(comment)
+}
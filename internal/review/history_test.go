@@ -0,0 +1,40 @@
+package review
+
+import "testing"
+
+func TestLoadPreviousResult_whenSavedForBranch_shouldRoundTripAndCompare(t *testing.T) {
+	// arrange
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	fixed := Comment{FilePath: "a.go", StartLine: 1, EndLine: 1, Severity: SeverityIssue, Title: "Leak", Body: "close the file"}
+	kept := Comment{FilePath: "a.go", StartLine: 5, EndLine: 5, Severity: SeverityNit, Title: "Typo", Body: "spelling", Rule: "Spell check."}
+	added := Comment{FilePath: "b.go", StartLine: 2, EndLine: 2, Severity: SeverityBlocker, Title: "Panic", Body: "nil map"}
+	for _, c := range []*Comment{&fixed, &kept, &added} {
+		c.ID = StableCommentID(*c)
+	}
+	previous := Result{Model: "m", GuidelineHash: "g", Verdict: Verdict{Decision: DecisionNoGo}, Comments: []Comment{fixed, kept}}
+	if err := SaveResult("/repo", "feature", previous); err != nil {
+		t.Fatalf("expected no error saving, got %v", err)
+	}
+
+	// act
+	loaded, ok, err := LoadPreviousResult("/repo", "feature", "g")
+	_, otherOK, otherErr := LoadPreviousResult("/repo", "main", "g")
+	comparison := CompareResults(loaded, Result{Comments: []Comment{kept, added}})
+
+	// assert
+	if err != nil || !ok || otherErr != nil || otherOK {
+		t.Fatalf("expected only the saved branch to load, got ok=%v err=%v, other ok=%v err=%v", ok, err, otherOK, otherErr)
+	}
+	if loaded.Verdict.Decision != DecisionNoGo || len(loaded.Comments) != 2 {
+		t.Fatalf("expected the saved result back, got %+v", loaded)
+	}
+	if len(comparison.New) != 1 || comparison.New[0].ID != added.ID {
+		t.Fatalf("expected %q to be new, got %+v", added.Title, comparison.New)
+	}
+	if len(comparison.Resolved) != 1 || comparison.Resolved[0].ID != fixed.ID {
+		t.Fatalf("expected %q to be resolved, got %+v", fixed.Title, comparison.Resolved)
+	}
+	if len(comparison.Unchanged) != 1 || comparison.Unchanged[0].Rule != "Spell check." {
+		t.Fatalf("expected %q to be unchanged, got %+v", kept.Title, comparison.Unchanged)
+	}
+}
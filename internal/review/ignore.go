@@ -0,0 +1,60 @@
+package review
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/git"
+)
+
+const ReviewIgnoreFile = ".reviewignore"
+
+// LoadReviewIgnore reads glob patterns from .reviewignore at the repo root, one per line.
+// Blank lines and lines starting with # are skipped. A missing file yields no patterns.
+func LoadReviewIgnore(repoRoot string) ([]string, error) {
+	file, err := os.Open(filepath.Join(repoRoot, ReviewIgnoreFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	patterns := make([]string, 0)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := git.ValidateGlob(line); err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return patterns, nil
+}
+
+// SplitIgnored separates files matching the .reviewignore patterns from those to send for review.
+func SplitIgnored(files []git.DiffFile, patterns []string) ([]git.DiffFile, []git.DiffFile) {
+	if len(patterns) == 0 {
+		return files, nil
+	}
+	reviewable := make([]git.DiffFile, 0, len(files))
+	ignored := make([]git.DiffFile, 0)
+	for _, file := range files {
+		if git.MatchAnyGlob(patterns, file.Path) {
+			ignored = append(ignored, file)
+			continue
+		}
+		reviewable = append(reviewable, file)
+	}
+	return reviewable, ignored
+}
@@ -0,0 +1,73 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestLoadReviewIgnore_whenFileIsMissing_shouldReturnNoPatterns(t *testing.T) {
+	// arrange
+	root := t.TempDir()
+
+	// act
+	patterns, err := LoadReviewIgnore(root)
+
+	// assert
+	if err != nil || len(patterns) != 0 {
+		t.Fatalf("expected no patterns and no error, got %v, %v", patterns, err)
+	}
+}
+
+func TestLoadReviewIgnore_whenFileHasBlankAndCommentLines_shouldSkipThem(t *testing.T) {
+	// arrange
+	root := t.TempDir()
+	content := "# generated code\n\n  vendor/**  \n\t\n*.pb.go\n  # indented comment\n"
+	if err := os.WriteFile(filepath.Join(root, ReviewIgnoreFile), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// act
+	patterns, err := LoadReviewIgnore(root)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !slices.Equal(patterns, []string{"vendor/**", "*.pb.go"}) {
+		t.Fatalf("expected only the two patterns, got %q", patterns)
+	}
+}
+
+func TestLoadReviewIgnore_whenPatternIsInvalid_shouldReturnError(t *testing.T) {
+	// arrange
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, ReviewIgnoreFile), []byte("*.go\ndocs/[a\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// act
+	patterns, err := LoadReviewIgnore(root)
+
+	// assert
+	if err == nil {
+		t.Fatalf("expected an invalid glob error, got patterns %q", patterns)
+	}
+}
+
+func TestSplitIgnored_whenPatternsMatchSomeFiles_shouldSeparateThem(t *testing.T) {
+	// arrange
+	files := fakeDiffFiles("main.go", "api/service.pb.go", "vendor/lib/x.go")
+
+	// act
+	reviewable, ignored := SplitIgnored(files, []string{"*.pb.go", "vendor/**"})
+
+	// assert
+	if len(reviewable) != 1 || reviewable[0].Path != "main.go" {
+		t.Fatalf("expected only main.go to be reviewable, got %+v", reviewable)
+	}
+	if len(ignored) != 2 || ignored[0].Path != "api/service.pb.go" || ignored[1].Path != "vendor/lib/x.go" {
+		t.Fatalf("expected the generated and vendored files to be ignored, got %+v", ignored)
+	}
+}
@@ -0,0 +1,176 @@
+package review
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// ReportSchemaVersion is bumped whenever a JSON report field is renamed or removed.
+const ReportSchemaVersion = 1
+
+// JSONReport is the stable, documented shape written by MarshalResult. Scripts may rely on these
+// field names; add fields rather than renaming them.
+type JSONReport struct {
+	// SchemaVersion is ReportSchemaVersion at the time the report was written.
+	SchemaVersion int `json:"schemaVersion"`
+	// Model is the LLM model that produced the review.
+	Model string `json:"model"`
+	// GuidelineHash identifies the guideline set used; empty when none were selected.
+	GuidelineHash string `json:"guidelineHash,omitempty"`
+	// GeneratedAt is when the review finished, in RFC 3339.
+	GeneratedAt time.Time   `json:"generatedAt"`
+	Verdict     JSONVerdict `json:"verdict"`
+	// Comments holds every finding, including ones deselected for publishing, sorted by file path,
+	// start line and ID.
+	Comments []JSONComment `json:"comments"`
+	// Dropped counts model findings discarded for missing file/line/title/body or lines outside the diff.
+	Dropped int `json:"dropped,omitempty"`
+	// FileErrors maps file paths that could not be reviewed to the error message.
+	FileErrors map[string]string `json:"fileErrors,omitempty"`
+}
+
+type JSONVerdict struct {
+	// Decision is "GO" or "NO_GO".
+	Decision  string    `json:"decision"`
+	Summary   string    `json:"summary"`
+	Rationale []string  `json:"rationale,omitempty"`
+	Stats     JSONStats `json:"stats"`
+	// Manual is true when a reviewer overrode the automated decision.
+	Manual bool `json:"manual,omitempty"`
+	// AutoDecision is the automated decision, set once a reviewer has toggled it.
+	AutoDecision string `json:"autoDecision,omitempty"`
+}
+
+type JSONStats struct {
+	Nit        int `json:"nit"`
+	Suggestion int `json:"suggestion"`
+	Issue      int `json:"issue"`
+	Blocker    int `json:"blocker"`
+}
+
+type JSONComment struct {
+	// ID is stable across runs for the same file, lines, severity, title and body.
+	ID       string `json:"id"`
+	FilePath string `json:"filePath"`
+	// StartLine and EndLine are 1-based and inclusive, on the new side of the diff.
+	StartLine int `json:"startLine"`
+	EndLine   int `json:"endLine"`
+	// Severity is one of NIT, SUGGESTION, ISSUE, BLOCKER.
+	Severity   string   `json:"severity"`
+	Title      string   `json:"title"`
+	Body       string   `json:"body"`
+	Suggestion *string  `json:"suggestion,omitempty"`
+	Evidence   *string  `json:"evidence,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	// Rule is the guideline the comment enforces; omitted when it is the model's own judgment.
+	Rule string `json:"rule,omitempty"`
+	// Publish reports whether the comment is selected for publishing.
+	Publish bool `json:"publish"`
+}
+
+// MarshalResult encodes res as an indented JSONReport.
+func MarshalResult(res Result) ([]byte, error) {
+	report := JSONReport{
+		SchemaVersion: ReportSchemaVersion,
+		Model:         res.Model,
+		GuidelineHash: res.GuidelineHash,
+		GeneratedAt:   res.GeneratedAt,
+		Verdict: JSONVerdict{
+			Decision:  string(res.Verdict.Decision),
+			Summary:   res.Verdict.Summary,
+			Rationale: res.Verdict.Rationale,
+			Stats: JSONStats{
+				Nit:        res.Verdict.Stats.Nit,
+				Suggestion: res.Verdict.Stats.Suggestion,
+				Issue:      res.Verdict.Stats.Issue,
+				Blocker:    res.Verdict.Stats.Blocker,
+			},
+			Manual:       res.Verdict.Manual,
+			AutoDecision: string(res.Verdict.AutoDecision),
+		},
+		Comments:   make([]JSONComment, 0, len(res.Comments)),
+		Dropped:    res.Dropped,
+		FileErrors: res.FileErrors,
+	}
+	for _, comment := range res.Comments {
+		id := comment.ID
+		if id == "" {
+			id = StableCommentID(comment)
+		}
+		report.Comments = append(report.Comments, JSONComment{
+			ID:         id,
+			FilePath:   comment.FilePath,
+			StartLine:  comment.StartLine,
+			EndLine:    comment.EndLine,
+			Severity:   string(comment.Severity),
+			Title:      comment.Title,
+			Body:       comment.Body,
+			Suggestion: comment.Suggestion,
+			Evidence:   comment.Evidence,
+			Tags:       comment.Tags,
+			Rule:       comment.Rule,
+			Publish:    comment.Publish,
+		})
+	}
+	sort.Slice(report.Comments, func(i, j int) bool {
+		a, b := report.Comments[i], report.Comments[j]
+		if a.FilePath != b.FilePath {
+			return a.FilePath < b.FilePath
+		}
+		if a.StartLine != b.StartLine {
+			return a.StartLine < b.StartLine
+		}
+		return a.ID < b.ID
+	})
+	return json.MarshalIndent(report, "", "  ")
+}
+
+// UnmarshalResult decodes a report written by MarshalResult back into a Result. Comments come back
+// in report order and keep their IDs.
+func UnmarshalResult(data []byte) (Result, error) {
+	var report JSONReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return Result{}, err
+	}
+	res := Result{
+		Model:         report.Model,
+		GuidelineHash: report.GuidelineHash,
+		GeneratedAt:   report.GeneratedAt,
+		Verdict: Verdict{
+			Decision:  NormalizeDecision(report.Verdict.Decision),
+			Summary:   report.Verdict.Summary,
+			Rationale: report.Verdict.Rationale,
+			Stats: Stats{
+				Nit:        report.Verdict.Stats.Nit,
+				Suggestion: report.Verdict.Stats.Suggestion,
+				Issue:      report.Verdict.Stats.Issue,
+				Blocker:    report.Verdict.Stats.Blocker,
+			},
+			Manual: report.Verdict.Manual,
+		},
+		Comments:   make([]Comment, 0, len(report.Comments)),
+		Dropped:    report.Dropped,
+		FileErrors: report.FileErrors,
+	}
+	if report.Verdict.AutoDecision != "" {
+		res.Verdict.AutoDecision = NormalizeDecision(report.Verdict.AutoDecision)
+	}
+	for _, comment := range report.Comments {
+		res.Comments = append(res.Comments, Comment{
+			ID:         comment.ID,
+			FilePath:   comment.FilePath,
+			StartLine:  comment.StartLine,
+			EndLine:    comment.EndLine,
+			Severity:   NormalizeSeverity(comment.Severity),
+			Title:      comment.Title,
+			Body:       comment.Body,
+			Suggestion: comment.Suggestion,
+			Evidence:   comment.Evidence,
+			Tags:       comment.Tags,
+			Rule:       comment.Rule,
+			Publish:    comment.Publish,
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,42 @@
+package review
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMarshalResult_whenCommentsUnordered_shouldSortAndUseStableFieldNames(t *testing.T) {
+	// arrange
+	res := Result{
+		Model:   "openai/gpt-4o-mini",
+		Verdict: Verdict{Decision: DecisionGo, Summary: "Looks fine."},
+		Comments: []Comment{
+			{FilePath: "b.go", StartLine: 1, EndLine: 1, Severity: SeverityNit, Title: "Typo", Body: "spelling"},
+			{FilePath: "a.go", StartLine: 9, EndLine: 9, Severity: SeverityIssue, Title: "Leak", Body: "close the file", Publish: true},
+		},
+	}
+
+	// act
+	data, err := MarshalResult(res)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for _, want := range []string{`"schemaVersion": 1`, `"decision": "GO"`, `"filePath": "a.go"`, `"startLine": 9`} {
+		if !strings.Contains(string(data), want) {
+			t.Fatalf("expected report to contain %s, got:\n%s", want, data)
+		}
+	}
+	if strings.Contains(string(data), `"suggestion": null`) {
+		t.Fatalf("expected a nil suggestion to be omitted, got:\n%s", data)
+	}
+	var report JSONReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("expected valid JSON, got %v", err)
+	}
+	if len(report.Comments) != 2 || report.Comments[0].FilePath != "a.go" || report.Comments[1].ID != StableCommentID(res.Comments[0]) {
+		t.Fatalf("expected comments sorted by path with stable IDs, got %+v", report.Comments)
+	}
+}
@@ -0,0 +1,29 @@
+package review
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// DefaultLanguageFocus maps a file extension to extra review emphasis for that language. Config
+// can add extensions or replace these; an empty snippet turns an extension's focus off.
+var DefaultLanguageFocus = map[string]string{
+	".go":   "Go: check that errors are handled or returned with context, goroutines and channels cannot leak or deadlock, contexts are propagated, and deferred Close calls are not lost in loops.",
+	".ts":   "TypeScript: check for unsafe any and non-null assertions, unhandled promise rejections, missing await, and types that do not match runtime data.",
+	".tsx":  "TypeScript/React: check hook dependency arrays and rules of hooks, missing keys in lists, unhandled promises, and unsafe any.",
+	".js":   "JavaScript: check for unhandled promise rejections, missing await, loose equality, and accidental globals.",
+	".py":   "Python: check for bare except clauses, mutable default arguments, unclosed resources, and blocking calls in async code.",
+	".sql":  "SQL: check for missing indexes on new filters and joins, locking or full-table rewrites in migrations, non-reversible migrations, and string-built queries open to injection.",
+	".java": "Java: check for unclosed resources (use try-with-resources), swallowed exceptions, null handling, and thread-safety of shared state.",
+	".rs":   "Rust: check for unwrap/expect on fallible paths, unnecessary clones, unsafe blocks without justification, and error propagation.",
+}
+
+// languageFocus returns the focus snippet for path's extension, preferring opts.LanguageFocus
+// over DefaultLanguageFocus.
+func (o RunOptions) languageFocus(path string) string {
+	ext := strings.ToLower(filepath.Ext(path))
+	if focus, ok := o.LanguageFocus[ext]; ok {
+		return strings.TrimSpace(focus)
+	}
+	return DefaultLanguageFocus[ext]
+}
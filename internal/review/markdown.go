@@ -0,0 +1,143 @@
+package review
+
+import (
+	"cmp"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// RenderMarkdown renders the verdict, a stats table and every comment selected for publishing,
+// grouped by file with the most severe first. The model and guideline hash go in a collapsed
+// footer. It backs both the summary comment and the exported Markdown report.
+func RenderMarkdown(res Result) string {
+	var sb strings.Builder
+
+	decision := string(res.Verdict.Decision)
+	if res.Verdict.Manual {
+		decision += " (manual override)"
+	}
+	sb.WriteString(fmt.Sprintf("# AI Code Review Verdict: %s\n\n", decision))
+	sb.WriteString(fmt.Sprintf("**Summary**: %s\n\n", res.Verdict.Summary))
+
+	stats := res.Verdict.Stats
+	sb.WriteString("| Verdict | NIT | SUGGESTION | ISSUE | BLOCKER |\n")
+	sb.WriteString("| --- | ---: | ---: | ---: | ---: |\n")
+	sb.WriteString(fmt.Sprintf("| %s | %d | %d | %d | %d |\n\n", decision, stats.Nit, stats.Suggestion, stats.Issue, stats.Blocker))
+
+	if len(res.Verdict.Rationale) > 0 {
+		sb.WriteString("### Rationale\n")
+		for _, r := range res.Verdict.Rationale {
+			sb.WriteString(fmt.Sprintf("- %s\n", r))
+		}
+		sb.WriteString("\n")
+	}
+
+	var selected []Comment
+	for _, c := range res.Comments {
+		if c.Publish {
+			selected = append(selected, c)
+		}
+	}
+	slices.SortStableFunc(selected, func(a, b Comment) int {
+		return cmp.Or(cmp.Compare(a.FilePath, b.FilePath), CompareComments(a, b, OrderBySeverity))
+	})
+
+	if len(selected) > 0 {
+		sb.WriteString("## Detailed Comments\n\n")
+		for _, group := range groupByFile(selected) {
+			sb.WriteString(fmt.Sprintf("### `%s`\n\n", group.Path))
+			for _, c := range group.Comments {
+				sb.WriteString(fmt.Sprintf("#### %s %s\n", severityBadge(c.Severity), c.Title))
+				sb.WriteString(fmt.Sprintf("**Lines**: %s\n\n", lineRange(c)))
+				writeCommentDetails(&sb, c, false)
+			}
+			sb.WriteString("---\n\n")
+		}
+	}
+
+	sb.WriteString("<details><summary>Review details</summary>\n\n")
+	sb.WriteString(fmt.Sprintf("**Model**: %s\n", res.Model))
+	if res.GuidelineHash != "" {
+		sb.WriteString(fmt.Sprintf("**Guideline hash**: `%s`\n", res.GuidelineHash))
+	}
+	sb.WriteString("</details>\n\n")
+	sb.WriteString("*Generated by AI Code Reviewer*")
+
+	return sb.String()
+}
+
+// lineRange is "3" or "3-5".
+func lineRange(c Comment) string {
+	if c.EndLine > c.StartLine {
+		return fmt.Sprintf("%d-%d", c.StartLine, c.EndLine)
+	}
+	return fmt.Sprintf("%d", c.StartLine)
+}
+
+// RenderInlineComment renders one comment for posting on its own line of a pull request diff.
+func RenderInlineComment(c Comment) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %s\n\n", severityBadge(c.Severity), c.Title))
+	writeCommentDetails(&sb, c, false)
+	return strings.TrimSpace(sb.String())
+}
+
+// RenderInlineSuggestionComment is RenderInlineComment for a comment anchored on lines start to end.
+// When the suggestion is a fenced replacement (see SuggestionReplacement) for exactly those lines it
+// goes in a ```suggestion block, which GitHub and Bitbucket offer to apply in place of the lines;
+// any other suggestion is shown as plain code.
+func RenderInlineSuggestionComment(c Comment, start, end int) string {
+	native := start == c.StartLine && end == max(c.EndLine, c.StartLine)
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %s\n\n", severityBadge(c.Severity), c.Title))
+	writeCommentDetails(&sb, c, native)
+	return strings.TrimSpace(sb.String())
+}
+
+// writeCommentDetails writes the body, suggestion and evidence of c. Code goes in blocks fenced for
+// the file's language; with suggestionBlock, a fenced replacement becomes a native suggestion.
+func writeCommentDetails(sb *strings.Builder, c Comment, suggestionBlock bool) {
+	sb.WriteString(fmt.Sprintf("%s\n\n", c.Body))
+
+	language := strings.TrimPrefix(strings.ToLower(filepath.Ext(c.FilePath)), ".")
+	if code, ok := SuggestionReplacement(c); ok && suggestionBlock {
+		sb.WriteString("**Suggestion**:\n")
+		writeFencedCode(sb, "suggestion", code)
+	} else if code, ok := SuggestionCode(c); ok {
+		sb.WriteString("**Suggestion**:\n")
+		writeFencedCode(sb, language, code)
+	}
+
+	if c.Evidence != nil && *c.Evidence != "" {
+		sb.WriteString("<details><summary>Evidence</summary>\n\n")
+		writeFencedCode(sb, language, *c.Evidence)
+		sb.WriteString("</details>\n\n")
+	}
+}
+
+// writeFencedCode writes code in a block tagged info, with a fence longer than any backtick run in
+// the code so the code cannot close it early.
+func writeFencedCode(sb *strings.Builder, info, code string) {
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	sb.WriteString(fmt.Sprintf("%s%s\n%s\n%s\n\n", fence, info, code, fence))
+}
+
+func severityBadge(sev Severity) string {
+	switch sev {
+	case SeverityBlocker:
+		return "🔴 **BLOCKER**"
+	case SeverityIssue:
+		return "🟠 **ISSUE**"
+	case SeveritySuggestion:
+		return "🟡 **SUGGESTION**"
+	case SeverityNit:
+		return "⚪ **NIT**"
+	default:
+		return "🔵 **INFO**"
+	}
+}
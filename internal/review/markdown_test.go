@@ -0,0 +1,106 @@
+package review
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdown_whenCommentsMixed_shouldIncludeHeaderAndOnlySelectedComments(t *testing.T) {
+	// arrange
+	suggestion := "return nil"
+	res := Result{
+		Model:         "openai/gpt-4o-mini",
+		GuidelineHash: "abc123",
+		Verdict:       Verdict{Decision: DecisionNoGo, Summary: "Fix the nil check.", Stats: Stats{Blocker: 1}},
+		Comments: []Comment{
+			{FilePath: "a.go", StartLine: 3, EndLine: 5, Severity: SeverityBlocker, Title: "Nil deref", Body: "x may be nil", Suggestion: &suggestion, Publish: true},
+			{FilePath: "b.go", StartLine: 1, EndLine: 1, Severity: SeverityNit, Title: "Typo", Body: "spelling", Publish: false},
+		},
+	}
+
+	// act
+	markdown := RenderMarkdown(res)
+
+	// assert
+	for _, want := range []string{"Verdict: NO_GO", "openai/gpt-4o-mini", "`abc123`", "| NO_GO | 0 | 0 | 0 | 1 |", "### `a.go`", "**Lines**: 3-5", "return nil"} {
+		if !strings.Contains(markdown, want) {
+			t.Fatalf("expected markdown to contain %q, got:\n%s", want, markdown)
+		}
+	}
+	if strings.Contains(markdown, "Typo") {
+		t.Fatalf("expected unselected comments to be left out, got:\n%s", markdown)
+	}
+}
+
+func TestRenderInlineSuggestionComment_whenSuggestionIsFenced_shouldUseNativeSuggestionBlock(t *testing.T) {
+	// arrange
+	suggestion := "```go\nif x == nil {\n\treturn nil\n}\n```"
+	withSuggestion := Comment{FilePath: "a.go", StartLine: 3, EndLine: 3, Severity: SeverityIssue, Title: "Nil deref", Body: "x may be nil", Suggestion: &suggestion}
+	withoutSuggestion := Comment{FilePath: "a.go", StartLine: 4, EndLine: 4, Severity: SeverityNit, Title: "Typo", Body: "spelling"}
+
+	// act
+	inline := RenderInlineSuggestionComment(withSuggestion, 3, 3)
+	plain := RenderInlineSuggestionComment(withoutSuggestion, 4, 4)
+
+	// assert
+	if !strings.Contains(inline, "```suggestion\nif x == nil {\n\treturn nil\n}\n```") {
+		t.Fatalf("expected the code in a suggestion block, got:\n%s", inline)
+	}
+	if strings.Contains(inline, "```go") {
+		t.Fatalf("expected the original fence to be replaced, got:\n%s", inline)
+	}
+	if strings.Contains(plain, "```") {
+		t.Fatalf("expected no code block without a suggestion, got:\n%s", plain)
+	}
+}
+
+func TestRenderInlineSuggestionComment_whenNotAnExactReplacement_shouldUsePlainCodeBlock(t *testing.T) {
+	// arrange
+	prose := "Check it first:\n```go\nif x == nil {\n\treturn nil\n}\n```"
+	fenced := "```md\nUse ```go fences\n```"
+	withProse := Comment{FilePath: "a.go", StartLine: 3, EndLine: 3, Title: "Nil deref", Body: "x", Suggestion: &prose}
+	wider := Comment{FilePath: "a.go", StartLine: 3, EndLine: 5, Title: "Nil deref", Body: "x", Suggestion: &prose}
+	withFence := Comment{FilePath: "README.md", StartLine: 1, EndLine: 1, Title: "Fence", Body: "x", Suggestion: &fenced}
+
+	// act
+	proseInline := RenderInlineSuggestionComment(withProse, 3, 3)
+	widerInline := RenderInlineSuggestionComment(wider, 3, 3)
+	fenceInline := RenderInlineSuggestionComment(withFence, 1, 1)
+
+	// assert
+	for _, inline := range []string{proseInline, widerInline} {
+		if strings.Contains(inline, "suggestion\n") || !strings.Contains(inline, "```go\nif x == nil {") {
+			t.Fatalf("expected a plain go block, got:\n%s", inline)
+		}
+	}
+	if !strings.Contains(fenceInline, "````suggestion\nUse ```go fences\n````") {
+		t.Fatalf("expected a longer fence around code containing a fence, got:\n%s", fenceInline)
+	}
+}
+
+func TestRenderMarkdown_whenCommentsSpanFiles_shouldGroupByFileWithMostSevereFirst(t *testing.T) {
+	// arrange
+	res := Result{
+		Model:   "test-model",
+		Verdict: Verdict{Decision: DecisionGo},
+		Comments: []Comment{
+			{FilePath: "b.go", StartLine: 1, EndLine: 1, Severity: SeverityNit, Title: "B nit", Body: "x", Publish: true},
+			{FilePath: "a.go", StartLine: 1, EndLine: 1, Severity: SeverityNit, Title: "A nit", Body: "x", Publish: true},
+			{FilePath: "a.go", StartLine: 9, EndLine: 9, Severity: SeverityBlocker, Title: "A blocker", Body: "x", Publish: true},
+		},
+	}
+
+	// act
+	markdown := RenderMarkdown(res)
+
+	// assert
+	order := []string{"### `a.go`", "A blocker", "A nit", "### `b.go`", "B nit", "<details><summary>Review details</summary>", "**Model**: test-model"}
+	last := -1
+	for _, want := range order {
+		at := strings.Index(markdown, want)
+		if at <= last {
+			t.Fatalf("expected %q after the previous section, got:\n%s", want, markdown)
+		}
+		last = at
+	}
+}
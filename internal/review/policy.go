@@ -0,0 +1,71 @@
+package review
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// NoGoPolicy sets the comment counts at which the rule-based verdict becomes NO_GO. A threshold
+// of zero is disabled.
+type NoGoPolicy struct {
+	Blockers int
+	Issues   int
+}
+
+// DefaultNoGoPolicy is NO_GO on any blocker.
+var DefaultNoGoPolicy = NoGoPolicy{Blockers: 1}
+
+// ParseNoGoPolicy reads comma-separated thresholds such as "blockers>=2,issues>=5". Empty means
+// DefaultNoGoPolicy and "never" disables the rule-based NO_GO entirely.
+func ParseNoGoPolicy(value string) (NoGoPolicy, error) {
+	value = strings.TrimSpace(value)
+	switch value {
+	case "":
+		return DefaultNoGoPolicy, nil
+	case "never":
+		return NoGoPolicy{}, nil
+	}
+	var policy NoGoPolicy
+	for _, part := range strings.Split(value, ",") {
+		name, threshold, ok := strings.Cut(strings.TrimSpace(part), ">=")
+		count, err := strconv.Atoi(strings.TrimSpace(threshold))
+		if !ok || err != nil || count < 1 {
+			return NoGoPolicy{}, fmt.Errorf("invalid NO_GO threshold %q (want e.g. blockers>=1 or issues>=5)", part)
+		}
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "blockers":
+			policy.Blockers = count
+		case "issues":
+			policy.Issues = count
+		default:
+			return NoGoPolicy{}, fmt.Errorf("unknown NO_GO threshold %q (want blockers or issues)", name)
+		}
+	}
+	return policy, nil
+}
+
+// Decide is the rule-based decision for stats.
+func (p NoGoPolicy) Decide(stats Stats) Decision {
+	if p.Blockers > 0 && stats.Blocker >= p.Blockers {
+		return DecisionNoGo
+	}
+	if p.Issues > 0 && stats.Issue >= p.Issues {
+		return DecisionNoGo
+	}
+	return DecisionGo
+}
+
+func (p NoGoPolicy) String() string {
+	var parts []string
+	if p.Blockers > 0 {
+		parts = append(parts, fmt.Sprintf("blockers>=%d", p.Blockers))
+	}
+	if p.Issues > 0 {
+		parts = append(parts, fmt.Sprintf("issues>=%d", p.Issues))
+	}
+	if len(parts) == 0 {
+		return "never"
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,41 @@
+package review
+
+import "testing"
+
+func TestParseNoGoPolicy_whenIssueThresholdSet_shouldDecideOnIssuesAndBlockers(t *testing.T) {
+	// arrange
+	stats := Stats{Blocker: 1, Issue: 4}
+
+	// act
+	policy, err := ParseNoGoPolicy("blockers>=2, issues>=5")
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got := policy.Decide(stats); got != DecisionGo {
+		t.Fatalf("expected GO below both thresholds, got %s", got)
+	}
+	stats.Issue = 5
+	if got := policy.Decide(stats); got != DecisionNoGo {
+		t.Fatalf("expected NO_GO at 5 issues, got %s", got)
+	}
+	if policy.String() != "blockers>=2,issues>=5" {
+		t.Fatalf("expected canonical form, got %q", policy.String())
+	}
+}
+
+func TestParseNoGoPolicy_whenMalformed_shouldReturnError(t *testing.T) {
+	// arrange
+	values := []string{"blockers>0", "warnings>=1", "issues>=0"}
+
+	for _, value := range values {
+		// act
+		_, err := ParseNoGoPolicy(value)
+
+		// assert
+		if err == nil {
+			t.Fatalf("expected an error for %q", value)
+		}
+	}
+}
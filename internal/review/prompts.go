@@ -16,9 +16,10 @@ const fileReviewSchema = `{
       "severity": "BLOCKER",
       "title": "Short title",
       "body": "Detailed comment",
-      "suggestion": "Optional suggestion",
+      "suggestion": "Optional suggestion, or replacement code in one fenced block",
       "evidence": "Optional snippet",
-      "tags": ["optional", "tags"]
+      "tags": ["optional", "tags"],
+      "rule": "Optional guideline line this comment enforces"
     }
   ]
 }`
@@ -31,26 +32,49 @@ const verdictSchema = `{
   }
 }`
 
-func BuildFileReviewMessages(guidelines, diff string) []llm.Message {
+// BuildFileReviewMessages builds the per-file review prompt. languageFocus, when non-empty, is extra
+// emphasis for the file's language (see DefaultLanguageFocus). A minSeverity above NIT asks the model
+// to omit less severe findings. fileContext, when non-empty, is a
+// numbered excerpt of the full file around the hunks and is kept separate from the diff.
+func BuildFileReviewMessages(guidelines, languageFocus string, minSeverity Severity, diff, fileContext string) []llm.Message {
 	system := strings.Join([]string{
 		"You are a expert senior software engineer. You are tasked to review the code",
 		"Follow the provided guidelines.",
 		"Return JSON only. Do not include markdown fences.",
 	}, " ")
 
-	user := fmt.Sprintf(strings.Join([]string{
+	lines := []string{
 		"Guidelines:",
 		"%s",
 		"",
 		"Severity scale: NIT (minor), SUGGESTION (improvement), ISSUE (bug/maintainability), BLOCKER (must-fix).",
+	}
+	if minSeverity != "" && minSeverity != SeverityNit {
+		lines = append(lines, fmt.Sprintf("Only report findings of severity %s or above; leave out anything less severe.", minSeverity))
+	}
+	if languageFocus != "" {
+		lines = append(lines, "Language focus: "+strings.ReplaceAll(languageFocus, "%", "%%"))
+	}
+	lines = append(lines,
+		"For deleted files (+++ /dev/null), use the old-side line numbers from the hunk headers.",
+		"If the diff has old mode/new mode lines, consider whether the permission change (e.g. a new executable bit) is expected.",
 		"Review the diff and return comments in the schema below.",
+		"Put code in suggestion only as a single fenced code block that replaces lines startLine through endLine exactly; describe any other change in prose.",
+		"When a comment enforces one of the guidelines, quote that guideline line in rule; leave rule out when the finding is your own judgment.",
 		"If there are no comments, return {\"comments\": []}.",
 		"Schema:",
 		"%s",
 		"",
 		"Diff:",
 		"%s",
-	}, "\n"), guidelines, fileReviewSchema, diff)
+	)
+	user := fmt.Sprintf(strings.Join(lines, "\n"), guidelines, fileReviewSchema, diff)
+	if strings.TrimSpace(fileContext) != "" {
+		user += "\n\n" + strings.Join([]string{
+			"Surrounding file context (read-only, new-side line numbers; only comment on lines changed in the diff):",
+			fileContext,
+		}, "\n")
+	}
 
 	return []llm.Message{
 		{Role: "system", Content: system},
@@ -64,14 +88,6 @@ func BuildVerdictMessages(guidelines string, comments []Comment, stats Stats, ru
 		"Return JSON only. Do not include markdown fences.",
 	}, " ")
 
-	lines := make([]string, 0, len(comments))
-	for _, comment := range comments {
-		lines = append(lines, fmt.Sprintf("- [%s] %s:%d %s", comment.Severity, comment.FilePath, comment.StartLine, comment.Title))
-	}
-	if len(lines) == 0 {
-		lines = append(lines, "- No comments.")
-	}
-
 	user := fmt.Sprintf(strings.Join([]string{
 		"Guidelines:",
 		"%s",
@@ -80,13 +96,25 @@ func BuildVerdictMessages(guidelines string, comments []Comment, stats Stats, ru
 		"%s",
 		"",
 		"Stats: NIT=%d, SUGGESTION=%d, ISSUE=%d, BLOCKER=%d.",
-		"Rule-based decision is %s if any BLOCKER exists, otherwise GO.",
+		"Rule-based decision (from the team's NO_GO thresholds): %s.",
 		"Provide a verdict JSON matching this schema:",
 		"%s",
-	}, "\n"), guidelines, strings.Join(lines, "\n"), stats.Nit, stats.Suggestion, stats.Issue, stats.Blocker, ruleDecision, verdictSchema)
+	}, "\n"), guidelines, verdictCommentSummary(comments), stats.Nit, stats.Suggestion, stats.Issue, stats.Blocker, ruleDecision, verdictSchema)
 
 	return []llm.Message{
 		{Role: "system", Content: system},
 		{Role: "user", Content: user},
 	}
 }
+
+// verdictCommentSummary lists each comment on one line for the verdict prompt.
+func verdictCommentSummary(comments []Comment) string {
+	lines := make([]string, 0, len(comments))
+	for _, comment := range comments {
+		lines = append(lines, fmt.Sprintf("- [%s] %s:%d %s", comment.Severity, comment.FilePath, comment.StartLine, comment.Title))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "- No comments.")
+	}
+	return strings.Join(lines, "\n")
+}
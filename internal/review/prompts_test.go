@@ -0,0 +1,25 @@
+package review
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLanguageFocus_whenConfigOverridesExtension_shouldPreferConfigAndAllowDisabling(t *testing.T) {
+	// arrange
+	opts := RunOptions{LanguageFocus: map[string]string{".sql": "Check for Postgres locks.", ".go": ""}}
+
+	// act
+	sqlFocus := opts.languageFocus("db/001_init.SQL")
+	goFocus := opts.languageFocus("main.go")
+	tsFocus := opts.languageFocus("web/app.ts")
+	messages := BuildFileReviewMessages("", sqlFocus, SeverityNit, "diff", "")
+
+	// assert
+	if sqlFocus != "Check for Postgres locks." || goFocus != "" || tsFocus != DefaultLanguageFocus[".ts"] {
+		t.Fatalf("unexpected focus: sql=%q go=%q ts=%q", sqlFocus, goFocus, tsFocus)
+	}
+	if !strings.Contains(messages[1].Content, "Language focus: Check for Postgres locks.") {
+		t.Fatalf("expected the focus in the user prompt, got %q", messages[1].Content)
+	}
+}
@@ -0,0 +1,67 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/git"
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/llm"
+)
+
+// regenerateInstruction follows the usual file review prompt when a single finding is redone.
+const regenerateInstruction = `You already reviewed this diff and reported the finding below. Redo only this finding: expand and clarify it with concrete detail (what goes wrong, when, and how to fix it), adjusting the severity or lines if they were off. Return exactly one comment in the schema.
+
+Finding (%s, lines %d-%d): %s
+%s`
+
+// RegenerateComment asks the model to redo one comment with more detail, re-sending only that
+// comment's file diff with the run's guidelines (and file hint). The new comment takes the old
+// one's place: it keeps its ID, file and publish choice.
+func RegenerateComment(ctx context.Context, client ChatClient, file git.DiffFile, comment Comment, opts RunOptions) (Comment, error) {
+	guidelines, err := LoadGuidelines(opts.GuidelinePaths, opts.FreeText)
+	if err != nil {
+		return Comment{}, err
+	}
+	if opts.FileHints {
+		hint, err := LoadFileHint(opts.FileContent, file.Path)
+		if err != nil {
+			return Comment{}, err
+		}
+		guidelines = appendFileHint(guidelines, file.Path, hint)
+	}
+	messages, err := opts.Prompts.fileReviewMessages(guidelines, opts.languageFocus(file.Path), SeverityNit, RenderUnifiedDiffFile(file), "")
+	if err != nil {
+		return Comment{}, err
+	}
+	messages = append(messages, llm.Message{Role: "user", Content: fmt.Sprintf(regenerateInstruction,
+		comment.Severity, comment.StartLine, max(comment.EndLine, comment.StartLine), comment.Title, comment.Body)})
+
+	content, err := client.ChatCompletion(ctx, llm.ChatRequest{
+		Model:          opts.Model,
+		Messages:       messages,
+		Temperature:    opts.temperature(),
+		MaxTokens:      opts.MaxTokens,
+		ResponseFormat: llm.JSONObjectFormat,
+	})
+	if err != nil {
+		return Comment{}, err
+	}
+	if strings.TrimSpace(content) == "" {
+		return Comment{}, errors.New("empty response from LLM")
+	}
+	comments, _, err := parseFileComments(content)
+	if err != nil {
+		return Comment{}, err
+	}
+	if len(comments) == 0 {
+		return Comment{}, errors.New("the model returned no comment")
+	}
+
+	regenerated := comments[0]
+	regenerated.ID = comment.ID
+	regenerated.FilePath = comment.FilePath
+	regenerated.Publish = comment.Publish
+	return regenerated, nil
+}
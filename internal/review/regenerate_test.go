@@ -0,0 +1,37 @@
+package review
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/llm"
+)
+
+func TestRegenerateComment_whenModelRepliesWithComment_shouldReplaceItKeepingIdentity(t *testing.T) {
+	// arrange
+	var sent llm.ChatRequest
+	client := fakeChatClient{fileReply: func(req llm.ChatRequest) (string, error) {
+		sent = req
+		return `{"comments":[{"filePath":"other.go","startLine":1,"endLine":1,"severity":"BLOCKER","title":"Nil map write","body":"Writing to a nil map panics."}]}`, nil
+	}}
+	comment := Comment{ID: "c1", FilePath: "a.go", StartLine: 1, EndLine: 1, Severity: SeverityIssue, Title: "Map use", Body: "Check the map.", Publish: false}
+
+	// act
+	got, err := RegenerateComment(context.Background(), client, fakeDiffFiles("a.go")[0], comment, RunOptions{})
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got.ID != "c1" || got.FilePath != "a.go" || got.Publish {
+		t.Fatalf("expected the original id, file and publish choice, got %+v", got)
+	}
+	if got.Severity != SeverityBlocker || got.Title != "Nil map write" {
+		t.Fatalf("expected the regenerated finding, got %+v", got)
+	}
+	last := sent.Messages[len(sent.Messages)-1].Content
+	if !strings.Contains(last, "Map use") || !strings.Contains(last, "Check the map.") {
+		t.Fatalf("expected the last turn to quote the finding, got:\n%s", last)
+	}
+}
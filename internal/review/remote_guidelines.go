@@ -0,0 +1,94 @@
+package review
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/config"
+)
+
+// RemoteGuidelineTTL is how long a fetched guideline URL is served from the cache before it is
+// fetched again.
+const RemoteGuidelineTTL = 15 * time.Minute
+
+// maxRemoteGuidelineBytes bounds a fetched guideline; anything larger is not a review guideline.
+const maxRemoteGuidelineBytes = 1 << 20
+
+var remoteGuidelineClient = &http.Client{Timeout: 10 * time.Second}
+
+// IsGuidelineURL reports whether a guideline entry is an http(s) URL rather than a file path.
+func IsGuidelineURL(path string) bool {
+	return strings.HasPrefix(path, "https://") || strings.HasPrefix(path, "http://")
+}
+
+// ReadGuideline returns the content of a guideline file or URL. URLs are cached under CacheDir
+// for RemoteGuidelineTTL; when a refresh fails, a stale cached copy is used if there is one.
+func ReadGuideline(path string) ([]byte, error) {
+	if !IsGuidelineURL(path) {
+		return os.ReadFile(path)
+	}
+	cachePath, cacheErr := remoteGuidelineCachePath(path)
+	if cacheErr == nil {
+		if info, err := os.Stat(cachePath); err == nil && time.Since(info.ModTime()) < RemoteGuidelineTTL {
+			if data, err := os.ReadFile(cachePath); err == nil {
+				return data, nil
+			}
+		}
+	}
+
+	data, err := fetchGuideline(path)
+	if err != nil {
+		if cacheErr == nil {
+			if stale, staleErr := os.ReadFile(cachePath); staleErr == nil {
+				slog.Warn("Using cached guideline after fetch failed", "url", path, "error", err)
+				return stale, nil
+			}
+		}
+		return nil, err
+	}
+	if cacheErr == nil {
+		if err := os.MkdirAll(filepath.Dir(cachePath), 0o755); err == nil {
+			if err := os.WriteFile(cachePath, data, 0o600); err != nil {
+				slog.Warn("Failed to cache guideline", "url", path, "error", err)
+			}
+		}
+	}
+	return data, nil
+}
+
+func fetchGuideline(url string) ([]byte, error) {
+	resp, err := remoteGuidelineClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("fetch guideline %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("fetch guideline %s: unexpected status %s", url, resp.Status)
+	}
+	// Read one byte past the limit so an oversized guideline fails instead of being cut silently.
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteGuidelineBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("fetch guideline %s: %w", url, err)
+	}
+	if len(data) > maxRemoteGuidelineBytes {
+		return nil, fmt.Errorf("fetch guideline %s: larger than %d bytes", url, maxRemoteGuidelineBytes)
+	}
+	return data, nil
+}
+
+func remoteGuidelineCachePath(url string) (string, error) {
+	dir, err := config.CacheDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(dir, "guidelines", hex.EncodeToString(sum[:])+".md"), nil
+}
@@ -0,0 +1,174 @@
+package review
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	// sarifToolName is the driver name code-scanning UIs show next to each finding.
+	sarifToolName = "code-reviewer"
+)
+
+// SARIFLog is the subset of SARIF 2.1.0 that ToSARIF emits.
+type SARIFLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+type SARIFRun struct {
+	Tool    SARIFTool     `json:"tool"`
+	Results []SARIFResult `json:"results"`
+}
+
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+type SARIFDriver struct {
+	Name  string      `json:"name"`
+	Rules []SARIFRule `json:"rules"`
+}
+
+type SARIFRule struct {
+	ID               string       `json:"id"`
+	ShortDescription SARIFMessage `json:"shortDescription"`
+}
+
+type SARIFResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   SARIFMessage    `json:"message"`
+	Locations []SARIFLocation `json:"locations"`
+	// PartialFingerprints lets code-scanning UIs match the same finding across runs.
+	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"`
+}
+
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+type SARIFLocation struct {
+	PhysicalLocation SARIFPhysicalLocation `json:"physicalLocation"`
+}
+
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	Region           SARIFRegion           `json:"region"`
+}
+
+type SARIFArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type SARIFRegion struct {
+	StartLine int `json:"startLine"`
+	EndLine   int `json:"endLine"`
+}
+
+var sarifRuleSanitizer = regexp.MustCompile(`[^a-z0-9]+`)
+
+// ToSARIF maps the comments selected for publishing to SARIF results. Each comment becomes one
+// result whose region is its StartLine-EndLine range on the new side of the diff.
+func ToSARIF(res Result) SARIFLog {
+	results := make([]SARIFResult, 0, len(res.Comments))
+	rules := map[string]SARIFRule{}
+	for _, comment := range res.Comments {
+		if !comment.Publish {
+			continue
+		}
+		ruleID := sarifRuleID(comment)
+		if _, ok := rules[ruleID]; !ok {
+			rules[ruleID] = SARIFRule{ID: ruleID, ShortDescription: SARIFMessage{Text: sarifRuleDescription(comment)}}
+		}
+		id := comment.ID
+		if id == "" {
+			id = StableCommentID(comment)
+		}
+		results = append(results, SARIFResult{
+			RuleID:  ruleID,
+			Level:   sarifLevel(comment.Severity),
+			Message: SARIFMessage{Text: sarifMessage(comment)},
+			Locations: []SARIFLocation{{PhysicalLocation: SARIFPhysicalLocation{
+				ArtifactLocation: SARIFArtifactLocation{URI: filepath.ToSlash(comment.FilePath)},
+				Region:           sarifRegion(comment.StartLine, comment.EndLine),
+			}}},
+			PartialFingerprints: map[string]string{"reviewCommentId/v1": id},
+		})
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i].Locations[0].PhysicalLocation, results[j].Locations[0].PhysicalLocation
+		if a.ArtifactLocation.URI != b.ArtifactLocation.URI {
+			return a.ArtifactLocation.URI < b.ArtifactLocation.URI
+		}
+		return a.Region.StartLine < b.Region.StartLine
+	})
+
+	ruleList := make([]SARIFRule, 0, len(rules))
+	for _, rule := range rules {
+		ruleList = append(ruleList, rule)
+	}
+	sort.Slice(ruleList, func(i, j int) bool { return ruleList[i].ID < ruleList[j].ID })
+
+	return SARIFLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []SARIFRun{{
+			Tool:    SARIFTool{Driver: SARIFDriver{Name: sarifToolName, Rules: ruleList}},
+			Results: results,
+		}},
+	}
+}
+
+// sarifRuleID uses the comment's first usable tag, falling back to its severity.
+func sarifRuleID(comment Comment) string {
+	for _, tag := range comment.Tags {
+		if slug := strings.Trim(sarifRuleSanitizer.ReplaceAllString(strings.ToLower(tag), "-"), "-"); slug != "" {
+			return "review/" + slug
+		}
+	}
+	return "review/" + strings.ToLower(string(comment.Severity))
+}
+
+func sarifRuleDescription(comment Comment) string {
+	for _, tag := range comment.Tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			return "Review findings tagged " + tag
+		}
+	}
+	return "Review findings of severity " + string(comment.Severity)
+}
+
+func sarifLevel(severity Severity) string {
+	switch severity {
+	case SeverityBlocker, SeverityIssue:
+		return "error"
+	case SeveritySuggestion:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+func sarifMessage(comment Comment) string {
+	text := strings.TrimSpace(comment.Title)
+	if body := strings.TrimSpace(comment.Body); body != "" {
+		text += "\n\n" + body
+	}
+	if comment.Suggestion != nil && strings.TrimSpace(*comment.Suggestion) != "" {
+		text += "\n\nSuggestion:\n" + strings.TrimSpace(*comment.Suggestion)
+	}
+	return text
+}
+
+// sarifRegion keeps the range valid for SARIF consumers, which reject lines below 1 and
+// end lines before the start line.
+func sarifRegion(startLine, endLine int) SARIFRegion {
+	startLine = max(startLine, 1)
+	return SARIFRegion{StartLine: startLine, EndLine: max(endLine, startLine)}
+}
@@ -0,0 +1,31 @@
+package review
+
+import "testing"
+
+func TestToSARIF_whenCommentsPublished_shouldMapLevelsRulesAndLineRanges(t *testing.T) {
+	// arrange
+	res := Result{Comments: []Comment{
+		{FilePath: "pkg/a.go", StartLine: 10, EndLine: 14, Severity: SeverityBlocker, Title: "Nil deref", Body: "x may be nil", Tags: []string{"Null Safety"}, Publish: true},
+		{FilePath: "pkg/a.go", StartLine: 3, EndLine: 0, Severity: SeverityNit, Title: "Typo", Body: "spelling", Publish: true},
+		{FilePath: "b.go", StartLine: 1, EndLine: 1, Severity: SeverityIssue, Title: "Skipped", Body: "deselected", Publish: false},
+	}}
+
+	// act
+	log := ToSARIF(res)
+
+	// assert
+	results := log.Runs[0].Results
+	if len(results) != 2 {
+		t.Fatalf("expected only published comments, got %d results", len(results))
+	}
+	nit, blocker := results[0], results[1]
+	if nit.Level != "note" || nit.RuleID != "review/nit" || nit.Locations[0].PhysicalLocation.Region != (SARIFRegion{StartLine: 3, EndLine: 3}) {
+		t.Fatalf("unexpected nit result: %+v", nit)
+	}
+	if blocker.Level != "error" || blocker.RuleID != "review/null-safety" || blocker.Locations[0].PhysicalLocation.Region != (SARIFRegion{StartLine: 10, EndLine: 14}) {
+		t.Fatalf("unexpected blocker result: %+v", blocker)
+	}
+	if len(log.Runs[0].Tool.Driver.Rules) != 2 {
+		t.Fatalf("expected one rule per distinct ruleId, got %+v", log.Runs[0].Tool.Driver.Rules)
+	}
+}
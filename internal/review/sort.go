@@ -0,0 +1,58 @@
+package review
+
+import (
+	"cmp"
+	"sort"
+)
+
+// CommentOrder is a way of ordering review comments for display.
+type CommentOrder int
+
+const (
+	// OrderBySeverity is BLOCKER first, then file, then start line. It is the default.
+	OrderBySeverity CommentOrder = iota
+	// OrderByFile is by file, then start line, then severity.
+	OrderByFile
+	// OrderByLine is by start line, then file.
+	OrderByLine
+)
+
+func (o CommentOrder) String() string {
+	switch o {
+	case OrderByFile:
+		return "file"
+	case OrderByLine:
+		return "line"
+	default:
+		return "severity"
+	}
+}
+
+// Next cycles severity → file → line → severity.
+func (o CommentOrder) Next() CommentOrder {
+	return (o + 1) % (OrderByLine + 1)
+}
+
+// SortComments orders comments in place by order. Remaining ties are broken by ID, so the order is
+// the same on every run.
+func SortComments(comments []Comment, order CommentOrder) {
+	sort.SliceStable(comments, func(i, j int) bool {
+		return CompareComments(comments[i], comments[j], order) < 0
+	})
+}
+
+// CompareComments compares a and b under order, like cmp.Compare.
+func CompareComments(a, b Comment, order CommentOrder) int {
+	severity := cmp.Compare(SeverityRank(a.Severity), SeverityRank(b.Severity))
+	file := cmp.Compare(a.FilePath, b.FilePath)
+	line := cmp.Compare(a.StartLine, b.StartLine)
+	id := cmp.Compare(a.ID, b.ID)
+	switch order {
+	case OrderByFile:
+		return cmp.Or(file, line, severity, id)
+	case OrderByLine:
+		return cmp.Or(line, file, severity, id)
+	default:
+		return cmp.Or(severity, file, line, id)
+	}
+}
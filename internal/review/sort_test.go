@@ -0,0 +1,42 @@
+package review
+
+import "testing"
+
+func TestSortComments_whenDefaultOrder_shouldSortBySeverityThenFileThenLine(t *testing.T) {
+	// arrange
+	comments := []Comment{
+		{ID: "1", FilePath: "b.go", StartLine: 3, Severity: SeverityNit},
+		{ID: "2", FilePath: "b.go", StartLine: 9, Severity: SeverityBlocker},
+		{ID: "3", FilePath: "a.go", StartLine: 5, Severity: SeverityBlocker},
+		{ID: "4", FilePath: "a.go", StartLine: 2, Severity: SeverityBlocker},
+		{ID: "5", FilePath: "c.go", StartLine: 1, Severity: SeverityIssue},
+	}
+
+	// act
+	SortComments(comments, OrderBySeverity)
+
+	// assert
+	want := []string{"4", "3", "2", "5", "1"}
+	for i, id := range want {
+		if comments[i].ID != id {
+			t.Fatalf("expected order %v, got %+v", want, comments)
+		}
+	}
+}
+
+func TestSeverityAtLeast_whenThresholdIsIssue_shouldIncludeBlockerAndIssueOnly(t *testing.T) {
+	// arrange
+	var got []Severity
+
+	// act
+	for _, sev := range Severities {
+		if sev.AtLeast(SeverityIssue) {
+			got = append(got, sev)
+		}
+	}
+
+	// assert
+	if len(got) != 2 || got[0] != SeverityBlocker || got[1] != SeverityIssue {
+		t.Fatalf("expected [BLOCKER ISSUE], got %v", got)
+	}
+}
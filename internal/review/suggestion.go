@@ -0,0 +1,144 @@
+package review
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/git"
+)
+
+// suggestionPatchContext is how many unchanged lines surround the replacement, as in git diff.
+const suggestionPatchContext = 3
+
+// SuggestionCode returns the replacement code in a comment's suggestion: the first fenced code
+// block, or the whole suggestion when it has no fence (reports and the TUI render it as code too).
+func SuggestionCode(comment Comment) (string, bool) {
+	if comment.Suggestion == nil {
+		return "", false
+	}
+	suggestion := strings.TrimSpace(*comment.Suggestion)
+	if suggestion == "" {
+		return "", false
+	}
+	start := strings.Index(suggestion, "```")
+	if start < 0 {
+		return suggestion, true
+	}
+	// Skip the info string (```go) on the opening fence line.
+	body := suggestion[start+3:]
+	newline := strings.Index(body, "\n")
+	if newline < 0 {
+		return "", false
+	}
+	body = body[newline+1:]
+	end := strings.Index(body, "```")
+	if end < 0 {
+		return "", false
+	}
+	code := strings.TrimSuffix(body[:end], "\n")
+	return code, code != ""
+}
+
+// SuggestionReplacement returns the suggested code when the suggestion is nothing but one fenced
+// code block, the form the prompt asks for when the code replaces the comment's whole line range.
+// Prose, several blocks (as left by merging overlapping comments) or an empty block return false,
+// since applying them in place of the lines could corrupt the file.
+func SuggestionReplacement(comment Comment) (string, bool) {
+	if comment.Suggestion == nil {
+		return "", false
+	}
+	lines := strings.Split(strings.TrimSpace(*comment.Suggestion), "\n")
+	if len(lines) < 3 || !strings.HasPrefix(lines[0], "```") || strings.TrimSpace(lines[len(lines)-1]) != "```" {
+		return "", false
+	}
+	body := lines[1 : len(lines)-1]
+	for _, line := range body {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			return "", false
+		}
+	}
+	code := strings.Join(body, "\n")
+	return code, strings.TrimSpace(code) != ""
+}
+
+// SuggestionPatch builds a unified diff that replaces the comment's lines (new-side numbers, as
+// reviewed) in content with its suggested code, for git apply. file is the comment's parsed diff:
+// the lines must fall inside one of its hunks, and the lines content has there must still match
+// the diff, so a patch is never built against a file that changed since the review.
+func SuggestionPatch(comment Comment, file git.DiffFile, content string) (string, error) {
+	code, ok := SuggestionCode(comment)
+	if !ok {
+		return "", errors.New("the comment has no suggested code")
+	}
+	if file.Deleted || file.Binary {
+		return "", fmt.Errorf("%s is not a text file in the reviewed version", file.Path)
+	}
+	start := comment.StartLine
+	end := max(comment.EndLine, start)
+	if start < 1 {
+		return "", errors.New("the comment has no line range")
+	}
+	if !insideHunk(file.Hunks, start, end) {
+		return "", fmt.Errorf("lines %d-%d of %s are outside the changed hunks", start, end, file.Path)
+	}
+
+	missingNewline := content != "" && !strings.HasSuffix(content, "\n")
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	if end > len(lines) {
+		return "", fmt.Errorf("%s has %d lines, the comment refers to %d-%d", file.Path, len(lines), start, end)
+	}
+	for _, hunk := range file.Hunks {
+		for _, line := range hunk.Lines {
+			if line.Kind == git.DiffLineDel || line.NewLine < 1 || line.NewLine > len(lines) {
+				continue
+			}
+			if lines[line.NewLine-1] != line.Text {
+				return "", fmt.Errorf("%s changed since it was reviewed (line %d differs)", file.Path, line.NewLine)
+			}
+		}
+	}
+
+	before := max(start-suggestionPatchContext, 1)
+	after := min(end+suggestionPatchContext, len(lines))
+	replacement := strings.Split(code, "\n")
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "diff --git a/%s b/%s\n--- a/%s\n+++ b/%s\n", file.Path, file.Path, file.Path, file.Path)
+	oldCount := after - before + 1
+	newCount := oldCount - (end - start + 1) + len(replacement)
+	fmt.Fprintf(&sb, "@@ -%d,%d +%d,%d @@\n", before, oldCount, before, newCount)
+	noNewline := func(line int) {
+		if missingNewline && line == len(lines) {
+			sb.WriteString("\\ No newline at end of file\n")
+		}
+	}
+	for line := before; line < start; line++ {
+		sb.WriteString(" " + lines[line-1] + "\n")
+	}
+	for line := start; line <= end; line++ {
+		sb.WriteString("-" + lines[line-1] + "\n")
+		noNewline(line)
+	}
+	for i, text := range replacement {
+		sb.WriteString("+" + text + "\n")
+		// The replacement keeps the file's missing final newline when it replaces the last line.
+		if i == len(replacement)-1 && after == end {
+			noNewline(end)
+		}
+	}
+	for line := end + 1; line <= after; line++ {
+		sb.WriteString(" " + lines[line-1] + "\n")
+		noNewline(line)
+	}
+	return sb.String(), nil
+}
+
+func insideHunk(hunks []git.DiffHunk, start, end int) bool {
+	for _, hunk := range hunks {
+		if start >= hunk.NewStart && end <= hunk.NewStart+hunk.NewLines-1 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package review
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/git"
+)
+
+func suggestionTestFile() (git.DiffFile, string) {
+	var lines []string
+	for i := 1; i <= 10; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	hunk := git.DiffHunk{NewStart: 4, NewLines: 3}
+	for i := 4; i <= 6; i++ {
+		hunk.Lines = append(hunk.Lines, git.DiffLine{Kind: git.DiffLineAdd, NewLine: i, Text: lines[i-1]})
+	}
+	return git.DiffFile{Path: "main.go", Hunks: []git.DiffHunk{hunk}}, strings.Join(lines, "\n") + "\n"
+}
+
+func TestSuggestionPatch_whenLinesInsideHunk_shouldReplaceThemWithTheCodeBlock(t *testing.T) {
+	// arrange
+	file, content := suggestionTestFile()
+	suggestion := "Use the fixed value:\n```go\nfixed 5\nfixed 5b\n```"
+	comment := Comment{FilePath: "main.go", StartLine: 5, EndLine: 5, Suggestion: &suggestion}
+
+	// act
+	patch, err := SuggestionPatch(comment, file, content)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := "diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n" +
+		"@@ -2,7 +2,8 @@\n line 2\n line 3\n line 4\n-line 5\n+fixed 5\n+fixed 5b\n line 6\n line 7\n line 8\n"
+	if patch != want {
+		t.Fatalf("expected patch:\n%s\ngot:\n%s", want, patch)
+	}
+}
+
+func TestSuggestionPatch_whenFileChangedSinceReview_shouldReturnError(t *testing.T) {
+	// arrange
+	file, content := suggestionTestFile()
+	content = strings.Replace(content, "line 4\n", "edited 4\n", 1)
+	suggestion := "fixed 5"
+	comment := Comment{FilePath: "main.go", StartLine: 5, Suggestion: &suggestion}
+
+	// act
+	_, err := SuggestionPatch(comment, file, content)
+
+	// assert
+	if err == nil || !strings.Contains(err.Error(), "changed since it was reviewed") {
+		t.Fatalf("expected a stale file error, got %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package review
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// SummaryTemplate replaces the built-in layout of the published summary comment. A nil
+// *SummaryTemplate renders RenderMarkdown.
+type SummaryTemplate struct {
+	tmpl *template.Template
+}
+
+// FileComments is one file's comments, as returned by the groupByFile template function.
+type FileComments struct {
+	Path     string
+	Comments []Comment
+}
+
+// summaryTemplateFuncs are the helpers a summary template can call besides the text/template
+// built-ins.
+var summaryTemplateFuncs = template.FuncMap{
+	// severityEmoji is the colored circle for a severity; severityBadge adds the bold name.
+	"severityEmoji": func(sev Severity) string {
+		emoji, _, _ := strings.Cut(severityBadge(sev), " ")
+		return emoji
+	},
+	"severityBadge": severityBadge,
+	// published keeps the comments selected for publishing.
+	"published": func(comments []Comment) []Comment {
+		var selected []Comment
+		for _, comment := range comments {
+			if comment.Publish {
+				selected = append(selected, comment)
+			}
+		}
+		return selected
+	},
+	"groupByFile": groupByFile,
+	// suggestion is the suggested code of a comment, empty when it has none.
+	"suggestion": func(comment Comment) string {
+		code, _ := SuggestionCode(comment)
+		return code
+	},
+}
+
+// groupByFile groups comments by file path, in the order each path first appears.
+func groupByFile(comments []Comment) []FileComments {
+	var groups []FileComments
+	index := map[string]int{}
+	for _, comment := range comments {
+		i, ok := index[comment.FilePath]
+		if !ok {
+			i = len(groups)
+			index[comment.FilePath] = i
+			groups = append(groups, FileComments{Path: comment.FilePath})
+		}
+		groups[i].Comments = append(groups[i].Comments, comment)
+	}
+	return groups
+}
+
+// LoadSummaryTemplate parses a text/template file for the summary comment. It is executed with
+// the review Result, e.g.
+//
+//	# {{.Verdict.Decision}} ({{.Verdict.Stats.Blocker}} blockers)
+//	{{range groupByFile (published .Comments)}}## {{.Path}}
+//	{{range .Comments}}- {{severityEmoji .Severity}} L{{.StartLine}} {{.Title}}
+//	{{end}}{{end}}
+func LoadSummaryTemplate(path string) (*SummaryTemplate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New(filepath.Base(path)).Funcs(summaryTemplateFuncs).Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("summary template %s: %w", path, err)
+	}
+	return &SummaryTemplate{tmpl: tmpl}, nil
+}
+
+// LoadRepoSummaryTemplate loads path relative to repoRoot; an empty path means the built-in layout.
+func LoadRepoSummaryTemplate(repoRoot, path string) (*SummaryTemplate, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, nil
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(repoRoot, path)
+	}
+	return LoadSummaryTemplate(path)
+}
+
+// Render renders res with the template, or with RenderMarkdown when t is nil.
+func (t *SummaryTemplate) Render(res Result) (string, error) {
+	if t == nil {
+		return RenderMarkdown(res), nil
+	}
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, res); err != nil {
+		return "", fmt.Errorf("summary template: %w", err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,44 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSummaryTemplate_whenLoadedFromRepo_shouldRenderWithHelpers(t *testing.T) {
+	// arrange
+	root := t.TempDir()
+	text := `{{.Verdict.Decision}}
+{{range groupByFile (published .Comments)}}## {{.Path}}
+{{range .Comments}}{{severityEmoji .Severity}} {{.Title}}
+{{end}}{{end}}Verify before merging.`
+	if err := os.WriteFile(filepath.Join(root, "summary.tmpl"), []byte(text), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	res := Result{
+		Verdict: Verdict{Decision: DecisionNoGo},
+		Comments: []Comment{
+			{FilePath: "a.go", Severity: SeverityBlocker, Title: "Nil deref", Publish: true},
+			{FilePath: "b.go", Severity: SeverityNit, Title: "Typo", Publish: true},
+			{FilePath: "a.go", Severity: SeverityIssue, Title: "Leak", Publish: true},
+			{FilePath: "c.go", Severity: SeverityIssue, Title: "Skipped", Publish: false},
+		},
+	}
+	tmpl, err := LoadRepoSummaryTemplate(root, "summary.tmpl")
+	if err != nil {
+		t.Fatalf("expected no error loading, got %v", err)
+	}
+
+	// act
+	got, err := tmpl.Render(res)
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := "NO_GO\n## a.go\n🔴 Nil deref\n🟠 Leak\n## b.go\n⚪ Typo\nVerify before merging."
+	if got != want {
+		t.Fatalf("expected:\n%s\ngot:\n%s", want, got)
+	}
+}
@@ -0,0 +1,139 @@
+package review
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/llm"
+)
+
+// Prompt template names a template file can define with {{define "..."}}. Each one replaces the
+// matching built-in prompt; anything left undefined keeps the default.
+const (
+	TemplateFileSystem    = "file-system"
+	TemplateFileUser      = "file-user"
+	TemplateVerdictSystem = "verdict-system"
+	TemplateVerdictUser   = "verdict-user"
+)
+
+// FilePromptData is what the file-system and file-user templates render.
+type FilePromptData struct {
+	Guidelines    string
+	LanguageFocus string
+	MinSeverity   Severity
+	Diff          string
+	FileContext   string
+	Schema        string
+}
+
+// VerdictPromptData is what the verdict-system and verdict-user templates render. Comments is the
+// one-line-per-comment summary the default prompt uses.
+type VerdictPromptData struct {
+	Guidelines   string
+	Comments     string
+	Stats        Stats
+	RuleDecision Decision
+	Schema       string
+}
+
+// PromptTemplates overrides some or all of the built-in prompts. A nil *PromptTemplates uses the
+// defaults.
+type PromptTemplates struct {
+	tmpl *template.Template
+	hash string
+}
+
+// LoadPromptTemplates parses a prompt template file. It must define at least one of the
+// Template* names, e.g.
+//
+//	{{define "file-system"}}You review Go services. Answer in JSON only.{{end}}
+func LoadPromptTemplates(path string) (*PromptTemplates, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New("prompts").Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("prompt template %s: %w", path, err)
+	}
+	found := false
+	for _, name := range []string{TemplateFileSystem, TemplateFileUser, TemplateVerdictSystem, TemplateVerdictUser} {
+		if tmpl.Lookup(name) != nil {
+			found = true
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("prompt template %s defines none of %q, %q, %q or %q", path,
+			TemplateFileSystem, TemplateFileUser, TemplateVerdictSystem, TemplateVerdictUser)
+	}
+	sum := sha256.Sum256(data)
+	return &PromptTemplates{tmpl: tmpl, hash: hex.EncodeToString(sum[:])}, nil
+}
+
+// LoadRepoPromptTemplates loads path relative to repoRoot; an empty path means the built-in prompts.
+func LoadRepoPromptTemplates(repoRoot, path string) (*PromptTemplates, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, nil
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(repoRoot, path)
+	}
+	return LoadPromptTemplates(path)
+}
+
+// Hash identifies the template content, so cached reviews made with other prompts are not reused.
+func (t *PromptTemplates) Hash() string {
+	if t == nil {
+		return ""
+	}
+	return t.hash
+}
+
+func (t *PromptTemplates) fileReviewMessages(guidelines, languageFocus string, minSeverity Severity, diff, fileContext string) ([]llm.Message, error) {
+	messages := BuildFileReviewMessages(guidelines, languageFocus, minSeverity, diff, fileContext)
+	data := FilePromptData{
+		Guidelines:    guidelines,
+		LanguageFocus: languageFocus,
+		MinSeverity:   minSeverity,
+		Diff:          diff,
+		FileContext:   fileContext,
+		Schema:        fileReviewSchema,
+	}
+	return messages, t.override(messages, TemplateFileSystem, TemplateFileUser, data)
+}
+
+func (t *PromptTemplates) verdictMessages(guidelines string, comments []Comment, stats Stats, ruleDecision Decision) ([]llm.Message, error) {
+	messages := BuildVerdictMessages(guidelines, comments, stats, ruleDecision)
+	data := VerdictPromptData{
+		Guidelines:   guidelines,
+		Comments:     verdictCommentSummary(comments),
+		Stats:        stats,
+		RuleDecision: ruleDecision,
+		Schema:       verdictSchema,
+	}
+	return messages, t.override(messages, TemplateVerdictSystem, TemplateVerdictUser, data)
+}
+
+// override replaces the system and user message contents with the named templates, when defined.
+func (t *PromptTemplates) override(messages []llm.Message, systemName, userName string, data any) error {
+	if t == nil {
+		return nil
+	}
+	for i, name := range []string{systemName, userName} {
+		if t.tmpl.Lookup(name) == nil {
+			continue
+		}
+		var buf bytes.Buffer
+		if err := t.tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+			return fmt.Errorf("prompt template: %w", err)
+		}
+		messages[i].Content = strings.TrimSpace(buf.String())
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package review
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadPromptTemplates_whenFileSystemDefined_shouldOverrideOnlyThatPrompt(t *testing.T) {
+	// arrange
+	path := filepath.Join(t.TempDir(), "prompts.tmpl")
+	content := `{{define "file-system"}}Answer in French. Guidelines: {{.Guidelines}}{{end}}`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// act
+	prompts, err := LoadPromptTemplates(path)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	messages, err := prompts.fileReviewMessages("be kind", "", SeverityNit, "@@ -1 +1 @@", "")
+
+	// assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if messages[0].Content != "Answer in French. Guidelines: be kind" {
+		t.Fatalf("expected the system prompt from the template, got %q", messages[0].Content)
+	}
+	if !strings.Contains(messages[1].Content, "Schema:") {
+		t.Fatalf("expected the built-in user prompt, got %q", messages[1].Content)
+	}
+	if prompts.Hash() == "" {
+		t.Fatal("expected a content hash")
+	}
+}
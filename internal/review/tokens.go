@@ -0,0 +1,38 @@
+package review
+
+import "github.com/techitung-arunyawee/code-reviewer-2/internal/git"
+
+// DefaultTokenWarningThreshold is the estimated prompt size above which the TUI asks before
+// starting a review.
+const DefaultTokenWarningThreshold = 100_000
+
+// charsPerToken is the usual rough ratio for English text and code.
+const charsPerToken = 4
+
+// EstimateTokens roughly sizes the prompts a review of files would send: every reviewed file's
+// rendered diff plus the guidelines, which each file request repeats. Files Run skips (binary, no
+// hunks) are not counted; per-file hints, surrounding context and the verdict request are left
+// out, so the real figure is somewhat higher.
+func EstimateTokens(files []git.DiffFile, guidelines string) int {
+	chars := 0
+	for _, file := range files {
+		if file.Binary || (len(file.Hunks) == 0 && !file.ModeChanged()) {
+			continue
+		}
+		chars += len(RenderUnifiedDiffFile(file)) + len(guidelines)
+	}
+	return chars / charsPerToken
+}
+
+// TokenWarningThreshold resolves a configured threshold: zero means the default and a negative
+// value disables the warning, reported as 0.
+func TokenWarningThreshold(configured int) int {
+	switch {
+	case configured < 0:
+		return 0
+	case configured == 0:
+		return DefaultTokenWarningThreshold
+	default:
+		return configured
+	}
+}
@@ -0,0 +1,23 @@
+package review
+
+import (
+	"testing"
+
+	"github.com/techitung-arunyawee/code-reviewer-2/internal/git"
+)
+
+func TestEstimateTokens_whenFilesIncludeBinary_shouldCountReviewedDiffsAndGuidelinesPerFile(t *testing.T) {
+	// arrange
+	hunk := []git.DiffHunk{{OldStart: 1, OldLines: 1, NewStart: 1, NewLines: 1, Lines: []git.DiffLine{{Kind: git.DiffLineAdd, Text: "x", NewLine: 1}}}}
+	files := []git.DiffFile{{Path: "a.go", Hunks: hunk}, {Path: "b.go", Hunks: hunk}, {Path: "logo.png", Binary: true}}
+	guidelines := "0123456789abcdef0123456789abcdef0123456789"
+	perFile := len(RenderUnifiedDiffFile(files[0])) + len(guidelines)
+
+	// act
+	tokens := EstimateTokens(files, guidelines)
+
+	// assert
+	if tokens != 2*perFile/4 {
+		t.Fatalf("expected %d tokens, got %d", 2*perFile/4, tokens)
+	}
+}
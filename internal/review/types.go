@@ -3,7 +3,9 @@ package review
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 )
@@ -17,6 +19,24 @@ const (
 	SeverityBlocker    Severity = "BLOCKER"
 )
 
+// Severities lists every severity from most to least severe. It is the single source of severity
+// ordering: sorting, publish thresholds and the TUI's filter cycle all follow it.
+var Severities = []Severity{SeverityBlocker, SeverityIssue, SeveritySuggestion, SeverityNit}
+
+// SeverityRank is the position of sev in Severities, 0 being the most severe. Unknown severities
+// rank as NIT, matching NormalizeSeverity.
+func SeverityRank(sev Severity) int {
+	if rank := slices.Index(Severities, sev); rank >= 0 {
+		return rank
+	}
+	return len(Severities) - 1
+}
+
+// AtLeast reports whether s is as severe as threshold or more.
+func (s Severity) AtLeast(threshold Severity) bool {
+	return SeverityRank(s) <= SeverityRank(threshold)
+}
+
 type Decision string
 
 const (
@@ -35,7 +55,10 @@ type Comment struct {
 	Suggestion *string
 	Evidence   *string
 	Tags       []string
-	Publish    bool
+	// Rule is the guideline line the comment enforces, as cited by the model; empty means the
+	// finding is the model's own judgment.
+	Rule    string
+	Publish bool
 }
 
 type Verdict struct {
@@ -43,6 +66,11 @@ type Verdict struct {
 	Summary   string
 	Rationale []string
 	Stats     Stats
+	// Manual is set when a reviewer overrode the automated decision.
+	Manual bool
+	// AutoDecision is the automated decision before any override. Empty means Decision has not been
+	// overridden since the review ran.
+	AutoDecision Decision
 }
 
 type Stats struct {
@@ -58,8 +86,12 @@ type Result struct {
 	Model         string
 	GuidelineHash string
 	Dropped       int
-	FileErrors    map[string]string
-	GeneratedAt   time.Time
+	Dismissed     int // comments the user removed as false positives
+	// FileErrors maps each file that could not be reviewed to its error message. Messages rather
+	// than error values are kept because results are cached and exported as JSON, where errors
+	// do not round-trip.
+	FileErrors  map[string]string
+	GeneratedAt time.Time
 }
 
 func ComputeStats(comments []Comment) Stats {
@@ -96,6 +128,29 @@ func StableCommentID(comment Comment) string {
 	return hex.EncodeToString(hasher.Sum(nil))
 }
 
+// PublishSelections records each comment's Publish choice by ID, so it can be restored on a
+// re-run with RestorePublishSelections.
+func PublishSelections(comments []Comment) map[string]bool {
+	selections := make(map[string]bool, len(comments))
+	for _, comment := range comments {
+		selections[comment.ID] = comment.Publish
+	}
+	return selections
+}
+
+// RestorePublishSelections reapplies prior choices to the comments with a matching ID and reports
+// how many matched; the others keep the Publish they were parsed with.
+func RestorePublishSelections(comments []Comment, selections map[string]bool) int {
+	restored := 0
+	for i := range comments {
+		if publish, ok := selections[comments[i].ID]; ok {
+			comments[i].Publish = publish
+			restored++
+		}
+	}
+	return restored
+}
+
 func NormalizeDecision(value string) Decision {
 	switch strings.ToUpper(strings.TrimSpace(value)) {
 	case "NO_GO", "NO-GO", "NOGO":
@@ -105,6 +160,45 @@ func NormalizeDecision(value string) Decision {
 	}
 }
 
+// OverrideDecision flips the verdict between GO and NO_GO. It is a manual call only while the
+// decision differs from the automated one, so flipping twice clears Manual again.
+// When enforceFloor is set, a verdict with blockers cannot be flipped to GO.
+func OverrideDecision(verdict Verdict, enforceFloor bool) (Verdict, error) {
+	next := oppositeDecision(verdict.Decision)
+	if next == DecisionGo && enforceFloor && verdict.Stats.Blocker > 0 {
+		return verdict, errors.New("cannot override to GO while blockers exist")
+	}
+	if verdict.AutoDecision == "" {
+		verdict.AutoDecision = verdict.Decision
+		if verdict.Manual {
+			verdict.AutoDecision = next
+		}
+	}
+	verdict.Decision = next
+	verdict.Manual = verdict.Decision != verdict.AutoDecision
+	return verdict, nil
+}
+
+func oppositeDecision(decision Decision) Decision {
+	if decision == DecisionNoGo {
+		return DecisionGo
+	}
+	return DecisionNoGo
+}
+
+// ParseMinSeverity reads a severity floor from user input. Empty means NIT (no floor); unlike
+// NormalizeSeverity, unknown values are an error.
+func ParseMinSeverity(value string) (Severity, error) {
+	if strings.TrimSpace(value) == "" {
+		return SeverityNit, nil
+	}
+	severity := Severity(strings.ToUpper(strings.TrimSpace(value)))
+	if !slices.Contains(Severities, severity) {
+		return "", fmt.Errorf("unknown severity %q (want BLOCKER, ISSUE, SUGGESTION or NIT)", value)
+	}
+	return severity, nil
+}
+
 func NormalizeSeverity(value string) Severity {
 	switch strings.ToUpper(strings.TrimSpace(value)) {
 	case "BLOCKER":
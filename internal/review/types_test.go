@@ -0,0 +1,60 @@
+package review
+
+import "testing"
+
+func TestRestorePublishSelections_whenRerunKeepsSomeComments_shouldReapplyOnlyMatchingChoices(t *testing.T) {
+	// arrange
+	prior := []Comment{{ID: "kept", Publish: false}, {ID: "gone", Publish: false}}
+	comments := []Comment{{ID: "kept", Publish: true}, {ID: "new", Publish: true}}
+
+	// act
+	restored := RestorePublishSelections(comments, PublishSelections(prior))
+
+	// assert
+	if restored != 1 {
+		t.Fatalf("expected 1 restored selection, got %d", restored)
+	}
+	if comments[0].Publish || !comments[1].Publish {
+		t.Fatalf("expected kept unselected and new selected, got %+v", comments)
+	}
+}
+
+func TestOverrideDecision_whenToggledOnceThenTwice_shouldClearManualOnReturn(t *testing.T) {
+	// arrange
+	verdict := Verdict{Decision: DecisionGo}
+
+	// act
+	once, err := OverrideDecision(verdict, true)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	twice, err := OverrideDecision(once, true)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// assert
+	if once.Decision != DecisionNoGo || !once.Manual {
+		t.Fatalf("expected manual NO_GO after one toggle, got %+v", once)
+	}
+	if twice.Decision != DecisionGo || twice.Manual {
+		t.Fatalf("expected automated GO after two toggles, got %+v", twice)
+	}
+}
+
+func TestOverrideDecision_whenBlockersExist_shouldRespectFloorSetting(t *testing.T) {
+	// arrange
+	verdict := Verdict{Decision: DecisionNoGo, Stats: Stats{Blocker: 1}}
+
+	// act
+	floored, floorErr := OverrideDecision(verdict, true)
+	overridden, err := OverrideDecision(verdict, false)
+
+	// assert
+	if floorErr == nil || floored.Decision != DecisionNoGo || floored.Manual {
+		t.Fatalf("expected floor to keep NO_GO with an error, got %+v, %v", floored, floorErr)
+	}
+	if err != nil || overridden.Decision != DecisionGo || !overridden.Manual {
+		t.Fatalf("expected manual GO without floor, got %+v, %v", overridden, err)
+	}
+}